@@ -0,0 +1,355 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/Eppo-exp/golang-sdk/v6/eppoclient"
+)
+
+const adminPrefix = "/admin/"
+
+// maxAdminBody limits the size of configurations uploaded through
+// the admin API.
+const maxAdminBody = 64 << 20
+
+const (
+	fixedVariationKey  = "fixed"
+	fixedAllocationKey = "fixed-value"
+	defaultTotalShards = 10000
+)
+
+// fixedValue is a value served to every subject, replacing the
+// variations and allocations of a flag.
+type fixedValue struct {
+	variationType eppoclient.VariationType
+	// value is the encoded variation value.
+	value json.RawMessage
+}
+
+// apply returns `flag` modified to serve the fixed value. `flag` may
+// be nil if the flag does not exist in the configuration.
+func (f fixedValue) apply(key string, flag map[string]json.RawMessage) map[string]json.RawMessage {
+	modified := make(map[string]json.RawMessage, len(flag)+5)
+	for field, value := range flag {
+		modified[field] = value
+	}
+	if _, ok := modified["totalShards"]; !ok {
+		modified["totalShards"] = json.RawMessage(fmt.Sprint(defaultTotalShards))
+	}
+	modified["key"] = mustMarshal(key)
+	modified["enabled"] = json.RawMessage("true")
+	modified["variationType"] = mustMarshal(f.variationType)
+	modified["variations"] = mustMarshal(map[string]interface{}{
+		fixedVariationKey: map[string]interface{}{"key": fixedVariationKey, "value": f.value},
+	})
+	modified["allocations"] = mustMarshal([]interface{}{map[string]interface{}{
+		"key":   fixedAllocationKey,
+		"rules": []interface{}{},
+		"splits": []interface{}{map[string]interface{}{
+			"variationKey": fixedVariationKey,
+			"shards":       []interface{}{},
+		}},
+		"doLog": true,
+	}})
+	return modified
+}
+
+// encodeFixedValue validates `value` against the variation type and
+// encodes it as served in the configuration. JSON values are served
+// as strings containing JSON.
+func encodeFixedValue(vt eppoclient.VariationType, value json.RawMessage) (json.RawMessage, error) {
+	switch vt {
+	case eppoclient.VariationTypeBoolean:
+		var v bool
+		if err := json.Unmarshal(value, &v); err != nil {
+			return nil, fmt.Errorf("value is not a boolean")
+		}
+	case eppoclient.VariationTypeInteger:
+		var v float64
+		if err := json.Unmarshal(value, &v); err != nil || v != math.Trunc(v) {
+			return nil, fmt.Errorf("value is not an integer")
+		}
+	case eppoclient.VariationTypeNumeric:
+		var v float64
+		if err := json.Unmarshal(value, &v); err != nil {
+			return nil, fmt.Errorf("value is not a number")
+		}
+	case eppoclient.VariationTypeString:
+		var v string
+		if err := json.Unmarshal(value, &v); err != nil {
+			return nil, fmt.Errorf("value is not a string")
+		}
+	case eppoclient.VariationTypeJSON:
+		if !json.Valid(value) {
+			return nil, fmt.Errorf("value is not valid JSON")
+		}
+		return mustMarshal(string(value)), nil
+	default:
+		return nil, fmt.Errorf("unknown variation type %q", vt)
+	}
+	return value, nil
+}
+
+func mustMarshal(value interface{}) json.RawMessage {
+	b, err := json.Marshal(value)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+// flagStatus describes a flag in responses of the admin API.
+type flagStatus struct {
+	Key           string                   `json:"key"`
+	Enabled       bool                     `json:"enabled"`
+	VariationType eppoclient.VariationType `json:"variationType"`
+	// FixedValue is the value set through the admin API, if any.
+	FixedValue json.RawMessage `json:"fixedValue,omitempty"`
+}
+
+// serveAdmin handles the admin API:
+//
+//	GET    /admin/flags               list flags
+//	POST   /admin/flags/{key}/enable  enable a flag
+//	POST   /admin/flags/{key}/disable disable a flag
+//	PUT    /admin/flags/{key}/value   serve a fixed value to everyone
+//	DELETE /admin/flags/{key}/value   restore configured allocations
+//	PUT    /admin/config              replace the flags configuration
+//	PUT    /admin/bandits             replace the bandits configuration
+//	POST   /admin/reload              re-read configuration files
+//	POST   /admin/reset               drop all flag modifications
+func (s *server) serveAdmin(w http.ResponseWriter, r *http.Request) {
+	if s.adminToken != "" && bearerToken(r) != s.adminToken {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	path := strings.TrimPrefix(r.URL.Path, adminPrefix)
+	switch {
+	case path == "flags":
+		s.adminRoute(w, r, http.MethodGet, s.listFlags)
+	case path == "config":
+		s.adminRoute(w, r, http.MethodPut, s.putConfig)
+	case path == "bandits":
+		s.adminRoute(w, r, http.MethodPut, s.putBandits)
+	case path == "reload":
+		s.adminRoute(w, r, http.MethodPost, s.reloadConfig)
+	case path == "reset":
+		s.adminRoute(w, r, http.MethodPost, s.resetFlags)
+	case strings.HasPrefix(path, "flags/"):
+		s.serveAdminFlag(w, r, strings.TrimPrefix(path, "flags/"))
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (s *server) serveAdminFlag(w http.ResponseWriter, r *http.Request, path string) {
+	i := strings.LastIndex(path, "/")
+	if i <= 0 {
+		http.NotFound(w, r)
+		return
+	}
+	key, action := path[:i], path[i+1:]
+	switch action {
+	case "enable":
+		s.adminRoute(w, r, http.MethodPost, func(w http.ResponseWriter, r *http.Request) {
+			s.setEnabled(w, key, true)
+		})
+	case "disable":
+		s.adminRoute(w, r, http.MethodPost, func(w http.ResponseWriter, r *http.Request) {
+			s.setEnabled(w, key, false)
+		})
+	case "value":
+		switch r.Method {
+		case http.MethodPut:
+			s.setFixedValue(w, r, key)
+		case http.MethodDelete:
+			s.clearFixedValue(w, key)
+		default:
+			w.Header().Set("Allow", "PUT, DELETE")
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		}
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (s *server) adminRoute(w http.ResponseWriter, r *http.Request, method string, handler http.HandlerFunc) {
+	if r.Method != method {
+		w.Header().Set("Allow", method)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	handler(w, r)
+}
+
+func (s *server) listFlags(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var doc struct {
+		Flags map[string]struct {
+			Enabled       bool                     `json:"enabled"`
+			VariationType eppoclient.VariationType `json:"variationType"`
+		} `json:"flags"`
+	}
+	if err := json.Unmarshal(s.servedFlags.body, &doc); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	flags := make([]flagStatus, 0, len(doc.Flags))
+	for key, flag := range doc.Flags {
+		status := flagStatus{Key: key, Enabled: flag.Enabled, VariationType: flag.VariationType}
+		if fixed, ok := s.fixed[key]; ok {
+			status.FixedValue = fixed.value
+		}
+		flags = append(flags, status)
+	}
+	sort.Slice(flags, func(i, j int) bool { return flags[i].Key < flags[j].Key })
+	writeJSON(w, http.StatusOK, flags)
+}
+
+func (s *server) setEnabled(w http.ResponseWriter, key string, enabled bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.flags.obfuscated() {
+		http.Error(w, "flags of obfuscated configurations cannot be modified", http.StatusBadRequest)
+		return
+	}
+	if _, ok := s.flags.flags[key]; !ok {
+		if _, ok := s.fixed[key]; !ok {
+			http.Error(w, fmt.Sprintf("flag %q not found", key), http.StatusNotFound)
+			return
+		}
+	}
+	s.enabled[key] = enabled
+	s.commitLocked(w, "flag %q enabled=%v", key, enabled)
+}
+
+func (s *server) setFixedValue(w http.ResponseWriter, r *http.Request, key string) {
+	var request struct {
+		Value json.RawMessage `json:"value"`
+		// VariationType is required for flags missing from the
+		// configuration, which are created.
+		VariationType eppoclient.VariationType `json:"variationType"`
+	}
+	if err := json.NewDecoder(io.LimitReader(r.Body, maxAdminBody)).Decode(&request); err != nil {
+		http.Error(w, fmt.Sprintf("invalid request: %v", err), http.StatusBadRequest)
+		return
+	}
+	if request.Value == nil {
+		http.Error(w, "invalid request: missing value", http.StatusBadRequest)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.flags.obfuscated() {
+		http.Error(w, "flags of obfuscated configurations cannot be modified", http.StatusBadRequest)
+		return
+	}
+	if vt, ok := s.flags.variationType(key); ok {
+		if request.VariationType != "" && request.VariationType != vt {
+			http.Error(w, fmt.Sprintf("flag %q has variation type %s", key, vt), http.StatusBadRequest)
+			return
+		}
+		request.VariationType = vt
+	} else if request.VariationType == "" {
+		http.Error(w, fmt.Sprintf("flag %q not found; variationType is required to create it", key), http.StatusBadRequest)
+		return
+	}
+	value, err := encodeFixedValue(request.VariationType, request.Value)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	s.fixed[key] = fixedValue{variationType: request.VariationType, value: value}
+	s.commitLocked(w, "flag %q fixed to %s", key, request.Value)
+}
+
+func (s *server) clearFixedValue(w http.ResponseWriter, key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.fixed[key]; !ok {
+		http.Error(w, fmt.Sprintf("flag %q has no fixed value", key), http.StatusNotFound)
+		return
+	}
+	delete(s.fixed, key)
+	if _, ok := s.flags.flags[key]; !ok {
+		delete(s.enabled, key)
+	}
+	s.commitLocked(w, "flag %q fixed value cleared", key)
+}
+
+func (s *server) putConfig(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxAdminBody))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	flags, err := parseFlagsDocument(body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.flags = flags
+	s.commitLocked(w, "flags configuration replaced")
+}
+
+func (s *server) putBandits(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxAdminBody))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if !json.Valid(body) {
+		http.Error(w, "invalid bandits configuration", http.StatusBadRequest)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.banditsJSON = body
+	s.commitLocked(w, "bandits configuration replaced")
+}
+
+func (s *server) reloadConfig(w http.ResponseWriter, r *http.Request) {
+	if err := s.reload(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	s.logger.Printf("configuration reloaded from %s", s.dir)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *server) resetFlags(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.enabled = map[string]bool{}
+	s.fixed = map[string]fixedValue{}
+	s.commitLocked(w, "flag modifications reset")
+}
+
+// commitLocked renders the modified configuration and responds to an
+// admin request. Must be called with s.mu held.
+func (s *server) commitLocked(w http.ResponseWriter, format string, args ...interface{}) {
+	if err := s.renderLocked(); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	s.logger.Printf(format, args...)
+	w.WriteHeader(http.StatusNoContent)
+}
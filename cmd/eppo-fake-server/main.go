@@ -0,0 +1,57 @@
+// Command eppo-fake-server serves Eppo flag and bandit configurations
+// from a local directory, for integration and end-to-end environments
+// without access to Eppo.
+//
+// The directory must contain flags.json, as served at
+// eppoclient.CONFIG_ENDPOINT, and may contain bandits.json, as served
+// at eppoclient.BANDIT_ENDPOINT. Point clients at the server with
+// Config.BaseUrl:
+//
+//	eppo-fake-server -dir ./testdata/eppo -addr :8080 -sdk-keys test-key
+//
+//	client, err := eppoclient.InitClient(eppoclient.Config{
+//		SdkKey:         "test-key",
+//		BaseUrl:        "http://localhost:8080",
+//		PollerInterval: time.Second,
+//	})
+//
+// Flags can be changed at runtime through the admin API under /admin/,
+// for example:
+//
+//	curl -X POST localhost:8080/admin/flags/new-checkout/disable
+//	curl -X PUT localhost:8080/admin/flags/new-checkout/value -d '{"value": true}'
+//	curl -X PUT localhost:8080/admin/config --data-binary @flags.json
+//	curl -X POST localhost:8080/admin/reset
+package main
+
+import (
+	"flag"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+func main() {
+	addr := flag.String("addr", ":8080", "address to listen on")
+	dir := flag.String("dir", ".", "directory containing flags.json and optionally bandits.json")
+	sdkKeys := flag.String("sdk-keys", "", "comma-separated list of accepted SDK keys; any non-empty key is accepted if empty")
+	adminToken := flag.String("admin-token", "", "bearer token required by the admin API; the admin API is unauthenticated if empty")
+	flag.Parse()
+
+	logger := log.New(os.Stderr, "eppo-fake-server: ", log.LstdFlags)
+
+	s, err := newServer(*dir, strings.Split(*sdkKeys, ","), *adminToken, logger)
+	if err != nil {
+		logger.Fatalf("failed to load configuration: %v", err)
+	}
+
+	httpServer := &http.Server{
+		Addr:              *addr,
+		Handler:           s,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	logger.Printf("serving %s on %s", *dir, *addr)
+	logger.Fatal(httpServer.ListenAndServe())
+}
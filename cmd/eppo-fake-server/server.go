@@ -0,0 +1,267 @@
+package main
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/Eppo-exp/golang-sdk/v6/eppoclient"
+)
+
+const (
+	flagsFileName   = "flags.json"
+	banditsFileName = "bandits.json"
+
+	// apiPrefix is the path prefix of Eppo's default base URL. Config
+	// endpoints are served both with and without it, so clients work
+	// with BaseUrl set to either "http://host:port" or
+	// "http://host:port/api".
+	apiPrefix = "/api"
+)
+
+var emptyBandits = []byte(`{"bandits":{}}`)
+
+// server serves flag and bandit configurations loaded from a
+// directory and modified through the admin API.
+type server struct {
+	dir        string
+	sdkKeys    map[string]bool
+	adminToken string
+	logger     *log.Logger
+
+	mu sync.Mutex
+	// flags is the configuration as loaded or uploaded, without admin
+	// modifications.
+	flags       *flagsDocument
+	banditsJSON []byte
+	// enabled holds flags enabled or disabled through the admin API.
+	enabled map[string]bool
+	// fixed holds flags set to a fixed value through the admin API.
+	fixed map[string]fixedValue
+
+	// servedFlags and servedBandits are rendered on every change.
+	servedFlags   servedResource
+	servedBandits servedResource
+}
+
+type servedResource struct {
+	body []byte
+	eTag string
+}
+
+func newServedResource(body []byte) servedResource {
+	sum := sha256.Sum256(body)
+	return servedResource{body: body, eTag: `"` + hex.EncodeToString(sum[:16]) + `"`}
+}
+
+// flagsDocument is a flags configuration decoded just enough to
+// modify individual flags while preserving everything else.
+type flagsDocument struct {
+	fields map[string]json.RawMessage
+	flags  map[string]map[string]json.RawMessage
+}
+
+func parseFlagsDocument(data []byte) (*flagsDocument, error) {
+	doc := &flagsDocument{}
+	if err := json.Unmarshal(data, &doc.fields); err != nil {
+		return nil, fmt.Errorf("invalid flags configuration: %w", err)
+	}
+	if err := json.Unmarshal(doc.fields["flags"], &doc.flags); err != nil {
+		return nil, fmt.Errorf("invalid flags configuration: flags: %w", err)
+	}
+	if doc.flags == nil {
+		return nil, errors.New("invalid flags configuration: missing flags")
+	}
+	return doc, nil
+}
+
+// obfuscated reports whether flag keys and values are obfuscated, in
+// which case flags cannot be modified by key.
+func (doc *flagsDocument) obfuscated() bool {
+	var format string
+	_ = json.Unmarshal(doc.fields["format"], &format)
+	return format == "CLIENT"
+}
+
+func (doc *flagsDocument) variationType(flagKey string) (eppoclient.VariationType, bool) {
+	flag, ok := doc.flags[flagKey]
+	if !ok {
+		return "", false
+	}
+	var vt eppoclient.VariationType
+	_ = json.Unmarshal(flag["variationType"], &vt)
+	return vt, true
+}
+
+func newServer(dir string, sdkKeys []string, adminToken string, logger *log.Logger) (*server, error) {
+	s := &server{
+		dir:        dir,
+		sdkKeys:    map[string]bool{},
+		adminToken: adminToken,
+		logger:     logger,
+		enabled:    map[string]bool{},
+		fixed:      map[string]fixedValue{},
+	}
+	for _, key := range sdkKeys {
+		if key != "" {
+			s.sdkKeys[key] = true
+		}
+	}
+	if err := s.reload(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// reload reads configuration files from the directory. Admin
+// modifications are kept.
+func (s *server) reload() error {
+	flagsJSON, err := os.ReadFile(filepath.Join(s.dir, flagsFileName))
+	if err != nil {
+		return err
+	}
+	flags, err := parseFlagsDocument(flagsJSON)
+	if err != nil {
+		return fmt.Errorf("%s: %w", flagsFileName, err)
+	}
+
+	banditsJSON, err := os.ReadFile(filepath.Join(s.dir, banditsFileName))
+	if errors.Is(err, os.ErrNotExist) {
+		banditsJSON = emptyBandits
+	} else if err != nil {
+		return err
+	} else if !json.Valid(banditsJSON) {
+		return fmt.Errorf("%s: invalid JSON", banditsFileName)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.flags = flags
+	s.banditsJSON = banditsJSON
+	return s.renderLocked()
+}
+
+// renderLocked applies admin modifications to the configuration and
+// updates the served resources. Must be called with s.mu held.
+func (s *server) renderLocked() error {
+	flags := make(map[string]map[string]json.RawMessage, len(s.flags.flags))
+	for key, flag := range s.flags.flags {
+		flags[key] = flag
+	}
+	for key, value := range s.fixed {
+		flags[key] = value.apply(key, flags[key])
+	}
+	for key, enabled := range s.enabled {
+		flag, ok := flags[key]
+		if !ok {
+			continue
+		}
+		modified := make(map[string]json.RawMessage, len(flag))
+		for field, value := range flag {
+			modified[field] = value
+		}
+		modified["enabled"] = json.RawMessage(fmt.Sprint(enabled))
+		flags[key] = modified
+	}
+
+	flagsJSON, err := json.Marshal(flags)
+	if err != nil {
+		return err
+	}
+	fields := make(map[string]json.RawMessage, len(s.flags.fields))
+	for field, value := range s.flags.fields {
+		fields[field] = value
+	}
+	fields["flags"] = flagsJSON
+	body, err := json.Marshal(fields)
+	if err != nil {
+		return err
+	}
+
+	s.servedFlags = newServedResource(body)
+	s.servedBandits = newServedResource(s.banditsJSON)
+	return nil
+}
+
+func (s *server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimPrefix(r.URL.Path, apiPrefix)
+	switch {
+	case path == eppoclient.CONFIG_ENDPOINT:
+		s.serveResource(w, r, func() servedResource { return s.servedFlags })
+	case path == eppoclient.BANDIT_ENDPOINT:
+		s.serveResource(w, r, func() servedResource { return s.servedBandits })
+	case strings.HasPrefix(r.URL.Path, adminPrefix):
+		s.serveAdmin(w, r)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (s *server) serveResource(w http.ResponseWriter, r *http.Request, get func() servedResource) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if !s.authorized(r) {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	s.mu.Lock()
+	resource := get()
+	s.mu.Unlock()
+
+	w.Header().Set("ETag", resource.eTag)
+	if r.Header.Get("If-None-Match") == resource.eTag {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if r.Method == http.MethodGet {
+		_, _ = w.Write(resource.body)
+	}
+}
+
+// authorized checks the SDK key passed in the apiKey query parameter
+// or as a bearer token. If no SDK keys are configured, any non-empty
+// key is accepted.
+func (s *server) authorized(r *http.Request) bool {
+	key := r.URL.Query().Get("apiKey")
+	if key == "" {
+		key = bearerToken(r)
+	}
+	if key == "" {
+		return false
+	}
+	return len(s.sdkKeys) == 0 || s.sdkKeys[key]
+}
+
+func bearerToken(r *http.Request) string {
+	token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if len(token) == len(r.Header.Get("Authorization")) {
+		return ""
+	}
+	return token
+}
+
+func writeJSON(w http.ResponseWriter, status int, value interface{}) {
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(value); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_, _ = w.Write(buf.Bytes())
+}
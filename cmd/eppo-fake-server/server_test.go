@@ -0,0 +1,251 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Eppo-exp/golang-sdk/v6/configbuilder"
+	"github.com/Eppo-exp/golang-sdk/v6/eppoclient"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+func testFlagsJSON(t *testing.T, value string) []byte {
+	b := configbuilder.New()
+	b.Flag("flag", eppoclient.VariationTypeString).
+		Variation("value", value).
+		Allocation(configbuilder.NewAllocation("all").Serve("value"))
+	b.Flag("number", eppoclient.VariationTypeInteger).
+		Variation("one", 1).
+		Allocation(configbuilder.NewAllocation("all").Serve("one"))
+	flagsJSON, err := b.FlagsJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return flagsJSON
+}
+
+func newTestServer(t *testing.T, sdkKeys []string, adminToken string) (*server, *httptest.Server) {
+	dir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, flagsFileName), testFlagsJSON(t, "a"), 0o600))
+	s, err := newServer(dir, sdkKeys, adminToken, log.New(io.Discard, "", 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	httpServer := httptest.NewServer(s)
+	t.Cleanup(httpServer.Close)
+	return s, httpServer
+}
+
+func request(t *testing.T, method, url, body string, header http.Header) *http.Response {
+	req, err := http.NewRequest(method, url, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for key, values := range header {
+		req.Header[key] = values
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { resp.Body.Close() })
+	return resp
+}
+
+func Test_server_clientPicksUpChanges(t *testing.T) {
+	_, httpServer := newTestServer(t, []string{"test-key"}, "")
+
+	client, err := eppoclient.InitClient(eppoclient.Config{
+		SdkKey:            "test-key",
+		BaseUrl:           httpServer.URL + apiPrefix,
+		PollerInterval:    10 * time.Millisecond,
+		ApplicationLogger: eppoclient.NewZapLogger(zap.NewNop()),
+	})
+	assert.NoError(t, err)
+	t.Cleanup(func() { _ = client.Close(context.Background()) })
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := client.WaitForInitialization(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	getFlag := func() (string, error) {
+		return client.GetStringAssignment("flag", "subject", eppoclient.Attributes{}, "default")
+	}
+	value, err := getFlag()
+	assert.NoError(t, err)
+	assert.Equal(t, "a", value)
+
+	eventually := func(expected string) {
+		assert.Eventually(t, func() bool {
+			value, _ := getFlag()
+			return value == expected
+		}, 5*time.Second, 10*time.Millisecond)
+	}
+
+	resp := request(t, http.MethodPost, httpServer.URL+"/admin/flags/flag/disable", "", nil)
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+	eventually("default")
+
+	resp = request(t, http.MethodPost, httpServer.URL+"/admin/flags/flag/enable", "", nil)
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+	eventually("a")
+
+	resp = request(t, http.MethodPut, httpServer.URL+"/admin/flags/flag/value", `{"value": "fixed"}`, nil)
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+	eventually("fixed")
+
+	resp = request(t, http.MethodPut, httpServer.URL+"/admin/config", string(testFlagsJSON(t, "b")), nil)
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+	// The fixed value is kept when the configuration is replaced.
+	time.Sleep(50 * time.Millisecond)
+	value, _ = getFlag()
+	assert.Equal(t, "fixed", value)
+
+	resp = request(t, http.MethodPost, httpServer.URL+"/admin/reset", "", nil)
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+	eventually("b")
+}
+
+func Test_server_newFlag(t *testing.T) {
+	_, httpServer := newTestServer(t, nil, "")
+
+	resp := request(t, http.MethodPut, httpServer.URL+"/admin/flags/new/value", `{"value": {"a": 1}}`, nil)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	resp = request(t, http.MethodPut, httpServer.URL+"/admin/flags/new/value", `{"value": {"a": 1}, "variationType": "JSON"}`, nil)
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+
+	resp = request(t, http.MethodGet, httpServer.URL+eppoclient.CONFIG_ENDPOINT+"?apiKey=any", "", nil)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	flagsJSON, err := io.ReadAll(resp.Body)
+	assert.NoError(t, err)
+
+	client, err := eppoclient.InitClientFromConfiguration(eppoclient.Config{
+		ApplicationLogger: eppoclient.NewZapLogger(zap.NewNop()),
+	}, flagsJSON, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	value, err := client.GetJSONAssignment("new", "subject", eppoclient.Attributes{}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"a": 1.0}, value)
+
+	resp = request(t, http.MethodGet, httpServer.URL+"/admin/flags", "", nil)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	var flags []flagStatus
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&flags))
+	if assert.Len(t, flags, 3) {
+		assert.Equal(t, "flag", flags[0].Key)
+		assert.Equal(t, "new", flags[1].Key)
+		assert.Equal(t, eppoclient.VariationTypeJSON, flags[1].VariationType)
+		assert.True(t, flags[1].Enabled)
+		assert.JSONEq(t, `"{\"a\": 1}"`, string(flags[1].FixedValue))
+	}
+
+	resp = request(t, http.MethodDelete, httpServer.URL+"/admin/flags/new/value", "", nil)
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+	resp = request(t, http.MethodDelete, httpServer.URL+"/admin/flags/new/value", "", nil)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
+
+func Test_server_eTag(t *testing.T) {
+	_, httpServer := newTestServer(t, nil, "")
+	url := httpServer.URL + eppoclient.CONFIG_ENDPOINT + "?apiKey=any"
+
+	resp := request(t, http.MethodGet, url, "", nil)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	eTag := resp.Header.Get("ETag")
+	assert.NotEmpty(t, eTag)
+
+	resp = request(t, http.MethodGet, url, "", http.Header{"If-None-Match": {eTag}})
+	assert.Equal(t, http.StatusNotModified, resp.StatusCode)
+
+	request(t, http.MethodPost, httpServer.URL+"/admin/flags/flag/disable", "", nil)
+	resp = request(t, http.MethodGet, url, "", http.Header{"If-None-Match": {eTag}})
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.NotEqual(t, eTag, resp.Header.Get("ETag"))
+
+	resp = request(t, http.MethodGet, httpServer.URL+eppoclient.BANDIT_ENDPOINT+"?apiKey=any", "", nil)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	body, err := io.ReadAll(resp.Body)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"bandits": {}}`, string(body))
+}
+
+func Test_server_sdkKeys(t *testing.T) {
+	_, httpServer := newTestServer(t, []string{"key-1", "key-2"}, "")
+	url := httpServer.URL + apiPrefix + eppoclient.CONFIG_ENDPOINT
+
+	resp := request(t, http.MethodGet, url, "", nil)
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	resp = request(t, http.MethodGet, url+"?apiKey=other", "", nil)
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	resp = request(t, http.MethodGet, url+"?apiKey=key-1", "", nil)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	resp = request(t, http.MethodGet, url, "", http.Header{"Authorization": {"Bearer key-2"}})
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}
+
+func Test_server_adminToken(t *testing.T) {
+	_, httpServer := newTestServer(t, nil, "secret")
+
+	resp := request(t, http.MethodGet, httpServer.URL+"/admin/flags", "", nil)
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	resp = request(t, http.MethodGet, httpServer.URL+"/admin/flags", "", http.Header{"Authorization": {"Bearer secret"}})
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}
+
+func Test_server_adminErrors(t *testing.T) {
+	_, httpServer := newTestServer(t, nil, "")
+
+	tests := []struct {
+		method, path, body string
+		status             int
+	}{
+		{http.MethodPost, "/admin/flags/missing/disable", "", http.StatusNotFound},
+		{http.MethodGet, "/admin/flags/flag/disable", "", http.StatusMethodNotAllowed},
+		{http.MethodPut, "/admin/flags/flag/value", `{"value": 1}`, http.StatusBadRequest},
+		{http.MethodPut, "/admin/flags/number/value", `{"value": 1.5}`, http.StatusBadRequest},
+		{http.MethodPut, "/admin/flags/number/value", `{"value": 2, "variationType": "STRING"}`, http.StatusBadRequest},
+		{http.MethodPut, "/admin/flags/flag/value", `{}`, http.StatusBadRequest},
+		{http.MethodPut, "/admin/config", `{"flags": 1}`, http.StatusBadRequest},
+		{http.MethodPut, "/admin/bandits", `not json`, http.StatusBadRequest},
+		{http.MethodPost, "/admin/unknown", "", http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		resp := request(t, tt.method, httpServer.URL+tt.path, tt.body, nil)
+		assert.Equal(t, tt.status, resp.StatusCode, "%s %s %s", tt.method, tt.path, tt.body)
+	}
+}
+
+func Test_server_reload(t *testing.T) {
+	s, httpServer := newTestServer(t, nil, "")
+	url := httpServer.URL + eppoclient.CONFIG_ENDPOINT + "?apiKey=any"
+	eTag := request(t, http.MethodGet, url, "", nil).Header.Get("ETag")
+
+	assert.NoError(t, os.WriteFile(filepath.Join(s.dir, flagsFileName), testFlagsJSON(t, "b"), 0o600))
+	assert.NoError(t, os.WriteFile(filepath.Join(s.dir, banditsFileName), []byte(`{"bandits": {"x": {}}}`), 0o600))
+	resp := request(t, http.MethodPost, httpServer.URL+"/admin/reload", "", nil)
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+	assert.NotEqual(t, eTag, request(t, http.MethodGet, url, "", nil).Header.Get("ETag"))
+
+	resp = request(t, http.MethodGet, httpServer.URL+eppoclient.BANDIT_ENDPOINT+"?apiKey=any", "", nil)
+	body, err := io.ReadAll(resp.Body)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"bandits": {"x": {}}}`, string(body))
+
+	assert.NoError(t, os.WriteFile(filepath.Join(s.dir, flagsFileName), []byte(`invalid`), 0o600))
+	resp = request(t, http.MethodPost, httpServer.URL+"/admin/reload", "", nil)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
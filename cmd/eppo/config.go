@@ -0,0 +1,188 @@
+package main
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/Eppo-exp/golang-sdk/v6/eppoclient"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultBaseURL = "https://fscdn.eppo.cloud/api"
+	sdkKeyEnv      = "EPPO_SDK_KEY"
+	fetchTimeout   = 30 * time.Second
+)
+
+// configSource is where a command reads configuration from: local
+// files or Eppo.
+type configSource struct {
+	file        string
+	banditsFile string
+	sdkKey      string
+	baseURL     string
+}
+
+// register adds flags selecting the configuration source. `prefix`
+// distinguishes multiple sources of a single command.
+func (s *configSource) register(fs *flag.FlagSet, prefix string) {
+	fs.StringVar(&s.file, prefix+"config", "", "flags configuration `file` (as served at "+eppoclient.CONFIG_ENDPOINT+")")
+	fs.StringVar(&s.banditsFile, prefix+"bandits", "", "optional bandits configuration `file` (as served at "+eppoclient.BANDIT_ENDPOINT+")")
+	fs.StringVar(&s.sdkKey, prefix+"sdk-key", "", "fetch configuration from Eppo with this SDK `key` (default $"+sdkKeyEnv+")")
+	fs.StringVar(&s.baseURL, prefix+"base-url", defaultBaseURL, "Eppo API `url` used with -"+prefix+"sdk-key")
+}
+
+// loadedConfig is a configuration loaded from a configSource.
+type loadedConfig struct {
+	flagsJSON   []byte
+	banditsJSON []byte
+	// flags holds metadata of flags keyed by flag key as found in
+	// configuration, i.e., hashed if the configuration is obfuscated.
+	flags      map[string]flagMetadata
+	obfuscated bool
+}
+
+type flagMetadata struct {
+	Enabled       bool                     `json:"enabled"`
+	VariationType eppoclient.VariationType `json:"variationType"`
+}
+
+func (s *configSource) load() (*loadedConfig, error) {
+	flagsJSON, banditsJSON, err := s.loadJSON()
+	if err != nil {
+		return nil, err
+	}
+	config := &loadedConfig{flagsJSON: flagsJSON, banditsJSON: banditsJSON}
+
+	var response struct {
+		Format string                  `json:"format"`
+		Flags  map[string]flagMetadata `json:"flags"`
+	}
+	if err := json.Unmarshal(flagsJSON, &response); err != nil {
+		return nil, fmt.Errorf("failed to parse flags configuration: %w", err)
+	}
+	if response.Flags == nil {
+		return nil, errors.New("failed to parse flags configuration: missing \"flags\" field")
+	}
+	config.flags = response.Flags
+	config.obfuscated = response.Format == "CLIENT"
+	return config, nil
+}
+
+// loadJSON reads raw configuration without parsing it. Bandits are
+// fetched from Eppo only if flags reference them.
+func (s *configSource) loadJSON() (flagsJSON, banditsJSON []byte, err error) {
+	if s.file != "" {
+		flagsJSON, err = os.ReadFile(s.file)
+		if err != nil {
+			return nil, nil, err
+		}
+		if s.banditsFile != "" {
+			banditsJSON, err = os.ReadFile(s.banditsFile)
+			if err != nil {
+				return nil, nil, err
+			}
+		}
+		return flagsJSON, banditsJSON, nil
+	}
+
+	if s.resolvedSDKKey() == "" {
+		return nil, nil, fmt.Errorf("%w: either -config or -sdk-key is required", errUsage)
+	}
+	flagsJSON, err = fetch(s.baseURL, eppoclient.CONFIG_ENDPOINT, s.resolvedSDKKey())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var response struct {
+		Bandits map[string]json.RawMessage `json:"bandits"`
+	}
+	if json.Unmarshal(flagsJSON, &response) == nil && len(response.Bandits) > 0 {
+		banditsJSON, err = fetch(s.baseURL, eppoclient.BANDIT_ENDPOINT, s.resolvedSDKKey())
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	return flagsJSON, banditsJSON, nil
+}
+
+func (s *configSource) resolvedSDKKey() string {
+	if s.sdkKey != "" {
+		return s.sdkKey
+	}
+	return os.Getenv(sdkKeyEnv)
+}
+
+func fetch(baseURL, endpoint, sdkKey string) ([]byte, error) {
+	query := url.Values{}
+	query.Set("apiKey", sdkKey)
+	query.Set("sdkName", "eppo-cli")
+
+	client := &http.Client{Timeout: fetchTimeout}
+	resp, err := client.Get(baseURL + endpoint + "?" + query.Encode())
+	if err != nil {
+		// Do not print the URL, it contains the SDK key.
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return nil, fmt.Errorf("failed to fetch %s: %v", endpoint, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusUnauthorized {
+		return nil, fmt.Errorf("failed to fetch %s: %w", endpoint, eppoclient.ErrUnauthorized)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch %s: unexpected status %s", endpoint, resp.Status)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+// flagKeys returns sorted keys of all flags. Keys of obfuscated
+// configurations are hashed and cannot be listed.
+func (c *loadedConfig) flagKeys() ([]string, error) {
+	if c.obfuscated {
+		return nil, errors.New("flag keys of obfuscated configurations cannot be listed; pass -flag")
+	}
+	keys := make([]string, 0, len(c.flags))
+	for key := range c.flags {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
+
+// flag looks up flag metadata by plaintext key.
+func (c *loadedConfig) flag(key string) (flagMetadata, bool) {
+	flag, ok := c.flags[c.configKey(key)]
+	return flag, ok
+}
+
+// configKey returns the key of a flag as found in configuration,
+// i.e., hashed if the configuration is obfuscated.
+func (c *loadedConfig) configKey(key string) string {
+	if c.obfuscated {
+		hash := md5.Sum([]byte(key))
+		return hex.EncodeToString(hash[:])
+	}
+	return key
+}
+
+// newClient creates an offline client for the configuration.
+// Assignments are not logged.
+func (c *loadedConfig) newClient() (*eppoclient.EppoClient, error) {
+	return eppoclient.InitClientFromConfiguration(eppoclient.Config{
+		ApplicationLogger: eppoclient.NewZapLogger(zap.NewNop()),
+	}, c.flagsJSON, c.banditsJSON)
+}
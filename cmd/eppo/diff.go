@@ -0,0 +1,144 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/Eppo-exp/golang-sdk/v6/eppoclient"
+)
+
+func runDiff(args []string, stdout, stderr io.Writer) error {
+	fs := newFlagSet("diff", "", stderr)
+	var oldSource, newSource configSource
+	oldSource.register(fs, "old-")
+	newSource.register(fs, "new-")
+	subjectsFile := fs.String("subjects", "", "subject sample `file` (JSON Lines of {\"key\": ..., \"attributes\": {...}}) to estimate changed assignments")
+	jsonOutput := fs.Bool("json", false, "print differences as JSON")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() > 0 {
+		fs.Usage()
+		return errUsage
+	}
+
+	oldFlagsJSON, _, err := oldSource.loadJSON()
+	if err != nil {
+		return fmt.Errorf("old configuration: %w", err)
+	}
+	newFlagsJSON, _, err := newSource.loadJSON()
+	if err != nil {
+		return fmt.Errorf("new configuration: %w", err)
+	}
+	diff, err := eppoclient.DiffConfigurations(oldFlagsJSON, newFlagsJSON)
+	if err != nil {
+		return err
+	}
+
+	var impacts []eppoclient.FlagImpact
+	if *subjectsFile != "" {
+		subjects, err := readSubjectsFile(*subjectsFile)
+		if err != nil {
+			return err
+		}
+		impacts, err = eppoclient.AnalyzeAssignmentImpact(oldFlagsJSON, newFlagsJSON, subjects)
+		if err != nil {
+			return err
+		}
+	}
+
+	if *jsonOutput {
+		encoder := json.NewEncoder(stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(struct {
+			Flags  []eppoclient.FlagDiff   `json:"flags"`
+			Impact []eppoclient.FlagImpact `json:"impact,omitempty"`
+		}{diff.Flags, impacts})
+	}
+
+	if len(diff.Flags) == 0 {
+		fmt.Fprintln(stdout, "no differences")
+		return nil
+	}
+	for _, flag := range diff.Flags {
+		printFlagDiff(stdout, flag)
+	}
+	if *subjectsFile != "" {
+		fmt.Fprintln(stdout)
+		printImpact(stdout, impacts)
+	}
+	return nil
+}
+
+func readSubjectsFile(path string) ([]eppoclient.Subject, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	subjects, err := eppoclient.ReadSubjects(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return subjects, nil
+}
+
+func printFlagDiff(w io.Writer, flag eppoclient.FlagDiff) {
+	switch {
+	case flag.Added:
+		fmt.Fprintf(w, "+ %s: added\n", flag.FlagKey)
+		return
+	case flag.Removed:
+		fmt.Fprintf(w, "- %s: removed\n", flag.FlagKey)
+		return
+	}
+
+	var changes []string
+	if flag.Enabled {
+		changes = append(changes, "enabled")
+	}
+	if flag.Disabled {
+		changes = append(changes, "disabled")
+	}
+	if flag.AllocationsChanged {
+		changes = append(changes, "allocations changed")
+	}
+	if len(flag.VariationsChanged) > 0 {
+		changes = append(changes, "variations changed ("+strings.Join(flag.VariationsChanged, ", ")+")")
+	}
+	if len(changes) == 0 {
+		changes = append(changes, "changed")
+	}
+	fmt.Fprintf(w, "~ %s: %s\n", flag.FlagKey, strings.Join(changes, ", "))
+	for _, d := range flag.Differences {
+		fmt.Fprintf(w, "    %s: %s -> %s\n", d.Path, formatValue(d.Old), formatValue(d.New))
+	}
+}
+
+func printImpact(w io.Writer, impacts []eppoclient.FlagImpact) {
+	if len(impacts) == 0 {
+		fmt.Fprintln(w, "Impact: no changed flags")
+		return
+	}
+	fmt.Fprintf(w, "Impact on %s:\n", plural(impacts[0].Subjects, "subject"))
+	for _, impact := range impacts {
+		share := 0.0
+		if impact.Subjects > 0 {
+			share = 100 * float64(impact.Changed) / float64(impact.Subjects)
+		}
+		fmt.Fprintf(w, "  %s: %s change variation (%.1f%%)\n", impact.FlagKey, plural(impact.Changed, "subject"), share)
+		for _, t := range impact.Transitions {
+			fmt.Fprintf(w, "    %s -> %s: %d\n", variationName(t.From), variationName(t.To), t.Subjects)
+		}
+	}
+}
+
+func variationName(key string) string {
+	if key == "" {
+		return "(default)"
+	}
+	return key
+}
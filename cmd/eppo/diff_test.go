@@ -0,0 +1,103 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Eppo-exp/golang-sdk/v6/configbuilder"
+	"github.com/Eppo-exp/golang-sdk/v6/eppoclient"
+	"github.com/stretchr/testify/assert"
+)
+
+// rolloutTestConfig returns testConfigBuilder with "checkout" rolled
+// out to `percent` of subjects instead of a half.
+func rolloutTestConfig(percent float64) *configbuilder.Builder {
+	b := testConfigBuilder()
+	b.Flag("checkout", eppoclient.VariationTypeBoolean).
+		Variation("on", true).
+		Variation("off", false).
+		Allocation(configbuilder.NewAllocation("internal").
+			Rule(configbuilder.OneOf("country", "US"), configbuilder.GTE("age", 18)).
+			Serve("on")).
+		Allocation(configbuilder.NewAllocation("rollout").
+			Salt("rollout").
+			Split("on", percent).
+			Split("off", 100-percent))
+	return b
+}
+
+func writeTestSubjects(t *testing.T, n int) string {
+	var lines []string
+	for i := 0; i < n; i++ {
+		lines = append(lines, fmt.Sprintf(`{"key": "subject-%d", "attributes": {"country": "FR"}}`, i))
+	}
+	path := filepath.Join(t.TempDir(), "subjects.jsonl")
+	if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func Test_diff(t *testing.T) {
+	oldPath := writeTestConfig(t, testConfigBuilder())
+	newPath := writeTestConfig(t, rolloutTestConfig(80))
+	subjectsPath := writeTestSubjects(t, 200)
+
+	status, stdout, stderr := runCLI("diff", "-old-config", oldPath, "-new-config", newPath, "-subjects", subjectsPath)
+	assert.Equal(t, 0, status, stderr)
+	assert.Contains(t, stdout, "~ checkout: allocations changed\n")
+	assert.Contains(t, stdout, "    allocations.rollout.splits[0].shards[0].ranges[0].end: 5000 -> 8000\n")
+	assert.Contains(t, stdout, "Impact on 200 subjects:\n")
+	assert.Contains(t, stdout, "  checkout: ")
+	assert.Contains(t, stdout, "    off -> on: ")
+	assert.NotContains(t, stdout, "on -> off")
+	assert.NotContains(t, stdout, "color")
+}
+
+func Test_diff_json(t *testing.T) {
+	oldPath := writeTestConfig(t, testConfigBuilder())
+	newBuilder := rolloutTestConfig(80)
+	newBuilder.Flag("color", eppoclient.VariationTypeString).
+		Variation("red", "red").
+		Disabled()
+	newPath := writeTestConfig(t, newBuilder)
+	subjectsPath := writeTestSubjects(t, 100)
+
+	status, stdout, stderr := runCLI("diff", "-old-config", oldPath, "-new-config", newPath, "-subjects", subjectsPath, "-json")
+	assert.Equal(t, 0, status, stderr)
+
+	var result struct {
+		Flags  []eppoclient.FlagDiff   `json:"flags"`
+		Impact []eppoclient.FlagImpact `json:"impact"`
+	}
+	assert.NoError(t, json.Unmarshal([]byte(stdout), &result))
+	if assert.Len(t, result.Flags, 2) {
+		assert.Equal(t, "checkout", result.Flags[0].FlagKey)
+		assert.Equal(t, "color", result.Flags[1].FlagKey)
+		assert.True(t, result.Flags[1].Disabled)
+	}
+	if assert.Len(t, result.Impact, 2) {
+		assert.Equal(t, eppoclient.FlagImpact{
+			FlagKey:     "color",
+			Subjects:    100,
+			Changed:     100,
+			Transitions: []eppoclient.VariationTransition{{From: "red", To: "", Subjects: 100}},
+		}, result.Impact[1])
+	}
+}
+
+func Test_diff_noDifferences(t *testing.T) {
+	path := writeTestConfig(t, testConfigBuilder())
+
+	status, stdout, stderr := runCLI("diff", "-old-config", path, "-new-config", path)
+	assert.Equal(t, 0, status, stderr)
+	assert.Equal(t, "no differences\n", stdout)
+
+	status, _, stderr = runCLI("diff", "-old-config", path, "-new-config", filepath.Join(t.TempDir(), "missing.json"))
+	assert.Equal(t, 1, status)
+	assert.Contains(t, stderr, "new configuration")
+}
@@ -0,0 +1,319 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Eppo-exp/golang-sdk/v6/eppoclient"
+)
+
+func runEval(args []string, stdout, stderr io.Writer) error {
+	fs := newFlagSet("eval", "[attribute=value ...]", stderr)
+	var source configSource
+	source.register(fs, "")
+	flagKey := fs.String("flag", "", "`key` of the flag to evaluate; all flags are evaluated if empty")
+	subjectKey := fs.String("subject", "", "subject `key` (required)")
+	attributesJSON := fs.String("attributes", "", "subject attributes as a JSON `object`; attribute=value arguments take precedence")
+	jsonOutput := fs.Bool("json", false, "print results as JSON")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if *subjectKey == "" {
+		return fmt.Errorf("%w: -subject is required", errUsage)
+	}
+	attributes, err := parseAttributes(*attributesJSON, fs.Args())
+	if err != nil {
+		return fmt.Errorf("%w: %v", errUsage, err)
+	}
+
+	config, err := source.load()
+	if err != nil {
+		return err
+	}
+	flagKeys := []string{*flagKey}
+	if *flagKey == "" {
+		flagKeys, err = config.flagKeys()
+		if err != nil {
+			return err
+		}
+	}
+	client, err := config.newClient()
+	if err != nil {
+		return err
+	}
+
+	explanations := make([]explanation, len(flagKeys))
+	for i, key := range flagKeys {
+		explanations[i] = evaluate(client, config, key, *subjectKey, attributes)
+	}
+
+	if *jsonOutput {
+		encoder := json.NewEncoder(stdout)
+		encoder.SetIndent("", "  ")
+		if *flagKey != "" {
+			return encoder.Encode(explanations[0])
+		}
+		return encoder.Encode(explanations)
+	}
+	for i, e := range explanations {
+		if i > 0 {
+			fmt.Fprintln(stdout)
+		}
+		e.print(stdout)
+	}
+	return nil
+}
+
+// parseAttributes merges attributes from a JSON object and
+// attribute=value arguments. Argument values that parse as numbers or
+// booleans are converted; pass them in JSON to keep them as strings.
+func parseAttributes(attributesJSON string, args []string) (eppoclient.Attributes, error) {
+	attributes := eppoclient.Attributes{}
+	if attributesJSON != "" {
+		if err := json.Unmarshal([]byte(attributesJSON), &attributes); err != nil {
+			return nil, fmt.Errorf("invalid -attributes: %v", err)
+		}
+	}
+	for _, arg := range args {
+		key, value, ok := strings.Cut(arg, "=")
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid attribute %q: expected attribute=value", arg)
+		}
+		attributes[key] = parseAttributeValue(value)
+	}
+	return attributes, nil
+}
+
+func parseAttributeValue(value string) interface{} {
+	if value == "true" || value == "false" {
+		return value == "true"
+	}
+	if number, err := strconv.ParseFloat(value, 64); err == nil {
+		return number
+	}
+	return value
+}
+
+// explanation is the result of evaluating a flag for a subject,
+// derived from eppoclient.EvaluationDetails.
+type explanation struct {
+	FlagKey           string                   `json:"flagKey"`
+	VariationType     eppoclient.VariationType `json:"variationType,omitempty"`
+	SubjectKey        string                   `json:"subjectKey"`
+	SubjectAttributes eppoclient.Attributes    `json:"subjectAttributes"`
+	// Value is nil if the subject is not assigned a variation.
+	Value         interface{}                 `json:"value"`
+	VariationKey  string                      `json:"variationKey,omitempty"`
+	AllocationKey string                      `json:"allocationKey,omitempty"`
+	Reason        eppoclient.EvaluationReason `json:"reason"`
+	Error         string                      `json:"error,omitempty"`
+	Allocations   []allocationExplanation     `json:"allocations"`
+}
+
+type allocationExplanation struct {
+	Key    string                      `json:"key"`
+	Reason eppoclient.EvaluationReason `json:"reason"`
+	Rules  []ruleExplanation           `json:"rules,omitempty"`
+	Splits []splitExplanation          `json:"splits,omitempty"`
+}
+
+type ruleExplanation struct {
+	Matched    bool                   `json:"matched"`
+	Conditions []conditionExplanation `json:"conditions"`
+}
+
+type conditionExplanation struct {
+	Attribute    string      `json:"attribute"`
+	Operator     string      `json:"operator"`
+	Value        interface{} `json:"value"`
+	SubjectValue interface{} `json:"subjectValue"`
+	Matched      bool        `json:"matched"`
+}
+
+type splitExplanation struct {
+	VariationKey string             `json:"variationKey"`
+	Matched      bool               `json:"matched"`
+	Shards       []shardExplanation `json:"shards"`
+}
+
+type shardExplanation struct {
+	Salt        string       `json:"salt"`
+	Shard       int64        `json:"shard"`
+	TotalShards int64        `json:"totalShards"`
+	Ranges      []shardRange `json:"ranges"`
+	Matched     bool         `json:"matched"`
+}
+
+// shardRange is a half-open [Start, End) range of shards.
+type shardRange struct {
+	Start int64 `json:"start"`
+	End   int64 `json:"end"`
+}
+
+// evaluate evaluates a flag with the getter matching its variation
+// type.
+func evaluate(client *eppoclient.EppoClient, config *loadedConfig, flagKey, subjectKey string, attributes eppoclient.Attributes) explanation {
+	flag, _ := config.flag(flagKey)
+
+	var value interface{}
+	var details eppoclient.EvaluationDetails
+	var err error
+	switch flag.VariationType {
+	case eppoclient.VariationTypeBoolean:
+		value, details, err = client.GetBoolAssignmentDetails(flagKey, subjectKey, attributes, false)
+	case eppoclient.VariationTypeInteger:
+		value, details, err = client.GetIntegerAssignmentDetails(flagKey, subjectKey, attributes, 0)
+	case eppoclient.VariationTypeNumeric:
+		value, details, err = client.GetNumericAssignmentDetails(flagKey, subjectKey, attributes, 0)
+	case eppoclient.VariationTypeJSON:
+		value, details, err = client.GetJSONAssignmentDetails(flagKey, subjectKey, attributes, nil)
+	default:
+		// Missing flags are reported by any getter.
+		value, details, err = client.GetStringAssignmentDetails(flagKey, subjectKey, attributes, "")
+	}
+
+	e := explanation{
+		FlagKey:           flagKey,
+		VariationType:     flag.VariationType,
+		SubjectKey:        subjectKey,
+		SubjectAttributes: details.SubjectAttributes,
+		VariationKey:      details.VariationKey,
+		AllocationKey:     details.AllocationKey,
+		Reason:            details.Reason,
+		Allocations:       make([]allocationExplanation, len(details.Allocations)),
+	}
+	if details.VariationKey != "" {
+		e.Value = value
+	}
+	if err != nil {
+		e.Error = err.Error()
+	}
+	for i, a := range details.Allocations {
+		e.Allocations[i] = explainAllocation(a)
+	}
+	return e
+}
+
+func explainAllocation(a eppoclient.AllocationEvaluation) allocationExplanation {
+	result := allocationExplanation{Key: a.Key, Reason: a.Reason}
+	for _, r := range a.Rules {
+		rule := ruleExplanation{Matched: r.Matched, Conditions: make([]conditionExplanation, len(r.Conditions))}
+		for i, c := range r.Conditions {
+			rule.Conditions[i] = conditionExplanation(c)
+		}
+		result.Rules = append(result.Rules, rule)
+	}
+	for _, s := range a.Splits {
+		split := splitExplanation{VariationKey: s.VariationKey, Matched: s.Matched, Shards: make([]shardExplanation, len(s.Shards))}
+		for i, shard := range s.Shards {
+			split.Shards[i] = shardExplanation{
+				Salt:        shard.Salt,
+				Shard:       shard.ShardValue,
+				TotalShards: shard.TotalShards,
+				Ranges:      make([]shardRange, len(shard.Ranges)),
+				Matched:     shard.Matched,
+			}
+			for j, r := range shard.Ranges {
+				split.Shards[i].Ranges[j] = shardRange(r)
+			}
+		}
+		result.Splits = append(result.Splits, split)
+	}
+	return result
+}
+
+// print writes a human-readable trace of the evaluation.
+func (e explanation) print(w io.Writer) {
+	if e.VariationType != "" {
+		fmt.Fprintf(w, "Flag:       %s (%s)\n", e.FlagKey, e.VariationType)
+	} else {
+		fmt.Fprintf(w, "Flag:       %s\n", e.FlagKey)
+	}
+	fmt.Fprintf(w, "Subject:    %s\n", e.SubjectKey)
+	if len(e.SubjectAttributes) > 0 {
+		fmt.Fprintf(w, "Attributes: %s\n", formatAttributes(e.SubjectAttributes))
+	}
+	if e.VariationKey != "" {
+		fmt.Fprintf(w, "Result:     %s (variation %q, allocation %q)\n", formatValue(e.Value), e.VariationKey, e.AllocationKey)
+	} else {
+		fmt.Fprintf(w, "Result:     default value (%s)\n", e.Reason)
+	}
+	if e.Error != "" {
+		fmt.Fprintf(w, "Error:      %s\n", e.Error)
+	}
+
+	if len(e.Allocations) == 0 {
+		return
+	}
+	fmt.Fprintln(w, "Allocations:")
+	for i, a := range e.Allocations {
+		fmt.Fprintf(w, "  %d. %s: %s\n", i+1, a.Key, a.Reason)
+		for j, r := range a.Rules {
+			fmt.Fprintf(w, "       rule %d: %s\n", j+1, matchedString(r.Matched))
+			for _, c := range r.Conditions {
+				fmt.Fprintf(w, "         [%s] %s %s %s (subject: %s)\n",
+					checkMark(c.Matched), c.Attribute, c.Operator, formatValue(c.Value), formatValue(c.SubjectValue))
+			}
+		}
+		for _, s := range a.Splits {
+			fmt.Fprintf(w, "       split -> %s: %s\n", s.VariationKey, matchedString(s.Matched))
+			for _, shard := range s.Shards {
+				fmt.Fprintf(w, "         [%s] shard %d/%d (salt %q) in %s\n",
+					checkMark(shard.Matched), shard.Shard, shard.TotalShards, shard.Salt, formatRanges(shard.Ranges))
+			}
+		}
+	}
+}
+
+func matchedString(matched bool) string {
+	if matched {
+		return "matched"
+	}
+	return "not matched"
+}
+
+func checkMark(matched bool) string {
+	if matched {
+		return "x"
+	}
+	return " "
+}
+
+func formatValue(value interface{}) string {
+	if value == nil {
+		return "<missing>"
+	}
+	b, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(b)
+}
+
+func formatAttributes(attributes eppoclient.Attributes) string {
+	keys := make([]string, 0, len(attributes))
+	for key := range attributes {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	parts := make([]string, len(keys))
+	for i, key := range keys {
+		parts[i] = key + "=" + formatValue(attributes[key])
+	}
+	return strings.Join(parts, " ")
+}
+
+func formatRanges(ranges []shardRange) string {
+	parts := make([]string, len(ranges))
+	for i, r := range ranges {
+		parts[i] = fmt.Sprintf("[%d, %d)", r.Start, r.End)
+	}
+	if len(parts) == 0 {
+		return "(no ranges)"
+	}
+	return strings.Join(parts, " ")
+}
@@ -0,0 +1,176 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Eppo-exp/golang-sdk/v6/configbuilder"
+	"github.com/Eppo-exp/golang-sdk/v6/eppoclient"
+	"github.com/stretchr/testify/assert"
+)
+
+func testConfigBuilder() *configbuilder.Builder {
+	b := configbuilder.New()
+	b.Flag("checkout", eppoclient.VariationTypeBoolean).
+		Variation("on", true).
+		Variation("off", false).
+		Allocation(configbuilder.NewAllocation("internal").
+			Rule(configbuilder.OneOf("country", "US"), configbuilder.GTE("age", 18)).
+			Serve("on")).
+		Allocation(configbuilder.NewAllocation("rollout").
+			Salt("rollout").
+			Split("on", 50).
+			Split("off", 50))
+	b.Flag("color", eppoclient.VariationTypeString).
+		Variation("red", "red").
+		Allocation(configbuilder.NewAllocation("all").Serve("red"))
+	b.Flag("disabled", eppoclient.VariationTypeInteger).
+		Variation("one", 1).
+		Disabled()
+	return b
+}
+
+func writeTestConfig(t *testing.T, b *configbuilder.Builder) string {
+	flagsJSON, err := b.FlagsJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "flags.json")
+	if err := os.WriteFile(path, flagsJSON, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func runCLI(args ...string) (int, string, string) {
+	var stdout, stderr bytes.Buffer
+	status := run(args, &stdout, &stderr)
+	return status, stdout.String(), stderr.String()
+}
+
+func Test_eval_ruleMatch(t *testing.T) {
+	path := writeTestConfig(t, testConfigBuilder())
+
+	status, stdout, stderr := runCLI("eval", "-config", path, "-flag", "checkout", "-subject", "alice", "country=US", "age=30")
+	assert.Equal(t, 0, status, stderr)
+	assert.Contains(t, stdout, "Flag:       checkout (BOOLEAN)")
+	assert.Contains(t, stdout, `Attributes: age=30 country="US" id="alice"`)
+	assert.Contains(t, stdout, `Result:     true (variation "on", allocation "internal")`)
+	assert.Contains(t, stdout, "1. internal: MATCH")
+	assert.Contains(t, stdout, `[x] country ONE_OF ["US"] (subject: "US")`)
+	assert.Contains(t, stdout, "2. rollout: UNEVALUATED")
+}
+
+func Test_eval_json(t *testing.T) {
+	path := writeTestConfig(t, testConfigBuilder())
+
+	status, stdout, stderr := runCLI("eval", "-config", path, "-flag", "checkout", "-subject", "bob",
+		"-attributes", `{"country": "FR", "age": "30"}`, "-json")
+	assert.Equal(t, 0, status, stderr)
+
+	var result explanation
+	assert.NoError(t, json.Unmarshal([]byte(stdout), &result))
+	assert.Equal(t, "checkout", result.FlagKey)
+	assert.Equal(t, "rollout", result.AllocationKey)
+	assert.Equal(t, eppoclient.EvaluationReasonMatch, result.Reason)
+	assert.Equal(t, "30", result.SubjectAttributes["age"])
+	if assert.Len(t, result.Allocations, 2) {
+		assert.Equal(t, eppoclient.EvaluationReasonRuleFailed, result.Allocations[0].Reason)
+		assert.False(t, result.Allocations[0].Rules[0].Conditions[0].Matched)
+
+		rollout := result.Allocations[1]
+		assert.Equal(t, eppoclient.EvaluationReasonMatch, rollout.Reason)
+		var matched *splitExplanation
+		for i := range rollout.Splits {
+			if rollout.Splits[i].Matched {
+				matched = &rollout.Splits[i]
+			}
+		}
+		if assert.NotNil(t, matched) {
+			assert.Equal(t, result.VariationKey, matched.VariationKey)
+			assert.Equal(t, "rollout-split", matched.Shards[0].Salt)
+			assert.Equal(t, int64(configbuilder.DefaultTotalShards), matched.Shards[0].TotalShards)
+		}
+	}
+}
+
+func Test_eval_allFlags(t *testing.T) {
+	path := writeTestConfig(t, testConfigBuilder())
+
+	status, stdout, stderr := runCLI("eval", "-config", path, "-subject", "alice", "-json")
+	assert.Equal(t, 0, status, stderr)
+
+	var results []explanation
+	assert.NoError(t, json.Unmarshal([]byte(stdout), &results))
+	if assert.Len(t, results, 3) {
+		assert.Equal(t, "checkout", results[0].FlagKey)
+		assert.Equal(t, "color", results[1].FlagKey)
+		assert.Equal(t, "red", results[1].Value)
+		assert.Equal(t, "disabled", results[2].FlagKey)
+		assert.Nil(t, results[2].Value)
+		assert.Equal(t, eppoclient.EvaluationReasonFlagDisabled, results[2].Reason)
+		assert.NotEmpty(t, results[2].Error)
+	}
+
+	status, stdout, _ = runCLI("eval", "-config", path, "-subject", "alice")
+	assert.Equal(t, 0, status)
+	assert.Contains(t, stdout, "Result:     default value (FLAG_DISABLED)")
+}
+
+func Test_eval_fetch(t *testing.T) {
+	flagsJSON, err := testConfigBuilder().FlagsJSON()
+	assert.NoError(t, err)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("apiKey") != "test-key" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		assert.Equal(t, "/api"+eppoclient.CONFIG_ENDPOINT, r.URL.Path)
+		_, _ = w.Write(flagsJSON)
+	}))
+	defer server.Close()
+
+	status, stdout, stderr := runCLI("eval", "-sdk-key", "test-key", "-base-url", server.URL+"/api", "-flag", "color", "-subject", "alice")
+	assert.Equal(t, 0, status, stderr)
+	assert.Contains(t, stdout, `Result:     "red"`)
+
+	status, _, stderr = runCLI("eval", "-sdk-key", "wrong-key", "-base-url", server.URL+"/api", "-flag", "color", "-subject", "alice")
+	assert.Equal(t, 1, status)
+	assert.Contains(t, stderr, "unauthorized")
+	assert.NotContains(t, stderr, "wrong-key")
+}
+
+func Test_eval_usage(t *testing.T) {
+	path := writeTestConfig(t, testConfigBuilder())
+	t.Setenv(sdkKeyEnv, "")
+
+	status, _, stderr := runCLI("eval", "-config", path)
+	assert.Equal(t, 2, status)
+	assert.Contains(t, stderr, "-subject is required")
+
+	status, _, stderr = runCLI("eval", "-subject", "alice")
+	assert.Equal(t, 2, status)
+	assert.Contains(t, stderr, "either -config or -sdk-key is required")
+
+	status, _, stderr = runCLI("eval", "-config", path, "-subject", "alice", "invalid")
+	assert.Equal(t, 2, status)
+	assert.Contains(t, stderr, `invalid attribute "invalid"`)
+
+	status, _, _ = runCLI("unknown")
+	assert.Equal(t, 2, status)
+
+	status, stdout, _ := runCLI("help")
+	assert.Equal(t, 0, status)
+	assert.Contains(t, stdout, "eval")
+}
+
+func Test_parseAttributes(t *testing.T) {
+	attributes, err := parseAttributes(`{"a": "1", "b": 2}`, []string{"b=3", "c=true", "d=text", "e=x=y"})
+	assert.NoError(t, err)
+	assert.Equal(t, eppoclient.Attributes{"a": "1", "b": 3.0, "c": true, "d": "text", "e": "x=y"}, attributes)
+}
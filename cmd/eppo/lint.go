@@ -0,0 +1,63 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/Eppo-exp/golang-sdk/v6/eppoclient"
+)
+
+func runLint(args []string, stdout, stderr io.Writer) error {
+	fs := newFlagSet("lint", "", stderr)
+	var source configSource
+	source.register(fs, "")
+	jsonOutput := fs.Bool("json", false, "print diagnostics as JSON")
+	strict := fs.Bool("strict", false, "fail on warnings too")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() > 0 {
+		fs.Usage()
+		return errUsage
+	}
+
+	flagsJSON, banditsJSON, err := source.loadJSON()
+	if err != nil {
+		return err
+	}
+	diagnostics := eppoclient.ValidateConfiguration(flagsJSON, banditsJSON)
+
+	if *jsonOutput {
+		if diagnostics == nil {
+			diagnostics = eppoclient.Diagnostics{}
+		}
+		encoder := json.NewEncoder(stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(diagnostics); err != nil {
+			return err
+		}
+	} else {
+		for _, d := range diagnostics {
+			fmt.Fprintln(stdout, d)
+		}
+		errs, warnings := len(diagnostics.Errors()), len(diagnostics.Warnings())
+		if errs == 0 && warnings == 0 {
+			fmt.Fprintln(stdout, "no problems found")
+		} else {
+			fmt.Fprintf(stdout, "%s, %s\n", plural(errs, "error"), plural(warnings, "warning"))
+		}
+	}
+
+	if diagnostics.HasErrors() || (*strict && len(diagnostics) > 0) {
+		return errSilent
+	}
+	return nil
+}
+
+func plural(n int, noun string) string {
+	if n == 1 {
+		return fmt.Sprintf("%d %s", n, noun)
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Eppo-exp/golang-sdk/v6/eppoclient"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_lint(t *testing.T) {
+	path := writeTestConfig(t, testConfigBuilder())
+
+	status, stdout, stderr := runCLI("lint", "-config", path)
+	assert.Equal(t, 0, status, stderr)
+	assert.Equal(t, "no problems found\n", stdout)
+
+	flagsJSON, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	broken := strings.Replace(string(flagsJSON), `"variationKey":"red"`, `"variationKey":"blue"`, 1)
+	broken = strings.Replace(broken, `"key":"checkout"`, `"key":"renamed"`, 1)
+	brokenPath := filepath.Join(t.TempDir(), "flags.json")
+	assert.NoError(t, os.WriteFile(brokenPath, []byte(broken), 0o600))
+
+	status, stdout, _ = runCLI("lint", "-config", brokenPath)
+	assert.Equal(t, 1, status)
+	assert.Contains(t, stdout, `error: flags.color.allocations[0].splits[0]: split references unknown variation "blue"`)
+	assert.Contains(t, stdout, `warning: flags.checkout.key:`)
+	assert.Contains(t, stdout, "1 error, 1 warning")
+
+	status, stdout, _ = runCLI("lint", "-config", brokenPath, "-json")
+	assert.Equal(t, 1, status)
+	var diagnostics eppoclient.Diagnostics
+	assert.NoError(t, json.Unmarshal([]byte(stdout), &diagnostics))
+	if assert.Len(t, diagnostics, 2) {
+		assert.Equal(t, "checkout", diagnostics[0].FlagKey)
+		assert.Equal(t, eppoclient.SeverityWarning, diagnostics[0].Severity)
+		assert.Equal(t, "color", diagnostics[1].FlagKey)
+		assert.Equal(t, eppoclient.SeverityError, diagnostics[1].Severity)
+	}
+}
+
+func Test_lint_strict(t *testing.T) {
+	flagsJSON, err := testConfigBuilder().FlagsJSON()
+	assert.NoError(t, err)
+	path := filepath.Join(t.TempDir(), "flags.json")
+	assert.NoError(t, os.WriteFile(path, []byte(strings.Replace(string(flagsJSON), `"key":"checkout"`, `"key":"renamed"`, 1)), 0o600))
+
+	status, _, _ := runCLI("lint", "-config", path)
+	assert.Equal(t, 0, status)
+	status, _, _ = runCLI("lint", "-config", path, "-strict")
+	assert.Equal(t, 1, status)
+}
+
+func Test_lint_invalidJSON(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "flags.json")
+	assert.NoError(t, os.WriteFile(path, []byte(`{"flags": `), 0o600))
+
+	status, stdout, _ := runCLI("lint", "-config", path)
+	assert.Equal(t, 1, status)
+	assert.Contains(t, stdout, "error: failed to parse flags configuration")
+}
@@ -0,0 +1,131 @@
+// Command eppo evaluates and inspects Eppo flag configurations from
+// the command line.
+//
+// Configuration is read from a file with -config, or fetched from
+// Eppo with -sdk-key (or the EPPO_SDK_KEY environment variable):
+//
+//	eppo eval -config flags.json -flag new-checkout -subject alice country=US age=30
+//	eppo eval -sdk-key $KEY -subject alice -attributes '{"country": "US"}' -json
+//	eppo lint -config flags.json -bandits bandits.json
+//	eppo diff -old-sdk-key $KEY -new-config flags.json -subjects subjects.jsonl
+//	eppo simulate -config flags.json -flag new-checkout -distribution attributes.json
+//	eppo scan -config flags.json ./...
+//
+// Run "eppo help <command>" for the options of a command.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+)
+
+// command is a subcommand of the CLI.
+type command struct {
+	name    string
+	summary string
+	// run executes the command with arguments following the command
+	// name. Returns errUsage (possibly wrapped) on invalid arguments.
+	run func(args []string, stdout, stderr io.Writer) error
+}
+
+// errUsage reports invalid command-line arguments. Errors wrapping it
+// are printed; errUsage itself means usage has already been printed
+// by the flag package.
+var errUsage = errors.New("invalid arguments")
+
+// errSilent makes the CLI exit with a failure status without
+// printing an error, because the command already reported the
+// failure in its output.
+var errSilent = errors.New("failed")
+
+func commands() []command {
+	return []command{
+		{name: "eval", summary: "evaluate flags for a subject and explain the result", run: runEval},
+		{name: "lint", summary: "validate configuration and report problems", run: runLint},
+		{name: "diff", summary: "compare two configurations and estimate changed assignments", run: runDiff},
+		{name: "simulate", summary: "simulate traffic of a flag and report allocation coverage", run: runSimulate},
+		{name: "scan", summary: "find flags referenced in Go code and report stale or unknown ones", run: runScan},
+	}
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// run executes the CLI and returns the exit status.
+func run(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		printUsage(stderr)
+		return 2
+	}
+
+	name, args := args[0], args[1:]
+	if name == "help" || name == "-h" || name == "-help" || name == "--help" {
+		if len(args) == 0 {
+			printUsage(stdout)
+			return 0
+		}
+		name, args = args[0], []string{"-help"}
+	}
+
+	for _, cmd := range commands() {
+		if cmd.name != name {
+			continue
+		}
+		err := cmd.run(args, stdout, stderr)
+		switch {
+		case err == nil || errors.Is(err, flag.ErrHelp):
+			return 0
+		case errors.Is(err, errUsage):
+			if err != errUsage {
+				fmt.Fprintf(stderr, "eppo %s: %v\n", name, err)
+			}
+			return 2
+		case errors.Is(err, errSilent):
+			return 1
+		default:
+			fmt.Fprintf(stderr, "eppo %s: %v\n", name, err)
+			return 1
+		}
+	}
+
+	fmt.Fprintf(stderr, "eppo: unknown command %q\n", name)
+	printUsage(stderr)
+	return 2
+}
+
+func printUsage(w io.Writer) {
+	fmt.Fprintln(w, "usage: eppo <command> [options]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Commands:")
+	for _, cmd := range commands() {
+		fmt.Fprintf(w, "  %-10s %s\n", cmd.name, cmd.summary)
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, `Run "eppo help <command>" for the options of a command.`)
+}
+
+// newFlagSet creates a flag set for a command that reports errors to
+// `stderr` instead of exiting.
+func newFlagSet(name, arguments string, stderr io.Writer) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprintf(stderr, "usage: eppo %s [options] %s\n\nOptions:\n", name, arguments)
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+// parseFlags parses command-line flags, converting errors to
+// errUsage.
+func parseFlags(fs *flag.FlagSet, args []string) error {
+	err := fs.Parse(args)
+	if err != nil && !errors.Is(err, flag.ErrHelp) {
+		return errUsage
+	}
+	return err
+}
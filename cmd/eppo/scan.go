@@ -0,0 +1,480 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Eppo-exp/golang-sdk/v6/eppoclient"
+)
+
+func runScan(args []string, stdout, stderr io.Writer) error {
+	fs := newFlagSet("scan", "[path ...]", stderr)
+	var source configSource
+	source.register(fs, "")
+	jsonOutput := fs.Bool("json", false, "print the report as JSON")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	paths := fs.Args()
+	if len(paths) == 0 {
+		paths = []string{"."}
+	}
+
+	config, err := source.load()
+	if err != nil {
+		return err
+	}
+	rolledOut, err := eppoclient.FindRolledOutFlags(config.flagsJSON)
+	if err != nil {
+		return err
+	}
+	s := newScanner()
+	for _, p := range paths {
+		if err := s.add(p); err != nil {
+			return err
+		}
+	}
+	references, unresolved := s.references()
+	report := newScanReport(config, rolledOut, references, unresolved)
+
+	if *jsonOutput {
+		encoder := json.NewEncoder(stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(report); err != nil {
+			return err
+		}
+	} else {
+		report.print(stdout, len(s.files))
+	}
+	if len(report.Missing) > 0 {
+		return errSilent
+	}
+	return nil
+}
+
+// getterNames are methods of eppoclient.Client taking a flag key,
+// which is the first argument or the second one for "Context"
+// variants.
+var getterNames = func() map[string]bool {
+	names := map[string]bool{"GetBanditAction": true, "GetBanditActionContext": true}
+	for _, t := range []string{"Bool", "Numeric", "Integer", "String", "JSON", "JSONBytes"} {
+		for _, details := range []string{"", "Details"} {
+			names["Get"+t+"Assignment"+details] = true
+			names["Get"+t+"Assignment"+details+"Context"] = true
+		}
+	}
+	return names
+}()
+
+// genericGetterNames are functions of package eppoclient taking the
+// client followed by a flag key, or a context, the client, and a flag
+// key for "Context" variants.
+var genericGetterNames = map[string]bool{"GetAssignment": true, "GetAssignmentContext": true}
+
+// flagReference is a call of a getter with a constant flag key.
+type flagReference struct {
+	FlagKey  string `json:"flagKey"`
+	Function string `json:"function"`
+	Position string `json:"position"`
+}
+
+// unresolvedReference is a call of a getter whose flag key is not a
+// constant known to the scanner.
+type unresolvedReference struct {
+	Function   string `json:"function"`
+	Position   string `json:"position"`
+	Expression string `json:"expression"`
+}
+
+// scanner finds flag references in Go source files. Without type
+// information, any method named like an eppoclient.Client getter is
+// treated as one.
+type scanner struct {
+	fset  *token.FileSet
+	files []*sourceFile
+	// Packages by directory and package name.
+	packages map[[2]string]*sourcePackage
+	// Packages by package name, to resolve imported constants.
+	packagesByName map[string][]*sourcePackage
+}
+
+type sourcePackage struct {
+	consts map[string]constDecl
+}
+
+type constDecl struct {
+	value ast.Expr
+	file  *sourceFile
+}
+
+type sourceFile struct {
+	ast *ast.File
+	pkg *sourcePackage
+	// Import paths by local name.
+	imports map[string]string
+}
+
+func newScanner() *scanner {
+	return &scanner{
+		fset:           token.NewFileSet(),
+		packages:       make(map[[2]string]*sourcePackage),
+		packagesByName: make(map[string][]*sourcePackage),
+	}
+}
+
+// add parses a Go file, or all Go files in a directory tree except
+// for vendor, testdata, and hidden directories. A trailing "/..." is
+// accepted as in Go package patterns.
+func (s *scanner) add(root string) error {
+	root = strings.TrimSuffix(strings.TrimSuffix(root, "..."), string(filepath.Separator))
+	if root == "" {
+		root = "."
+	}
+	return filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		name := d.Name()
+		if d.IsDir() {
+			if p != root && (name == "vendor" || name == "testdata" || strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_")) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !strings.HasSuffix(name, ".go") {
+			return nil
+		}
+		return s.addFile(p)
+	})
+}
+
+func (s *scanner) addFile(p string) error {
+	src, err := os.ReadFile(p)
+	if err != nil {
+		return err
+	}
+	file, err := parser.ParseFile(s.fset, p, src, parser.SkipObjectResolution)
+	if err != nil {
+		return err
+	}
+
+	key := [2]string{filepath.Dir(p), file.Name.Name}
+	pkg, ok := s.packages[key]
+	if !ok {
+		pkg = &sourcePackage{consts: make(map[string]constDecl)}
+		s.packages[key] = pkg
+		s.packagesByName[file.Name.Name] = append(s.packagesByName[file.Name.Name], pkg)
+	}
+	f := &sourceFile{ast: file, pkg: pkg, imports: make(map[string]string)}
+	for _, spec := range file.Imports {
+		importPath, err := strconv.Unquote(spec.Path.Value)
+		if err != nil {
+			continue
+		}
+		name := importedPackageName(importPath)
+		if spec.Name != nil {
+			name = spec.Name.Name
+		}
+		f.imports[name] = importPath
+	}
+
+	for _, decl := range file.Decls {
+		if gen, ok := decl.(*ast.GenDecl); ok && gen.Tok == token.CONST {
+			addConsts(pkg.consts, gen, f)
+		}
+	}
+	s.files = append(s.files, f)
+	return nil
+}
+
+// addConsts records constants declared with explicit values.
+func addConsts(consts map[string]constDecl, gen *ast.GenDecl, f *sourceFile) {
+	for _, spec := range gen.Specs {
+		valueSpec := spec.(*ast.ValueSpec)
+		if len(valueSpec.Values) != len(valueSpec.Names) {
+			continue
+		}
+		for i, name := range valueSpec.Names {
+			consts[name.Name] = constDecl{value: valueSpec.Values[i], file: f}
+		}
+	}
+}
+
+// importedPackageName guesses the name of a package from its import
+// path, skipping major version suffixes.
+func importedPackageName(importPath string) string {
+	name := path.Base(importPath)
+	if len(name) > 1 && name[0] == 'v' {
+		if _, err := strconv.Atoi(name[1:]); err == nil {
+			name = path.Base(path.Dir(importPath))
+		}
+	}
+	return name
+}
+
+// references returns getter calls with constant flag keys and those
+// with other flag keys, in order of position.
+func (s *scanner) references() ([]flagReference, []unresolvedReference) {
+	var references []flagReference
+	var unresolved []unresolvedReference
+	for _, f := range s.files {
+		for _, decl := range f.ast.Decls {
+			// Constants declared in functions shadow package
+			// constants.
+			scopes := []map[string]constDecl{f.pkg.consts}
+			if fn, ok := decl.(*ast.FuncDecl); ok && fn.Body != nil {
+				local := make(map[string]constDecl)
+				ast.Inspect(fn.Body, func(node ast.Node) bool {
+					if gen, ok := node.(*ast.GenDecl); ok && gen.Tok == token.CONST {
+						addConsts(local, gen, f)
+					}
+					return true
+				})
+				scopes = append(scopes, local)
+			}
+
+			ast.Inspect(decl, func(node ast.Node) bool {
+				call, ok := node.(*ast.CallExpr)
+				if !ok {
+					return true
+				}
+				function, index, ok := f.getterCall(call)
+				if !ok || index >= len(call.Args) {
+					return true
+				}
+				position := s.fset.Position(call.Args[index].Pos()).String()
+				if key, ok := s.constString(call.Args[index], f, scopes, 0); ok {
+					references = append(references, flagReference{FlagKey: key, Function: function, Position: position})
+				} else {
+					unresolved = append(unresolved, unresolvedReference{Function: function, Position: position, Expression: types.ExprString(call.Args[index])})
+				}
+				return true
+			})
+		}
+	}
+	return references, unresolved
+}
+
+// getterCall returns the name of the getter called by `call` and the
+// index of its flag key argument.
+func (f *sourceFile) getterCall(call *ast.CallExpr) (string, int, bool) {
+	fun := call.Fun
+	switch index := fun.(type) {
+	case *ast.IndexExpr:
+		fun = index.X
+	case *ast.IndexListExpr:
+		fun = index.X
+	}
+	selector, ok := fun.(*ast.SelectorExpr)
+	if !ok {
+		return "", 0, false
+	}
+	name := selector.Sel.Name
+	index := 0
+	if strings.HasSuffix(name, "Context") {
+		index = 1
+	}
+
+	if ident, ok := selector.X.(*ast.Ident); ok && isEppoClientImport(f.imports[ident.Name]) {
+		if genericGetterNames[name] {
+			return name, index + 1, true
+		}
+		return "", 0, false
+	}
+	if getterNames[name] {
+		return name, index, true
+	}
+	return "", 0, false
+}
+
+func isEppoClientImport(importPath string) bool {
+	return strings.HasPrefix(importPath, "github.com/Eppo-exp/golang-sdk/") && strings.HasSuffix(importPath, "/eppoclient")
+}
+
+// constString evaluates a constant string expression: string
+// literals, constants, parentheses, and concatenation.
+func (s *scanner) constString(expr ast.Expr, f *sourceFile, scopes []map[string]constDecl, depth int) (string, bool) {
+	if depth > 16 {
+		return "", false
+	}
+	switch expr := expr.(type) {
+	case *ast.BasicLit:
+		if expr.Kind != token.STRING {
+			return "", false
+		}
+		value, err := strconv.Unquote(expr.Value)
+		return value, err == nil
+	case *ast.ParenExpr:
+		return s.constString(expr.X, f, scopes, depth+1)
+	case *ast.BinaryExpr:
+		if expr.Op != token.ADD {
+			return "", false
+		}
+		x, ok := s.constString(expr.X, f, scopes, depth+1)
+		if !ok {
+			return "", false
+		}
+		y, ok := s.constString(expr.Y, f, scopes, depth+1)
+		return x + y, ok
+	case *ast.Ident:
+		for i := len(scopes) - 1; i >= 0; i-- {
+			if decl, ok := scopes[i][expr.Name]; ok {
+				return s.constString(decl.value, decl.file, scopes[:i+1], depth+1)
+			}
+		}
+	case *ast.SelectorExpr:
+		ident, ok := expr.X.(*ast.Ident)
+		if !ok {
+			return "", false
+		}
+		importPath, ok := f.imports[ident.Name]
+		if !ok {
+			return "", false
+		}
+		// Packages are matched by name, so the value must be the
+		// same in all scanned packages with that name.
+		value, found := "", false
+		for _, pkg := range s.packagesByName[importedPackageName(importPath)] {
+			decl, ok := pkg.consts[expr.Sel.Name]
+			if !ok {
+				continue
+			}
+			v, ok := s.constString(decl.value, decl.file, []map[string]constDecl{pkg.consts}, depth+1)
+			if !ok || (found && v != value) {
+				return "", false
+			}
+			value, found = v, true
+		}
+		return value, found
+	}
+	return "", false
+}
+
+// scanReport cross-references flag references with configuration.
+type scanReport struct {
+	// Flags referenced in code but missing from configuration.
+	Missing []flagUsage `json:"missing"`
+	// Flags in configuration never referenced in code. Empty if
+	// configuration is obfuscated.
+	Unused []string `json:"unused"`
+	// UnusedOmitted is set if unused flags are not listed because
+	// configuration is obfuscated and only hashes of their keys are
+	// known.
+	UnusedOmitted bool `json:"unusedOmitted,omitempty"`
+	// Referenced flags that assign the same variation to everyone.
+	RolledOut []rolledOutUsage `json:"rolledOut"`
+	// Getter calls whose flag keys could not be determined.
+	Unresolved []unresolvedReference `json:"unresolved"`
+	// Number of getter calls with constant flag keys.
+	References int `json:"references"`
+}
+
+type flagUsage struct {
+	FlagKey    string   `json:"flagKey"`
+	References []string `json:"references"`
+}
+
+type rolledOutUsage struct {
+	flagUsage
+	VariationKey string      `json:"variationKey"`
+	Value        interface{} `json:"value"`
+}
+
+func newScanReport(config *loadedConfig, rolledOut []eppoclient.RolledOutFlag, references []flagReference, unresolved []unresolvedReference) scanReport {
+	report := scanReport{
+		Missing:    []flagUsage{},
+		Unused:     []string{},
+		RolledOut:  []rolledOutUsage{},
+		Unresolved: unresolved,
+		References: len(references),
+	}
+	if report.Unresolved == nil {
+		report.Unresolved = []unresolvedReference{}
+	}
+
+	positions := make(map[string][]string)
+	referenced := make(map[string]bool)
+	for _, r := range references {
+		positions[r.FlagKey] = append(positions[r.FlagKey], r.Position)
+		referenced[config.configKey(r.FlagKey)] = true
+	}
+	rolledOutByKey := make(map[string]eppoclient.RolledOutFlag, len(rolledOut))
+	for _, flag := range rolledOut {
+		rolledOutByKey[flag.FlagKey] = flag
+	}
+
+	keys := make([]string, 0, len(positions))
+	for key := range positions {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		usage := flagUsage{FlagKey: key, References: positions[key]}
+		if _, ok := config.flag(key); !ok {
+			report.Missing = append(report.Missing, usage)
+		} else if flag, ok := rolledOutByKey[config.configKey(key)]; ok {
+			report.RolledOut = append(report.RolledOut, rolledOutUsage{flagUsage: usage, VariationKey: flag.VariationKey, Value: flag.Value})
+		}
+	}
+	if config.obfuscated {
+		report.UnusedOmitted = true
+		return report
+	}
+	for key := range config.flags {
+		if !referenced[key] {
+			report.Unused = append(report.Unused, key)
+		}
+	}
+	sort.Strings(report.Unused)
+	return report
+}
+
+func (r scanReport) print(w io.Writer, files int) {
+	fmt.Fprintf(w, "Scanned %s: %s with constant flag keys.\n", plural(files, "file"), plural(r.References, "getter call"))
+	if len(r.Missing) > 0 {
+		fmt.Fprintln(w, "\nMissing from configuration (code always gets the default value):")
+		for _, usage := range r.Missing {
+			usage.print(w, usage.FlagKey)
+		}
+	}
+	if len(r.Unused) > 0 {
+		fmt.Fprintln(w, "\nNot referenced in code:")
+		for _, key := range r.Unused {
+			fmt.Fprintf(w, "  %s\n", key)
+		}
+	}
+	if r.UnusedOmitted {
+		fmt.Fprintln(w, "\nFlags not referenced in code are not listed: configuration is obfuscated, so only hashes of flag keys are known.")
+	}
+	if len(r.RolledOut) > 0 {
+		fmt.Fprintln(w, "\nRolled out to a single variation (can be cleaned up):")
+		for _, usage := range r.RolledOut {
+			usage.print(w, fmt.Sprintf("%s: always %q (%s)", usage.FlagKey, usage.VariationKey, formatValue(usage.Value)))
+		}
+	}
+	if len(r.Unresolved) > 0 {
+		fmt.Fprintln(w, "\nFlag keys that are not constant (not checked):")
+		for _, u := range r.Unresolved {
+			fmt.Fprintf(w, "  %s: %s(%s)\n", u.Position, u.Function, u.Expression)
+		}
+	}
+}
+
+func (u flagUsage) print(w io.Writer, title string) {
+	fmt.Fprintf(w, "  %s\n", title)
+	for _, position := range u.References {
+		fmt.Fprintf(w, "      %s\n", position)
+	}
+}
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// writeTestSource writes Go files into a temporary directory and
+// returns its path. `files` maps slash-separated paths to contents.
+func writeTestSource(t *testing.T, files map[string]string) string {
+	dir := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+var scanTestSource = map[string]string{
+	"flags/flags.go": `package flags
+
+const (
+	Color  = "color"
+	prefix = "missing-"
+)
+
+const Missing = prefix + "flag"
+`,
+	"app/app.go": `package app
+
+import (
+	"context"
+
+	"example.com/app/flags"
+	eppo "github.com/Eppo-exp/golang-sdk/v6/eppoclient"
+)
+
+func checkout(ctx context.Context, client eppo.Client, key string) {
+	const local = "checkout"
+	client.GetBoolAssignment(local, "subject", nil, false)
+	client.GetStringAssignmentContext(ctx, flags.Color, "subject", nil, "")
+	eppo.GetAssignment[string](nil, flags.Missing, "subject", nil, "")
+	client.GetIntegerAssignmentDetails(key, "subject", nil, 0)
+	client.Close()
+}
+`,
+	"app/testdata/ignored.go": `package ignored
+
+func f(client interface{ GetBoolAssignment(string) }) { client.GetBoolAssignment("ignored") }
+`,
+}
+
+func Test_scan(t *testing.T) {
+	config := writeTestConfig(t, testConfigBuilder())
+	dir := writeTestSource(t, scanTestSource)
+
+	status, stdout, stderr := runCLI("scan", "-config", config, dir+"/...")
+	assert.Equal(t, 1, status, stderr)
+	appFile := filepath.Join(dir, "app", "app.go")
+	assert.Contains(t, stdout, "Scanned 2 files: 3 getter calls with constant flag keys.\n")
+	assert.Contains(t, stdout, "Missing from configuration (code always gets the default value):\n  missing-flag\n      "+appFile+":14:34\n")
+	assert.Contains(t, stdout, "Not referenced in code:\n  disabled\n")
+	assert.Contains(t, stdout, "Rolled out to a single variation (can be cleaned up):\n  color: always \"red\" (\"red\")\n      "+appFile+":13:41\n")
+	assert.Contains(t, stdout, "Flag keys that are not constant (not checked):\n  "+appFile+":15:37: GetIntegerAssignmentDetails(key)\n")
+	assert.NotContains(t, stdout, "checkout:")
+}
+
+func Test_scan_json(t *testing.T) {
+	config := writeTestConfig(t, testConfigBuilder())
+	source := map[string]string{"main.go": `package main
+
+func main() {
+	client.GetBoolAssignment("checkout", "subject", nil, false)
+	client.GetStringAssignment("color", "subject", nil, "")
+}
+`}
+	dir := writeTestSource(t, source)
+
+	status, stdout, stderr := runCLI("scan", "-config", config, "-json", filepath.Join(dir, "main.go"))
+	assert.Equal(t, 0, status, stderr)
+
+	var report scanReport
+	assert.NoError(t, json.Unmarshal([]byte(stdout), &report))
+	assert.Equal(t, 2, report.References)
+	assert.Empty(t, report.Missing)
+	assert.Equal(t, []string{"disabled"}, report.Unused)
+	if assert.Len(t, report.RolledOut, 1) {
+		assert.Equal(t, "color", report.RolledOut[0].FlagKey)
+		assert.Equal(t, "red", report.RolledOut[0].Value)
+	}
+	assert.Empty(t, report.Unresolved)
+}
+
+func Test_scan_obfuscated(t *testing.T) {
+	hash := func(key string) string {
+		sum := md5.Sum([]byte(key))
+		return hex.EncodeToString(sum[:])
+	}
+	config := filepath.Join(t.TempDir(), "flags.json")
+	flagsJSON := `{"format": "CLIENT", "flags": {
+  "` + hash("checkout") + `": {"key": "` + hash("checkout") + `", "enabled": true, "variationType": "BOOLEAN", "totalShards": 10000, "variations": {}, "allocations": []},
+  "` + hash("unused") + `": {"key": "` + hash("unused") + `", "enabled": true, "variationType": "BOOLEAN", "totalShards": 10000, "variations": {}, "allocations": []}
+}}`
+	if err := os.WriteFile(config, []byte(flagsJSON), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	source := map[string]string{"main.go": `package main
+
+func main() {
+	client.GetBoolAssignment("checkout", "subject", nil, false)
+	client.GetBoolAssignment("missing", "subject", nil, false)
+}
+`}
+	dir := writeTestSource(t, source)
+
+	status, stdout, stderr := runCLI("scan", "-config", config, filepath.Join(dir, "main.go"))
+	assert.Equal(t, 1, status, stderr)
+	assert.Contains(t, stdout, "Missing from configuration (code always gets the default value):\n  missing\n")
+	assert.NotContains(t, stdout, "checkout\n")
+	assert.NotContains(t, stdout, hash("unused"))
+	assert.Contains(t, stdout, "Flags not referenced in code are not listed: configuration is obfuscated")
+
+	status, stdout, stderr = runCLI("scan", "-config", config, "-json", filepath.Join(dir, "main.go"))
+	assert.Equal(t, 1, status, stderr)
+	var report scanReport
+	assert.NoError(t, json.Unmarshal([]byte(stdout), &report))
+	assert.Empty(t, report.Unused)
+	assert.True(t, report.UnusedOmitted)
+}
+
+func Test_scan_parseError(t *testing.T) {
+	config := writeTestConfig(t, testConfigBuilder())
+	dir := writeTestSource(t, map[string]string{"broken.go": "package broken\n\nfunc {"})
+
+	status, _, stderr := runCLI("scan", "-config", config, dir)
+	assert.Equal(t, 1, status)
+	assert.Contains(t, stderr, "broken.go")
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/Eppo-exp/golang-sdk/v6/eppoclient"
+)
+
+func runSimulate(args []string, stdout, stderr io.Writer) error {
+	fs := newFlagSet("simulate", "", stderr)
+	var source configSource
+	source.register(fs, "")
+	flagKey := fs.String("flag", "", "`key` of the flag to simulate (required)")
+	subjectsFile := fs.String("subjects", "", "subject sample `file` (JSON Lines of {\"key\": ..., \"attributes\": {...}})")
+	distributionFile := fs.String("distribution", "", "`file` with a JSON object mapping attributes to [{\"value\": ..., \"weight\": ...}] to generate synthetic subjects")
+	n := fs.Int("n", 10000, "`number` of synthetic subjects")
+	seed := fs.Int64("seed", 1, "random `seed` for synthetic subjects")
+	jsonOutput := fs.Bool("json", false, "print the report as JSON")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	switch {
+	case fs.NArg() > 0:
+		fs.Usage()
+		return errUsage
+	case *flagKey == "":
+		return fmt.Errorf("%w: -flag is required", errUsage)
+	case *subjectsFile != "" && *distributionFile != "":
+		return fmt.Errorf("%w: -subjects and -distribution are mutually exclusive", errUsage)
+	case *n <= 0:
+		return fmt.Errorf("%w: -n must be positive", errUsage)
+	}
+
+	flagsJSON, _, err := source.loadJSON()
+	if err != nil {
+		return err
+	}
+	var subjects []eppoclient.Subject
+	if *subjectsFile != "" {
+		subjects, err = readSubjectsFile(*subjectsFile)
+	} else {
+		subjects, err = syntheticSubjects(*distributionFile, *n, *seed)
+	}
+	if err != nil {
+		return err
+	}
+	report, err := eppoclient.SimulateTraffic(flagsJSON, *flagKey, subjects)
+	if err != nil {
+		return err
+	}
+
+	if *jsonOutput {
+		encoder := json.NewEncoder(stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(report)
+	}
+	printTrafficReport(stdout, report)
+	return nil
+}
+
+// syntheticSubjects generates subjects with attributes drawn from the
+// distribution in `path`, or without attributes if `path` is empty.
+func syntheticSubjects(path string, n int, seed int64) ([]eppoclient.Subject, error) {
+	var distribution eppoclient.AttributeDistribution
+	if path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(data, &distribution); err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+	}
+	subjects, err := eppoclient.SyntheticSubjects(distribution, n, seed)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return subjects, nil
+}
+
+func printTrafficReport(w io.Writer, report eppoclient.TrafficReport) {
+	fmt.Fprintf(w, "Flag:     %s\n", report.FlagKey)
+	fmt.Fprintf(w, "Subjects: %d\n", report.Subjects)
+	if !report.Enabled {
+		fmt.Fprintln(w, "Flag is disabled: every subject gets the default value.")
+	}
+
+	if len(report.Allocations) > 0 {
+		fmt.Fprintln(w, "Allocations:")
+	}
+	for i, a := range report.Allocations {
+		fmt.Fprintf(w, "  %d. %s: matched %s, assigned %s, fell through %s\n",
+			i+1, a.Key, formatShare(a.Matched.Share), formatShare(a.Assigned.Share), formatShare(a.FellThrough.Share))
+		for _, v := range a.Variations {
+			fmt.Fprintf(w, "       %s: %s\n", v.VariationKey, formatShare(v.Share))
+		}
+	}
+
+	fmt.Fprintln(w, "Variations:")
+	for _, v := range report.Variations {
+		fmt.Fprintf(w, "  %s: %s\n", v.VariationKey, formatShare(v.Share))
+	}
+	fmt.Fprintf(w, "  %s: %s\n", variationName(""), formatShare(report.Default.Share))
+
+	if len(report.Issues) > 0 {
+		fmt.Fprintln(w, "Issues:")
+	}
+	for _, issue := range report.Issues {
+		fmt.Fprintf(w, "  %s: %s: %s\n", issue.Kind, issue.AllocationKey, issue.Message)
+	}
+}
+
+func formatShare(share float64) string {
+	return fmt.Sprintf("%.1f%%", 100*share)
+}
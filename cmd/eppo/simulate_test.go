@@ -0,0 +1,74 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Eppo-exp/golang-sdk/v6/configbuilder"
+	"github.com/Eppo-exp/golang-sdk/v6/eppoclient"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_simulate(t *testing.T) {
+	path := writeTestConfig(t, testConfigBuilder())
+	distributionPath := filepath.Join(t.TempDir(), "distribution.json")
+	distribution := `{
+  "country": [{"value": "US", "weight": 1}, {"value": "FR", "weight": 1}],
+  "age": [{"value": 30, "weight": 1}]
+}`
+	if err := os.WriteFile(distributionPath, []byte(distribution), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	status, stdout, stderr := runCLI("simulate", "-config", path, "-flag", "checkout", "-distribution", distributionPath, "-n", "2000", "-json")
+	assert.Equal(t, 0, status, stderr)
+
+	var report eppoclient.TrafficReport
+	assert.NoError(t, json.Unmarshal([]byte(stdout), &report))
+	assert.Equal(t, 2000, report.Subjects)
+	if assert.Len(t, report.Allocations, 2) {
+		assert.InDelta(t, 0.5, report.Allocations[0].Assigned.Share, 0.05)
+		assert.InDelta(t, 0.5, report.Allocations[1].Matched.Share, 0.05)
+		assert.Zero(t, report.Allocations[1].FellThrough.Subjects)
+	}
+	assert.Zero(t, report.Default.Subjects)
+	assert.Empty(t, report.Issues)
+}
+
+func Test_simulate_issues(t *testing.T) {
+	b := testConfigBuilder()
+	b.Flag("partial", eppoclient.VariationTypeString).
+		Variation("a", "a").
+		Variation("b", "b").
+		Allocation(configbuilder.NewAllocation("half").Exposure(50).Serve("a")).
+		Allocation(configbuilder.NewAllocation("rest").Serve("b")).
+		Allocation(configbuilder.NewAllocation("never").Serve("a"))
+	path := writeTestConfig(t, b)
+
+	status, stdout, stderr := runCLI("simulate", "-config", path, "-flag", "partial", "-n", "1000")
+	assert.Equal(t, 0, status, stderr)
+	assert.Contains(t, stdout, "Subjects: 1000\n")
+	assert.Contains(t, stdout, "  1. half: matched 100.0%, assigned ")
+	assert.Contains(t, stdout, "  3. never: matched 0.0%, assigned 0.0%, fell through 0.0%\n")
+	assert.Contains(t, stdout, "  (default): 0.0%\n")
+	assert.Contains(t, stdout, "  SHARD_GAP: half: 50.00% of matching subjects are not in any split and fall through\n")
+	assert.Contains(t, stdout, `  UNREACHABLE_ALLOCATION: never: allocation is never evaluated: earlier allocation "rest" assigns every subject`)
+}
+
+func Test_simulate_usage(t *testing.T) {
+	path := writeTestConfig(t, testConfigBuilder())
+
+	status, _, stderr := runCLI("simulate", "-config", path)
+	assert.Equal(t, 2, status)
+	assert.Contains(t, stderr, "-flag is required")
+
+	status, _, stderr = runCLI("simulate", "-config", path, "-flag", "checkout", "-subjects", "a", "-distribution", "b")
+	assert.Equal(t, 2, status)
+	assert.Contains(t, stderr, "mutually exclusive")
+
+	status, _, stderr = runCLI("simulate", "-config", path, "-flag", "missing")
+	assert.Equal(t, 1, status)
+	assert.Contains(t, stderr, "missing")
+}
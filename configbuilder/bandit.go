@@ -0,0 +1,153 @@
+package configbuilder
+
+import (
+	"sort"
+	"time"
+)
+
+// BanditBuilder builds a contextual bandit model. See
+// Builder.Bandit.
+type BanditBuilder struct {
+	key                    string
+	modelName              string
+	modelVersion           string
+	gamma                  float64
+	defaultActionScore     float64
+	actionProbabilityFloor float64
+	actions                map[string]*BanditActionBuilder
+}
+
+// ModelName sets the model name. Defaults to "falcon".
+func (b *BanditBuilder) ModelName(name string) *BanditBuilder {
+	b.modelName = name
+	return b
+}
+
+// Gamma sets the exploration parameter. Higher values favor the
+// best-scored action more. Defaults to 1.
+func (b *BanditBuilder) Gamma(gamma float64) *BanditBuilder {
+	b.gamma = gamma
+	return b
+}
+
+// DefaultActionScore sets the score of actions without coefficients.
+func (b *BanditBuilder) DefaultActionScore(score float64) *BanditBuilder {
+	b.defaultActionScore = score
+	return b
+}
+
+// ActionProbabilityFloor sets the minimum probability of selecting
+// any action.
+func (b *BanditBuilder) ActionProbabilityFloor(floor float64) *BanditBuilder {
+	b.actionProbabilityFloor = floor
+	return b
+}
+
+// Action sets coefficients of an action. Actions without
+// coefficients are scored with DefaultActionScore.
+func (b *BanditBuilder) Action(action *BanditActionBuilder) *BanditBuilder {
+	b.actions[action.key] = action
+	return b
+}
+
+func (b *BanditBuilder) build(updatedAt time.Time) wireBandit {
+	bandit := wireBandit{
+		BanditKey:    b.key,
+		ModelName:    b.modelName,
+		ModelVersion: b.modelVersion,
+		UpdatedAt:    formatTime(updatedAt),
+		ModelData: wireBanditModelData{
+			Gamma:                  b.gamma,
+			DefaultActionScore:     b.defaultActionScore,
+			ActionProbabilityFloor: b.actionProbabilityFloor,
+			Coefficients:           make(map[string]wireBanditCoefficients, len(b.actions)),
+		},
+	}
+	for key, action := range b.actions {
+		bandit.ModelData.Coefficients[key] = action.build()
+	}
+	return bandit
+}
+
+// BanditActionBuilder builds coefficients of a single bandit action.
+type BanditActionBuilder struct {
+	key                string
+	intercept          float64
+	subjectNumeric     []wireNumericCoefficient
+	subjectCategorical []wireCategoricalCoefficient
+	actionNumeric      []wireNumericCoefficient
+	actionCategorical  []wireCategoricalCoefficient
+}
+
+// NewBanditAction creates action coefficients to be added with
+// BanditBuilder.Action.
+func NewBanditAction(key string, intercept float64) *BanditActionBuilder {
+	return &BanditActionBuilder{key: key, intercept: intercept}
+}
+
+// SubjectNumeric adds a coefficient of a numeric subject attribute.
+// `missing` is used if the subject does not have the attribute.
+func (a *BanditActionBuilder) SubjectNumeric(attribute string, coefficient, missing float64) *BanditActionBuilder {
+	a.subjectNumeric = append(a.subjectNumeric, wireNumericCoefficient{
+		AttributeKey:            attribute,
+		Coefficient:             coefficient,
+		MissingValueCoefficient: missing,
+	})
+	return a
+}
+
+// SubjectCategorical adds coefficients of a categorical subject
+// attribute per attribute value. `missing` is used if the subject
+// does not have the attribute or its value is not listed.
+func (a *BanditActionBuilder) SubjectCategorical(attribute string, values map[string]float64, missing float64) *BanditActionBuilder {
+	a.subjectCategorical = append(a.subjectCategorical, wireCategoricalCoefficient{
+		AttributeKey:            attribute,
+		ValueCoefficients:       values,
+		MissingValueCoefficient: missing,
+	})
+	return a
+}
+
+// ActionNumeric adds a coefficient of a numeric action attribute.
+func (a *BanditActionBuilder) ActionNumeric(attribute string, coefficient, missing float64) *BanditActionBuilder {
+	a.actionNumeric = append(a.actionNumeric, wireNumericCoefficient{
+		AttributeKey:            attribute,
+		Coefficient:             coefficient,
+		MissingValueCoefficient: missing,
+	})
+	return a
+}
+
+// ActionCategorical adds coefficients of a categorical action
+// attribute per attribute value.
+func (a *BanditActionBuilder) ActionCategorical(attribute string, values map[string]float64, missing float64) *BanditActionBuilder {
+	a.actionCategorical = append(a.actionCategorical, wireCategoricalCoefficient{
+		AttributeKey:            attribute,
+		ValueCoefficients:       values,
+		MissingValueCoefficient: missing,
+	})
+	return a
+}
+
+func (a *BanditActionBuilder) build() wireBanditCoefficients {
+	return wireBanditCoefficients{
+		ActionKey:                      a.key,
+		Intercept:                      a.intercept,
+		SubjectNumericCoefficients:     sortedNumeric(a.subjectNumeric),
+		SubjectCategoricalCoefficients: sortedCategorical(a.subjectCategorical),
+		ActionNumericCoefficients:      sortedNumeric(a.actionNumeric),
+		ActionCategoricalCoefficients:  sortedCategorical(a.actionCategorical),
+	}
+}
+
+func sortedNumeric(coefficients []wireNumericCoefficient) []wireNumericCoefficient {
+	result := append([]wireNumericCoefficient{}, coefficients...)
+	sort.SliceStable(result, func(i, j int) bool { return result[i].AttributeKey < result[j].AttributeKey })
+	return result
+}
+
+func sortedCategorical(coefficients []wireCategoricalCoefficient) []wireCategoricalCoefficient {
+	result := append([]wireCategoricalCoefficient{}, coefficients...)
+	sort.SliceStable(result, func(i, j int) bool { return result[i].AttributeKey < result[j].AttributeKey })
+	return result
+}
@@ -0,0 +1,167 @@
+// Package configbuilder constructs Eppo flag and bandit
+// configurations in Go code and serializes them to the wire format
+// served by Eppo, so tests and local development do not need
+// hand-written configuration JSON.
+//
+//	b := configbuilder.New()
+//	b.Flag("new-checkout", eppoclient.VariationTypeBoolean).
+//		Variation("on", true).
+//		Variation("off", false).
+//		Allocation(configbuilder.NewAllocation("internal").
+//			Rule(configbuilder.OneOf("email", "alice@example.com")).
+//			Serve("on")).
+//		Allocation(configbuilder.NewAllocation("rollout").
+//			Split("on", 20).
+//			Split("off", 80))
+//
+//	flagsJSON, banditsJSON, err := b.Build()
+//	client, err := eppoclient.InitClientFromConfiguration(config, flagsJSON, banditsJSON)
+package configbuilder
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/Eppo-exp/golang-sdk/v6/eppoclient"
+)
+
+// DefaultTotalShards is the number of shards of flags built without
+// FlagBuilder.TotalShards, same as used by Eppo.
+const DefaultTotalShards = 10000
+
+// Builder builds flag and bandit configurations. The zero value is
+// an empty builder ready to use.
+type Builder struct {
+	flags     []*FlagBuilder
+	bandits   []*BanditBuilder
+	createdAt time.Time
+}
+
+// New returns an empty builder.
+func New() *Builder {
+	return &Builder{}
+}
+
+// Flag adds a flag and returns its builder. Adding a flag with the
+// same key again replaces the previous one.
+func (b *Builder) Flag(key string, variationType eppoclient.VariationType) *FlagBuilder {
+	flag := &FlagBuilder{
+		key:           key,
+		variationType: variationType,
+		enabled:       true,
+		totalShards:   DefaultTotalShards,
+	}
+	for i, f := range b.flags {
+		if f.key == key {
+			b.flags[i] = flag
+			return flag
+		}
+	}
+	b.flags = append(b.flags, flag)
+	return flag
+}
+
+// Bandit adds a bandit model and returns its builder. Adding a bandit
+// with the same key again replaces the previous one.
+func (b *Builder) Bandit(key, modelVersion string) *BanditBuilder {
+	bandit := &BanditBuilder{
+		key:          key,
+		modelName:    "falcon",
+		modelVersion: modelVersion,
+		gamma:        1.0,
+		actions:      map[string]*BanditActionBuilder{},
+	}
+	for i, existing := range b.bandits {
+		if existing.key == key {
+			b.bandits[i] = bandit
+			return bandit
+		}
+	}
+	b.bandits = append(b.bandits, bandit)
+	return bandit
+}
+
+// CreatedAt sets the creation time reported in configuration
+// metadata. Defaults to the zero time, so the output is
+// deterministic.
+func (b *Builder) CreatedAt(t time.Time) *Builder {
+	b.createdAt = t
+	return b
+}
+
+// Build serializes the configuration. `banditsJSON` is nil if no
+// bandits have been added.
+func (b *Builder) Build() (flagsJSON []byte, banditsJSON []byte, err error) {
+	flagsJSON, err = b.FlagsJSON()
+	if err != nil {
+		return nil, nil, err
+	}
+	banditsJSON, err = b.BanditsJSON()
+	if err != nil {
+		return nil, nil, err
+	}
+	return flagsJSON, banditsJSON, nil
+}
+
+// MustBuild is the same as Build but panics on error. Intended for
+// tests.
+func (b *Builder) MustBuild() (flagsJSON []byte, banditsJSON []byte) {
+	flagsJSON, banditsJSON, err := b.Build()
+	if err != nil {
+		panic(err)
+	}
+	return flagsJSON, banditsJSON
+}
+
+// FlagsJSON serializes flags in the format served at the flags
+// configuration endpoint.
+func (b *Builder) FlagsJSON() ([]byte, error) {
+	response := wireConfigResponse{
+		CreatedAt: formatTime(b.createdAt),
+		Format:    "SERVER",
+		Flags:     make(map[string]wireFlag, len(b.flags)),
+	}
+	for _, f := range b.flags {
+		flag, err := f.build()
+		if err != nil {
+			return nil, fmt.Errorf("flag %q: %w", f.key, err)
+		}
+		response.Flags[f.key] = flag
+
+		for _, bv := range f.banditVariations {
+			if response.Bandits == nil {
+				response.Bandits = map[string][]wireBanditVariation{}
+			}
+			response.Bandits[bv.Key] = append(response.Bandits[bv.Key], bv)
+		}
+	}
+	for _, variations := range response.Bandits {
+		sort.Slice(variations, func(i, j int) bool {
+			return variations[i].FlagKey < variations[j].FlagKey
+		})
+	}
+	return json.Marshal(response)
+}
+
+// BanditsJSON serializes bandit models in the format served at the
+// bandits configuration endpoint. Returns nil if no bandits have been
+// added.
+func (b *Builder) BanditsJSON() ([]byte, error) {
+	if len(b.bandits) == 0 {
+		return nil, nil
+	}
+	response := wireBanditResponse{
+		UpdatedAt: formatTime(b.createdAt),
+		Bandits:   make(map[string]wireBandit, len(b.bandits)),
+	}
+	for _, bandit := range b.bandits {
+		response.Bandits[bandit.key] = bandit.build(b.createdAt)
+	}
+	return json.Marshal(response)
+}
+
+func formatTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339)
+}
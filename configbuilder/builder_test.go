@@ -0,0 +1,224 @@
+package configbuilder
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/Eppo-exp/golang-sdk/v6/eppoclient"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+func initTestClient(t *testing.T, b *Builder) *eppoclient.EppoClient {
+	flagsJSON, banditsJSON, err := b.Build()
+	assert.NoError(t, err)
+	client, err := eppoclient.InitClientFromConfiguration(eppoclient.Config{
+		ApplicationLogger: eppoclient.NewZapLogger(zap.NewNop()),
+	}, flagsJSON, banditsJSON)
+	assert.NoError(t, err)
+	return client
+}
+
+func Test_Builder_rulesAndSplits(t *testing.T) {
+	b := New()
+	b.Flag("flag", eppoclient.VariationTypeString).
+		Variation("internal", "internal").
+		Variation("on", "on").
+		Variation("off", "off").
+		Allocation(NewAllocation("internal-users").
+			Rule(OneOf("email", "alice@example.com")).
+			Rule(GTE("age", 100), Matches("country", "^U")).
+			Serve("internal")).
+		Allocation(NewAllocation("rollout").
+			Split("on", 20).
+			Split("off", 80))
+	client := initTestClient(t, b)
+
+	value, err := client.GetStringAssignment("flag", "alice", eppoclient.Attributes{"email": "alice@example.com"}, "default")
+	assert.NoError(t, err)
+	assert.Equal(t, "internal", value)
+
+	value, err = client.GetStringAssignment("flag", "bob", eppoclient.Attributes{"age": 101, "country": "US"}, "default")
+	assert.NoError(t, err)
+	assert.Equal(t, "internal", value)
+
+	counts := map[string]int{}
+	for i := 0; i < 10000; i++ {
+		value, err := client.GetStringAssignment("flag", fmt.Sprintf("subject-%d", i), eppoclient.Attributes{}, "default")
+		assert.NoError(t, err)
+		counts[value]++
+	}
+	assert.InDelta(t, 2000, counts["on"], 200)
+	assert.InDelta(t, 8000, counts["off"], 200)
+	assert.Equal(t, 10000, counts["on"]+counts["off"])
+}
+
+func Test_Builder_exposureAndTimeWindow(t *testing.T) {
+	now := time.Now()
+	b := New()
+	b.Flag("flag", eppoclient.VariationTypeInteger).
+		Variation("one", 1).
+		Variation("two", 2).
+		Variation("three", int64(3)).
+		Allocation(NewAllocation("expired").
+			Between(now.Add(-2*time.Hour), now.Add(-time.Hour)).
+			Serve("three")).
+		Allocation(NewAllocation("half").
+			Exposure(50).
+			Serve("one")).
+		Allocation(NewAllocation("rest").
+			Between(now.Add(-time.Hour), time.Time{}).
+			Serve("two"))
+	client := initTestClient(t, b)
+
+	counts := map[int64]int{}
+	for i := 0; i < 10000; i++ {
+		value, err := client.GetIntegerAssignment("flag", fmt.Sprintf("subject-%d", i), eppoclient.Attributes{}, 0)
+		assert.NoError(t, err)
+		counts[value]++
+	}
+	assert.InDelta(t, 5000, counts[1], 300)
+	assert.InDelta(t, 5000, counts[2], 300)
+	assert.Zero(t, counts[3])
+}
+
+func Test_Builder_variationTypes(t *testing.T) {
+	b := New()
+	b.Flag("json", eppoclient.VariationTypeJSON).
+		Variation("object", map[string]interface{}{"a": 1}).
+		Allocation(NewAllocation("all").Serve("object"))
+	b.Flag("raw-json", eppoclient.VariationTypeJSON).
+		Variation("raw", []byte(`[1, 2]`)).
+		Allocation(NewAllocation("all").Serve("raw"))
+	b.Flag("numeric", eppoclient.VariationTypeNumeric).
+		Variation("pi", 3.14).
+		Allocation(NewAllocation("all").DoLog(false).Serve("pi"))
+	b.Flag("disabled", eppoclient.VariationTypeBoolean).
+		Variation("on", true).
+		Allocation(NewAllocation("all").Serve("on")).
+		Disabled()
+	client := initTestClient(t, b)
+
+	jsonValue, err := client.GetJSONAssignment("json", "subject", eppoclient.Attributes{}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"a": 1.0}, jsonValue)
+
+	rawJSON, err := client.GetJSONBytesAssignment("raw-json", "subject", eppoclient.Attributes{}, nil)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `[1, 2]`, string(rawJSON))
+
+	number, err := client.GetNumericAssignment("numeric", "subject", eppoclient.Attributes{}, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, 3.14, number)
+
+	enabled, err := client.GetBoolAssignment("disabled", "subject", eppoclient.Attributes{}, false)
+	assert.ErrorIs(t, err, eppoclient.ErrFlagNotEnabled)
+	assert.False(t, enabled)
+}
+
+func Test_Builder_bandits(t *testing.T) {
+	b := New()
+	b.Flag("bandit-flag", eppoclient.VariationTypeString).
+		Variation("control", "control").
+		Bandit("bandit", "shoe-bandit").
+		Allocation(NewAllocation("all").Serve("bandit"))
+	b.Bandit("shoe-bandit", "v1").
+		Gamma(100).
+		Action(NewBanditAction("nike", 1).
+			SubjectNumeric("age", 0.1, 0).
+			SubjectCategorical("country", map[string]float64{"US": 1}, 0)).
+		Action(NewBanditAction("adidas", 0).
+			ActionNumeric("price", -0.01, 0).
+			ActionCategorical("color", map[string]float64{"red": 0.5}, 0))
+	client := initTestClient(t, b)
+
+	result := client.GetBanditAction("bandit-flag", "subject",
+		eppoclient.ContextAttributes{Numeric: map[string]float64{"age": 30}, Categorical: map[string]string{"country": "US"}},
+		map[string]eppoclient.ContextAttributes{"nike": {}, "adidas": {Numeric: map[string]float64{"price": 100}}},
+		"default")
+	assert.Equal(t, "shoe-bandit", result.Variation)
+	if assert.NotNil(t, result.Action) {
+		assert.Equal(t, "nike", *result.Action)
+	}
+}
+
+func Test_Builder_wireFormat(t *testing.T) {
+	b := New()
+	b.Flag("flag", eppoclient.VariationTypeJSON).
+		Variation("a", map[string]int{"x": 1}).
+		Variation("b", map[string]int{"x": 2}).
+		Variation("c", map[string]int{"x": 3}).
+		Allocation(NewAllocation("allocation").
+			Salt("salt").
+			Exposure(25).
+			Split("a", 1).
+			Split("b", 1).
+			Split("c", 1))
+
+	flagsJSON, err := b.FlagsJSON()
+	assert.NoError(t, err)
+
+	var response struct {
+		Flags map[string]struct {
+			Variations  map[string]wireVariation
+			Allocations []wireAllocation
+		}
+	}
+	assert.NoError(t, json.Unmarshal(flagsJSON, &response))
+	flag := response.Flags["flag"]
+
+	// JSON variations are encoded as strings.
+	assert.JSONEq(t, `"{\"x\":1}"`, string(flag.Variations["a"].Value))
+
+	splits := flag.Allocations[0].Splits
+	traffic := wireShard{Salt: "salt-traffic", Ranges: []wireShardRange{{Start: 0, End: 2500}}}
+	assert.Equal(t, []wireShard{{Salt: "salt-split", Ranges: []wireShardRange{{Start: 0, End: 3333}}}, traffic}, splits[0].Shards)
+	assert.Equal(t, []wireShard{{Salt: "salt-split", Ranges: []wireShardRange{{Start: 3333, End: 6667}}}, traffic}, splits[1].Shards)
+	assert.Equal(t, []wireShard{{Salt: "salt-split", Ranges: []wireShardRange{{Start: 6667, End: 10000}}}, traffic}, splits[2].Shards)
+
+	banditsJSON, err := b.BanditsJSON()
+	assert.NoError(t, err)
+	assert.Nil(t, banditsJSON)
+}
+
+func Test_Builder_errors(t *testing.T) {
+	tests := map[string]func(b *Builder){
+		"unknown variation": func(b *Builder) {
+			b.Flag("flag", eppoclient.VariationTypeBoolean).
+				Variation("on", true).
+				Allocation(NewAllocation("all").Serve("off"))
+		},
+		"mismatching type": func(b *Builder) {
+			b.Flag("flag", eppoclient.VariationTypeInteger).Variation("on", 1.5)
+		},
+		"serve and split": func(b *Builder) {
+			b.Flag("flag", eppoclient.VariationTypeBoolean).
+				Variation("on", true).
+				Allocation(NewAllocation("all").Serve("on").Split("on", 1))
+		},
+		"no splits": func(b *Builder) {
+			b.Flag("flag", eppoclient.VariationTypeBoolean).
+				Variation("on", true).
+				Allocation(NewAllocation("all"))
+		},
+		"bandit on non-string flag": func(b *Builder) {
+			b.Flag("flag", eppoclient.VariationTypeBoolean).Bandit("bandit", "bandit")
+		},
+		"invalid exposure": func(b *Builder) {
+			b.Flag("flag", eppoclient.VariationTypeBoolean).
+				Variation("on", true).
+				Allocation(NewAllocation("all").Exposure(150).Serve("on"))
+		},
+	}
+	for name, setup := range tests {
+		t.Run(name, func(t *testing.T) {
+			b := New()
+			setup(b)
+			_, _, err := b.Build()
+			assert.Error(t, err)
+			assert.Panics(t, func() { b.MustBuild() })
+		})
+	}
+}
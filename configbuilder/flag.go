@@ -0,0 +1,439 @@
+package configbuilder
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/Eppo-exp/golang-sdk/v6/eppoclient"
+)
+
+// FlagBuilder builds a single flag. See Builder.Flag.
+type FlagBuilder struct {
+	key              string
+	variationType    eppoclient.VariationType
+	enabled          bool
+	totalShards      int64
+	variations       []variationEntry
+	allocations      []*AllocationBuilder
+	banditVariations []wireBanditVariation
+	err              error
+}
+
+type variationEntry struct {
+	key   string
+	value interface{}
+}
+
+// Variation adds a variation. `value` must match the variation type
+// of the flag: bool for BOOLEAN, any integer type for INTEGER, any
+// number for NUMERIC, and string for STRING flags. JSON flags accept
+// raw JSON ([]byte or json.RawMessage) or any value that is marshaled
+// to JSON.
+func (f *FlagBuilder) Variation(key string, value interface{}) *FlagBuilder {
+	f.variations = append(f.variations, variationEntry{key: key, value: value})
+	return f
+}
+
+// Bandit adds a variation selecting the bandit `banditKey`, so that
+// GetBanditAction evaluates the bandit when the subject is assigned
+// this variation. Only STRING flags may reference bandits.
+func (f *FlagBuilder) Bandit(variationKey, banditKey string) *FlagBuilder {
+	if f.variationType != eppoclient.VariationTypeString {
+		f.setError(fmt.Errorf("bandit variation %q requires a STRING flag", variationKey))
+		return f
+	}
+	f.variations = append(f.variations, variationEntry{key: variationKey, value: banditKey})
+	f.banditVariations = append(f.banditVariations, wireBanditVariation{
+		Key:            banditKey,
+		FlagKey:        f.key,
+		VariationKey:   variationKey,
+		VariationValue: banditKey,
+	})
+	return f
+}
+
+// Allocation appends an allocation. Allocations are evaluated in the
+// order they are added.
+func (f *FlagBuilder) Allocation(allocation *AllocationBuilder) *FlagBuilder {
+	f.allocations = append(f.allocations, allocation)
+	return f
+}
+
+// Disabled marks the flag as disabled, so the default value is always
+// returned.
+func (f *FlagBuilder) Disabled() *FlagBuilder {
+	f.enabled = false
+	return f
+}
+
+// TotalShards sets the number of shards subjects are hashed into.
+// Defaults to DefaultTotalShards.
+func (f *FlagBuilder) TotalShards(n int64) *FlagBuilder {
+	f.totalShards = n
+	return f
+}
+
+func (f *FlagBuilder) setError(err error) {
+	if f.err == nil {
+		f.err = err
+	}
+}
+
+func (f *FlagBuilder) build() (wireFlag, error) {
+	if f.err != nil {
+		return wireFlag{}, f.err
+	}
+	if f.totalShards <= 0 {
+		return wireFlag{}, fmt.Errorf("total shards must be positive, got %d", f.totalShards)
+	}
+
+	flag := wireFlag{
+		Key:           f.key,
+		Enabled:       f.enabled,
+		VariationType: string(f.variationType),
+		Variations:    make(map[string]wireVariation, len(f.variations)),
+		Allocations:   make([]wireAllocation, 0, len(f.allocations)),
+		TotalShards:   f.totalShards,
+	}
+
+	for _, v := range f.variations {
+		value, err := encodeVariationValue(f.variationType, v.value)
+		if err != nil {
+			return wireFlag{}, fmt.Errorf("variation %q: %w", v.key, err)
+		}
+		flag.Variations[v.key] = wireVariation{Key: v.key, Value: value}
+	}
+
+	for _, a := range f.allocations {
+		allocation, err := a.build(f.key, f.totalShards)
+		if err != nil {
+			return wireFlag{}, fmt.Errorf("allocation %q: %w", a.key, err)
+		}
+		for _, s := range allocation.Splits {
+			if _, ok := flag.Variations[s.VariationKey]; !ok {
+				return wireFlag{}, fmt.Errorf("allocation %q: unknown variation %q", a.key, s.VariationKey)
+			}
+		}
+		flag.Allocations = append(flag.Allocations, allocation)
+	}
+
+	return flag, nil
+}
+
+// encodeVariationValue encodes `value` as found in the wire format.
+// JSON variations are encoded as strings containing JSON.
+func encodeVariationValue(variationType eppoclient.VariationType, value interface{}) (json.RawMessage, error) {
+	switch variationType {
+	case eppoclient.VariationTypeBoolean:
+		if _, ok := value.(bool); !ok {
+			return nil, fmt.Errorf("expected bool, got %T", value)
+		}
+	case eppoclient.VariationTypeString:
+		if _, ok := value.(string); !ok {
+			return nil, fmt.Errorf("expected string, got %T", value)
+		}
+	case eppoclient.VariationTypeInteger:
+		f, ok := toFloat64(value)
+		if !ok || f != math.Trunc(f) {
+			return nil, fmt.Errorf("expected integer, got %v (%T)", value, value)
+		}
+	case eppoclient.VariationTypeNumeric:
+		if _, ok := toFloat64(value); !ok {
+			return nil, fmt.Errorf("expected number, got %T", value)
+		}
+	case eppoclient.VariationTypeJSON:
+		var raw []byte
+		switch v := value.(type) {
+		case []byte:
+			raw = v
+		case json.RawMessage:
+			raw = v
+		default:
+			var err error
+			raw, err = json.Marshal(v)
+			if err != nil {
+				return nil, err
+			}
+		}
+		if !json.Valid(raw) {
+			return nil, fmt.Errorf("invalid JSON: %s", raw)
+		}
+		value = string(raw)
+	default:
+		return nil, fmt.Errorf("unknown variation type %q", variationType)
+	}
+	return json.Marshal(value)
+}
+
+func toFloat64(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case int:
+		return float64(v), true
+	case int8:
+		return float64(v), true
+	case int16:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case uint:
+		return float64(v), true
+	case uint8:
+		return float64(v), true
+	case uint16:
+		return float64(v), true
+	case uint32:
+		return float64(v), true
+	case uint64:
+		return float64(v), true
+	case float32:
+		return float64(v), true
+	case float64:
+		return v, true
+	}
+	return 0, false
+}
+
+// AllocationBuilder builds an allocation: rules selecting subjects
+// and splits assigning them to variations.
+type AllocationBuilder struct {
+	key          string
+	rules        [][]Condition
+	splits       []splitEntry
+	serve        string
+	exposure     float64
+	startAt      time.Time
+	endAt        time.Time
+	doLog        *bool
+	salt         string
+	extraLogging map[string]string
+}
+
+type splitEntry struct {
+	variationKey string
+	weight       float64
+}
+
+// NewAllocation creates an allocation to be added with
+// FlagBuilder.Allocation. Without rules, the allocation matches all
+// subjects.
+func NewAllocation(key string) *AllocationBuilder {
+	return &AllocationBuilder{key: key, exposure: 100}
+}
+
+// Rule adds a rule matching subjects that satisfy all `conditions`.
+// The allocation matches subjects satisfying any of its rules.
+func (a *AllocationBuilder) Rule(conditions ...Condition) *AllocationBuilder {
+	a.rules = append(a.rules, conditions)
+	return a
+}
+
+// Serve assigns all matching subjects to the variation. Mutually
+// exclusive with Split.
+func (a *AllocationBuilder) Serve(variationKey string) *AllocationBuilder {
+	a.serve = variationKey
+	return a
+}
+
+// Split assigns a share of matching subjects proportional to
+// `weight` to the variation, e.g., Split("on", 20).Split("off", 80).
+// Weights are relative and do not need to add up to 100.
+func (a *AllocationBuilder) Split(variationKey string, weight float64) *AllocationBuilder {
+	a.splits = append(a.splits, splitEntry{variationKey: variationKey, weight: weight})
+	return a
+}
+
+// Exposure limits the allocation to `percent` (0-100) of matching
+// subjects. Subjects outside of exposure fall through to the next
+// allocation. Defaults to 100.
+func (a *AllocationBuilder) Exposure(percent float64) *AllocationBuilder {
+	a.exposure = percent
+	return a
+}
+
+// Between limits the allocation to the time window [startAt, endAt].
+// Zero time leaves the corresponding end of the window open.
+func (a *AllocationBuilder) Between(startAt, endAt time.Time) *AllocationBuilder {
+	a.startAt, a.endAt = startAt, endAt
+	return a
+}
+
+// DoLog sets whether assignments from this allocation are logged.
+// Assignments are logged by default.
+func (a *AllocationBuilder) DoLog(doLog bool) *AllocationBuilder {
+	a.doLog = &doLog
+	return a
+}
+
+// Salt sets the salt used for hashing subjects into shards. Defaults
+// to "<flag key>-<allocation key>". Changing the salt reshuffles
+// subjects between splits.
+func (a *AllocationBuilder) Salt(salt string) *AllocationBuilder {
+	a.salt = salt
+	return a
+}
+
+// ExtraLogging sets extra fields included in assignment events of
+// this allocation.
+func (a *AllocationBuilder) ExtraLogging(extraLogging map[string]string) *AllocationBuilder {
+	a.extraLogging = extraLogging
+	return a
+}
+
+func (a *AllocationBuilder) build(flagKey string, totalShards int64) (wireAllocation, error) {
+	allocation := wireAllocation{
+		Key:   a.key,
+		Rules: make([]wireRule, 0, len(a.rules)),
+		DoLog: a.doLog,
+	}
+	if !a.startAt.IsZero() {
+		allocation.StartAt = formatTime(a.startAt)
+	}
+	if !a.endAt.IsZero() {
+		allocation.EndAt = formatTime(a.endAt)
+	}
+
+	for _, conditions := range a.rules {
+		allocation.Rules = append(allocation.Rules, wireRule{Conditions: conditions})
+	}
+
+	salt := a.salt
+	if salt == "" {
+		salt = flagKey + "-" + a.key
+	}
+
+	if a.exposure < 0 || a.exposure > 100 {
+		return wireAllocation{}, fmt.Errorf("exposure must be within [0, 100], got %v", a.exposure)
+	}
+	var exposureShards []wireShard
+	if a.exposure < 100 {
+		exposureShards = []wireShard{{
+			Salt:   salt + "-traffic",
+			Ranges: []wireShardRange{{Start: 0, End: percentToShards(a.exposure, totalShards)}},
+		}}
+	}
+
+	switch {
+	case a.serve != "" && len(a.splits) > 0:
+		return wireAllocation{}, fmt.Errorf("both Serve and Split are used")
+	case a.serve != "":
+		allocation.Splits = []wireSplit{{
+			VariationKey: a.serve,
+			Shards:       append([]wireShard{}, exposureShards...),
+			ExtraLogging: a.extraLogging,
+		}}
+	case len(a.splits) > 0:
+		ranges, err := splitRanges(a.splits, totalShards)
+		if err != nil {
+			return wireAllocation{}, err
+		}
+		for i, s := range a.splits {
+			allocation.Splits = append(allocation.Splits, wireSplit{
+				VariationKey: s.variationKey,
+				Shards: append([]wireShard{{
+					Salt:   salt + "-split",
+					Ranges: []wireShardRange{ranges[i]},
+				}}, exposureShards...),
+				ExtraLogging: a.extraLogging,
+			})
+		}
+	default:
+		return wireAllocation{}, fmt.Errorf("no variation to serve: use Serve or Split")
+	}
+
+	return allocation, nil
+}
+
+// splitRanges converts relative weights into consecutive shard ranges
+// covering all shards. Boundaries are rounded to the nearest shard.
+func splitRanges(splits []splitEntry, totalShards int64) ([]wireShardRange, error) {
+	var total float64
+	for _, s := range splits {
+		if s.weight < 0 {
+			return nil, fmt.Errorf("split %q: negative weight %v", s.variationKey, s.weight)
+		}
+		total += s.weight
+	}
+	if total == 0 {
+		return nil, fmt.Errorf("split weights add up to zero")
+	}
+
+	ranges := make([]wireShardRange, len(splits))
+	var cumulative float64
+	var start int64
+	for i, s := range splits {
+		cumulative += s.weight
+		end := int64(math.Round(cumulative / total * float64(totalShards)))
+		if i == len(splits)-1 {
+			end = totalShards
+		}
+		ranges[i] = wireShardRange{Start: start, End: end}
+		start = end
+	}
+	return ranges, nil
+}
+
+func percentToShards(percent float64, totalShards int64) int64 {
+	return int64(math.Round(percent / 100 * float64(totalShards)))
+}
+
+// Condition is a single condition of an allocation rule.
+type Condition struct {
+	Attribute string      `json:"attribute"`
+	Operator  string      `json:"operator"`
+	Value     interface{} `json:"value"`
+}
+
+// OneOf matches subjects whose attribute equals one of `values`.
+func OneOf(attribute string, values ...string) Condition {
+	return Condition{Attribute: attribute, Operator: "ONE_OF", Value: values}
+}
+
+// NotOneOf matches subjects whose attribute equals none of `values`.
+func NotOneOf(attribute string, values ...string) Condition {
+	return Condition{Attribute: attribute, Operator: "NOT_ONE_OF", Value: values}
+}
+
+// Matches matches subjects whose attribute matches the regular
+// expression.
+func Matches(attribute, regex string) Condition {
+	return Condition{Attribute: attribute, Operator: "MATCHES", Value: regex}
+}
+
+// NotMatches matches subjects whose attribute does not match the
+// regular expression.
+func NotMatches(attribute, regex string) Condition {
+	return Condition{Attribute: attribute, Operator: "NOT_MATCHES", Value: regex}
+}
+
+// GT matches subjects whose attribute is greater than `value`.
+// `value` is either a number or a semantic version string.
+func GT(attribute string, value interface{}) Condition {
+	return Condition{Attribute: attribute, Operator: "GT", Value: value}
+}
+
+// GTE matches subjects whose attribute is greater than or equal to
+// `value`. See GT.
+func GTE(attribute string, value interface{}) Condition {
+	return Condition{Attribute: attribute, Operator: "GTE", Value: value}
+}
+
+// LT matches subjects whose attribute is less than `value`. See GT.
+func LT(attribute string, value interface{}) Condition {
+	return Condition{Attribute: attribute, Operator: "LT", Value: value}
+}
+
+// LTE matches subjects whose attribute is less than or equal to
+// `value`. See GT.
+func LTE(attribute string, value interface{}) Condition {
+	return Condition{Attribute: attribute, Operator: "LTE", Value: value}
+}
+
+// IsNull matches subjects whose attribute is missing (or not
+// missing, if `isNull` is false).
+func IsNull(attribute string, isNull bool) Condition {
+	return Condition{Attribute: attribute, Operator: "IS_NULL", Value: isNull}
+}
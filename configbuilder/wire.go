@@ -0,0 +1,107 @@
+package configbuilder
+
+import "encoding/json"
+
+// Wire format of the flags configuration endpoint. Mirrors
+// configResponse of package eppoclient.
+
+type wireConfigResponse struct {
+	CreatedAt string                           `json:"createdAt"`
+	Format    string                           `json:"format"`
+	Flags     map[string]wireFlag              `json:"flags"`
+	Bandits   map[string][]wireBanditVariation `json:"bandits,omitempty"`
+}
+
+type wireFlag struct {
+	Key           string                   `json:"key"`
+	Enabled       bool                     `json:"enabled"`
+	VariationType string                   `json:"variationType"`
+	Variations    map[string]wireVariation `json:"variations"`
+	Allocations   []wireAllocation         `json:"allocations"`
+	TotalShards   int64                    `json:"totalShards"`
+}
+
+type wireVariation struct {
+	Key   string          `json:"key"`
+	Value json.RawMessage `json:"value"`
+}
+
+type wireAllocation struct {
+	Key     string      `json:"key"`
+	Rules   []wireRule  `json:"rules"`
+	StartAt string      `json:"startAt,omitempty"`
+	EndAt   string      `json:"endAt,omitempty"`
+	Splits  []wireSplit `json:"splits"`
+	DoLog   *bool       `json:"doLog,omitempty"`
+}
+
+type wireRule struct {
+	Conditions []Condition `json:"conditions"`
+}
+
+type wireSplit struct {
+	Shards       []wireShard       `json:"shards"`
+	VariationKey string            `json:"variationKey"`
+	ExtraLogging map[string]string `json:"extraLogging,omitempty"`
+}
+
+type wireShard struct {
+	Salt   string           `json:"salt"`
+	Ranges []wireShardRange `json:"ranges"`
+}
+
+type wireShardRange struct {
+	Start int64 `json:"start"`
+	End   int64 `json:"end"`
+}
+
+type wireBanditVariation struct {
+	Key            string `json:"key"`
+	FlagKey        string `json:"flagKey"`
+	VariationKey   string `json:"variationKey"`
+	VariationValue string `json:"variationValue"`
+}
+
+// Wire format of the bandits configuration endpoint. Mirrors
+// banditResponse of package eppoclient.
+
+type wireBanditResponse struct {
+	Bandits   map[string]wireBandit `json:"bandits"`
+	UpdatedAt string                `json:"updatedAt"`
+}
+
+type wireBandit struct {
+	BanditKey    string              `json:"banditKey"`
+	ModelName    string              `json:"modelName"`
+	ModelVersion string              `json:"modelVersion"`
+	ModelData    wireBanditModelData `json:"modelData"`
+	UpdatedAt    string              `json:"updatedAt"`
+}
+
+type wireBanditModelData struct {
+	Gamma                  float64                           `json:"gamma"`
+	DefaultActionScore     float64                           `json:"defaultActionScore"`
+	ActionProbabilityFloor float64                           `json:"actionProbabilityFloor"`
+	Coefficients           map[string]wireBanditCoefficients `json:"coefficients"`
+}
+
+type wireBanditCoefficients struct {
+	ActionKey                      string                       `json:"actionKey"`
+	Intercept                      float64                      `json:"intercept"`
+	SubjectNumericCoefficients     []wireNumericCoefficient     `json:"subjectNumericCoefficients"`
+	SubjectCategoricalCoefficients []wireCategoricalCoefficient `json:"subjectCategoricalCoefficients"`
+	ActionNumericCoefficients      []wireNumericCoefficient     `json:"actionNumericCoefficients"`
+	ActionCategoricalCoefficients  []wireCategoricalCoefficient `json:"actionCategoricalCoefficients"`
+}
+
+type wireNumericCoefficient struct {
+	AttributeKey            string  `json:"attributeKey"`
+	Coefficient             float64 `json:"coefficient"`
+	MissingValueCoefficient float64 `json:"missingValueCoefficient"`
+}
+
+type wireCategoricalCoefficient struct {
+	AttributeKey            string             `json:"attributeKey"`
+	ValueCoefficients       map[string]float64 `json:"valueCoefficients"`
+	MissingValueCoefficient float64            `json:"missingValueCoefficient"`
+}
@@ -0,0 +1,124 @@
+package eppoclient
+
+import (
+	"context"
+	"fmt"
+)
+
+// AssignmentResult is the assignment of a single flag returned by
+// GetAllAssignments.
+type AssignmentResult struct {
+	// Value type depends on VariationType:
+	// - STRING -> string
+	// - INTEGER -> int64
+	// - NUMERIC -> float64
+	// - BOOLEAN -> bool
+	// - JSON -> interface{} (as returned by GetJSONAssignment)
+	Value interface{}
+	// Empty if the value has been set by a local override or a
+	// hook.
+	VariationKey  string
+	AllocationKey string
+	VariationType VariationType
+}
+
+type getAllAssignmentsOptions struct {
+	disableLogging bool
+}
+
+// GetAllAssignmentsOption customizes GetAllAssignments behavior.
+type GetAllAssignmentsOption func(*getAllAssignmentsOptions)
+
+// WithoutAssignmentLogging disables logging of assignments made by
+// GetAllAssignments.
+func WithoutAssignmentLogging() GetAllAssignmentsOption {
+	return func(o *getAllAssignmentsOptions) {
+		o.disableLogging = true
+	}
+}
+
+// AssignmentLoggingDisabled reports whether `opts` include
+// WithoutAssignmentLogging. It's meant for implementations of Client
+// other than EppoClient.
+func AssignmentLoggingDisabled(opts ...GetAllAssignmentsOption) bool {
+	var options getAllAssignmentsOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+	return options.disableLogging
+}
+
+// GetAllAssignments evaluates all enabled flags for the subject
+// against a single configuration snapshot.
+//
+// Returns a map from flag key to assignment result. Flags the subject
+// is not assigned to (e.g., the flag is disabled or no allocation
+// matched) are omitted.
+//
+// Each flag goes through the same pipeline as the typed getters:
+// local overrides, hooks, metrics, and tracing apply to every enabled
+// flag.
+// Assignments are logged through the assignment logger unless
+// WithoutAssignmentLogging option is passed.
+//
+// Returns ErrObfuscatedConfiguration if the configuration is
+// obfuscated.
+func (ec *EppoClient) GetAllAssignments(
+	ctx context.Context,
+	subjectKey string,
+	subjectAttributes Attributes,
+	opts ...GetAllAssignmentsOption,
+) (map[string]AssignmentResult, error) {
+	var options getAllAssignmentsOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	if ec.closed.Load() {
+		return nil, ErrClientClosed
+	}
+	if subjectKey == "" {
+		return nil, fmt.Errorf("no subject key provided")
+	}
+
+	config := ec.configurationStore.getConfiguration()
+	if config.flags.isObfuscated() {
+		return nil, ErrObfuscatedConfiguration
+	}
+
+	assignOpts := assignOptions{
+		logging: !options.disableLogging,
+		bulk:    true,
+		// Augment attributes once instead of once per flag.
+		augmentedSubjectAttributes: augmentWithSubjectKey(subjectAttributes, subjectKey),
+	}
+	results := make(map[string]AssignmentResult, len(config.flags.Flags))
+	for flagKey, flag := range config.flags.Flags {
+		if !flag.Enabled {
+			continue
+		}
+		evaluation, err := ec.assign(ctx, config, flagKey, subjectKey, subjectAttributes, flag.VariationType, nil, assignOpts)
+		if err != nil {
+			continue
+		}
+
+		result := AssignmentResult{
+			Value:         evaluation.value,
+			VariationType: flag.VariationType.toPublic(),
+		}
+		if v, ok := evaluation.value.(jsonVariationValue); ok {
+			result.Value = v.Parsed
+		}
+		// Overridden assignments have neither allocation nor
+		// split.
+		if evaluation.allocation != nil {
+			result.AllocationKey = evaluation.allocation.Key
+		}
+		if evaluation.split != nil {
+			result.VariationKey = evaluation.split.VariationKey
+		}
+		results[flagKey] = result
+	}
+
+	return results, nil
+}
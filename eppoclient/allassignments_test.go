@@ -0,0 +1,173 @@
+package eppoclient
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func newAllAssignmentsTestConfiguration() configuration {
+	fullRange := []split{{VariationKey: "on"}}
+	return configuration{flags: configResponse{
+		Flags: map[string]*flagConfiguration{
+			"bool-flag": {
+				Key:           "bool-flag",
+				Enabled:       true,
+				TotalShards:   10000,
+				VariationType: booleanVariation,
+				Variations:    map[string]variation{"on": {Key: "on", Value: []byte(`true`)}},
+				Allocations:   []allocation{{Key: "bool-allocation", Splits: fullRange}},
+			},
+			"json-flag": {
+				Key:           "json-flag",
+				Enabled:       true,
+				TotalShards:   10000,
+				VariationType: jsonVariation,
+				Variations:    map[string]variation{"on": {Key: "on", Value: []byte(`"{\"a\": 1}"`)}},
+				Allocations:   []allocation{{Key: "json-allocation", Splits: fullRange}},
+			},
+			"targeted-flag": {
+				Key:           "targeted-flag",
+				Enabled:       true,
+				TotalShards:   10000,
+				VariationType: stringVariation,
+				Variations:    map[string]variation{"on": {Key: "on", Value: []byte(`"on"`)}},
+				Allocations: []allocation{{
+					Key: "targeted-allocation",
+					Rules: []rule{{Conditions: []condition{
+						{Operator: "ONE_OF", Attribute: "id", Value: []string{"other-subject"}},
+					}}},
+					Splits: fullRange,
+				}},
+			},
+			"disabled-flag": {
+				Key:           "disabled-flag",
+				Enabled:       false,
+				TotalShards:   10000,
+				VariationType: stringVariation,
+				Variations:    map[string]variation{"on": {Key: "on", Value: []byte(`"on"`)}},
+				Allocations:   []allocation{{Key: "allocation", Splits: fullRange}},
+			},
+		},
+	}}
+}
+
+func Test_GetAllAssignments(t *testing.T) {
+	logger := new(mockLogger)
+	logger.Mock.On("LogAssignment", mock.Anything).Return()
+	client := newEppoClient(newConfigurationStoreWithConfig(newAllAssignmentsTestConfiguration()), nil, nil, logger, nil, applicationLogger)
+
+	results, err := client.GetAllAssignments(context.Background(), "subject", Attributes{"country": "US"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]AssignmentResult{
+		"bool-flag": {
+			Value:         true,
+			VariationKey:  "on",
+			AllocationKey: "bool-allocation",
+			VariationType: VariationTypeBoolean,
+		},
+		"json-flag": {
+			Value:         map[string]interface{}{"a": 1.0},
+			VariationKey:  "on",
+			AllocationKey: "json-allocation",
+			VariationType: VariationTypeJSON,
+		},
+	}, results)
+	logger.AssertNumberOfCalls(t, "LogAssignment", 2)
+
+	event := logger.Calls[0].Arguments[0].(AssignmentEvent)
+	assert.Equal(t, Attributes{"country": "US"}, event.SubjectAttributes)
+}
+
+func Test_GetAllAssignments_withoutLogging(t *testing.T) {
+	logger := new(mockLogger)
+	logger.Mock.On("LogAssignment", mock.Anything).Return()
+	client := newEppoClient(newConfigurationStoreWithConfig(newAllAssignmentsTestConfiguration()), nil, nil, logger, nil, applicationLogger)
+
+	results, err := client.GetAllAssignments(context.Background(), "other-subject", Attributes{}, WithoutAssignmentLogging())
+
+	assert.NoError(t, err)
+	assert.Len(t, results, 3)
+	assert.Equal(t, "on", results["targeted-flag"].Value)
+	logger.AssertNotCalled(t, "LogAssignment", mock.Anything)
+}
+
+func Test_GetAllAssignments_unassignedFlagsAreNotErrors(t *testing.T) {
+	core, logs := observer.New(zap.DebugLevel)
+	client := newEppoClient(newConfigurationStoreWithConfig(newAllAssignmentsTestConfiguration()), nil, nil, nil, nil, NewZapLogger(zap.New(core)))
+
+	results, err := client.GetAllAssignments(context.Background(), "subject", Attributes{})
+
+	assert.NoError(t, err)
+	assert.Len(t, results, 2)
+	assert.Zero(t, logs.FilterLevelExact(zap.ErrorLevel).Len())
+	assert.Equal(t, 1, logs.FilterMessage("failed to evaluate flag targeted-flag: "+ErrSubjectAllocation.Error()).Len())
+}
+
+func Test_GetAllAssignments_blankSubject(t *testing.T) {
+	client := newEppoClient(newConfigurationStoreWithConfig(newAllAssignmentsTestConfiguration()), nil, nil, nil, nil, applicationLogger)
+
+	_, err := client.GetAllAssignments(context.Background(), "", Attributes{})
+
+	assert.Error(t, err)
+}
+
+func Test_GetAllAssignments_pipeline(t *testing.T) {
+	var calls []string
+	logger := new(mockLogger)
+	logger.Mock.On("LogAssignment", mock.Anything).Return()
+	client := newHooksTestClient(newAllAssignmentsTestConfiguration(), logger,
+		testHook{name: "hook", calls: &calls, before: func(hookCtx *HookContext) *HookOverride {
+			if hookCtx.FlagKey == "bool-flag" {
+				return &HookOverride{Value: false}
+			}
+			return nil
+		}},
+	)
+	metrics := NewPrometheusMetrics()
+	client.metrics = metrics
+	tracer := &testTracer{}
+	client.tracer = tracer
+
+	results, err := client.GetAllAssignments(context.Background(), "subject", Attributes{})
+
+	assert.NoError(t, err)
+	assert.Equal(t, AssignmentResult{Value: false, VariationType: VariationTypeBoolean}, results["bool-flag"])
+	assert.Equal(t, "json-allocation", results["json-flag"].AllocationKey)
+	assert.Len(t, results, 2)
+	for _, flagKey := range []string{"bool-flag", "json-flag", "targeted-flag"} {
+		assert.Contains(t, calls, "hook.before("+flagKey+")")
+		assert.Contains(t, calls, "hook.finally("+flagKey+")")
+	}
+	// Disabled flags are skipped.
+	assert.NotContains(t, calls, "hook.before(disabled-flag)")
+	assert.Len(t, metrics.evaluations.snapshot(), 3)
+	assert.Len(t, tracer.getSpans(), 3)
+	// The overridden assignment is not logged.
+	logger.AssertNumberOfCalls(t, "LogAssignment", 1)
+}
+
+func Test_GetAllAssignments_overrides(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "overrides.yaml")
+	writeOverridesFile(t, path, overridesTestFile)
+	logger := new(mockLogger)
+	logger.Mock.On("LogAssignment", mock.Anything).Return()
+	client := initOverridesTestClient(t, path, logger, true)
+
+	results, err := client.GetAllAssignments(context.Background(), "alice", Attributes{})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]AssignmentResult{
+		"flag": {Value: "alice-value", VariationType: VariationTypeString},
+	}, results)
+	logger.AssertNumberOfCalls(t, "LogAssignment", 1)
+
+	_, err = client.GetAllAssignments(context.Background(), "alice", Attributes{}, WithoutAssignmentLogging())
+	assert.NoError(t, err)
+	logger.AssertNumberOfCalls(t, "LogAssignment", 1)
+}
@@ -0,0 +1,125 @@
+package eppoclient
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+
+	"go.uber.org/zap"
+)
+
+// Subject is a subject key with attributes, e.g., an entry of a
+// subject sample. See ReadSubjects.
+type Subject struct {
+	Key        string     `json:"key"`
+	Attributes Attributes `json:"attributes,omitempty"`
+}
+
+// ReadSubjects reads a subject sample in JSON Lines format: one
+// object per line with "key" and optional "attributes":
+//
+//	{"key": "alice", "attributes": {"country": "US", "age": 30}}
+//	{"key": "bob"}
+func ReadSubjects(r io.Reader) ([]Subject, error) {
+	var subjects []Subject
+	decoder := json.NewDecoder(r)
+	for {
+		var subject Subject
+		err := decoder.Decode(&subject)
+		if errors.Is(err, io.EOF) {
+			return subjects, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("subject %d: %w", len(subjects)+1, err)
+		}
+		if subject.Key == "" {
+			return nil, fmt.Errorf("subject %d: missing \"key\"", len(subjects)+1)
+		}
+		subjects = append(subjects, subject)
+	}
+}
+
+// FlagImpact summarizes how assignments of a flag change between two
+// configurations for a sample of subjects.
+type FlagImpact struct {
+	FlagKey string `json:"flagKey"`
+	// Number of subjects evaluated.
+	Subjects int `json:"subjects"`
+	// Number of subjects assigned a different variation.
+	Changed int `json:"changed"`
+	// Changed assignments grouped by old and new variation, sorted
+	// by decreasing number of subjects.
+	Transitions []VariationTransition `json:"transitions,omitempty"`
+}
+
+// VariationTransition counts subjects that move from one variation
+// to another. Variation keys are empty for subjects that are not
+// assigned a variation and get the default value.
+type VariationTransition struct {
+	From     string `json:"from"`
+	To       string `json:"to"`
+	Subjects int    `json:"subjects"`
+}
+
+// AnalyzeAssignmentImpact evaluates `subjects` against flags of two
+// configurations (as served at CONFIG_ENDPOINT) and reports, for
+// every flag that differs (see DiffConfigurations), how many subjects
+// would be assigned a different variation.
+//
+// Flags are evaluated at the current time, so allocations scheduled
+// to start or end later do not contribute.
+func AnalyzeAssignmentImpact(oldFlagsJSON, newFlagsJSON []byte, subjects []Subject) ([]FlagImpact, error) {
+	oldConfig, newConfig, err := parseConfigurationPair(oldFlagsJSON, newFlagsJSON)
+	if err != nil {
+		return nil, err
+	}
+
+	logger := NewZapLogger(zap.NewNop())
+	change := diffConfigurations(oldConfig, newConfig)
+	impacts := make([]FlagImpact, len(change.Flags))
+	for i, flagChange := range change.Flags {
+		oldFlag, newFlag := oldConfig.flags.Flags[flagChange.FlagKey], newConfig.flags.Flags[flagChange.FlagKey]
+
+		impact := FlagImpact{FlagKey: flagChange.FlagKey, Subjects: len(subjects)}
+		transitions := make(map[[2]string]int)
+		for _, subject := range subjects {
+			from := assignedVariation(oldFlag, subject, logger)
+			to := assignedVariation(newFlag, subject, logger)
+			if from != to {
+				impact.Changed++
+				transitions[[2]string{from, to}]++
+			}
+		}
+		for t, count := range transitions {
+			impact.Transitions = append(impact.Transitions, VariationTransition{From: t[0], To: t[1], Subjects: count})
+		}
+		sort.Slice(impact.Transitions, func(i, j int) bool {
+			a, b := impact.Transitions[i], impact.Transitions[j]
+			if a.Subjects != b.Subjects {
+				return a.Subjects > b.Subjects
+			}
+			if a.From != b.From {
+				return a.From < b.From
+			}
+			return a.To < b.To
+		})
+		impacts[i] = impact
+	}
+	return impacts, nil
+}
+
+// assignedVariation returns the key of the variation assigned to the
+// subject, or an empty string if the flag is nil or the subject gets
+// the default value.
+func assignedVariation(flag *flagConfiguration, subject Subject, logger ApplicationLogger) string {
+	if flag == nil {
+		return ""
+	}
+	evaluation, err := flag.eval(subject.Key, subject.Attributes, logger, nil)
+	if err != nil {
+		return ""
+	}
+	return evaluation.split.VariationKey
+}
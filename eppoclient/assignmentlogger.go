@@ -21,6 +21,12 @@ type BanditActionLogger interface {
 	LogBanditAction(event BanditEvent)
 }
 
+// LoggerFlusher is implemented by assignment loggers that buffer
+// events. EppoClient.Close() flushes such loggers before returning.
+type LoggerFlusher interface {
+	Flush(ctx context.Context) error
+}
+
 // TODO: in the next major release, upgrade Timestamp fields to time.Time.
 
 type AssignmentEvent struct {
@@ -2,7 +2,10 @@ package eppoclient
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -17,6 +20,24 @@ type EppoClient struct {
 	logger             IAssignmentLogger
 	loggerContext      IAssignmentLoggerContext
 	applicationLogger  ApplicationLogger
+
+	// Optional streamer. nil if streaming is disabled.
+	streamer *streamer
+	// Set after Close() is called.
+	closed atomic.Bool
+	// Closed when the SDK key is rejected for the first time.
+	unauthorizedCh   chan struct{}
+	unauthorizedOnce sync.Once
+	// Optional hook called when the SDK key is rejected.
+	onUnauthorized func(error)
+	// Optional metrics sink. nil if disabled.
+	metrics Metrics
+	// Optional tracer. nil if disabled.
+	tracer Tracer
+	// Evaluation hooks in registration order.
+	hooks []EvaluationHook
+	// Optional local overrides. nil if disabled.
+	overrides *overrideStore
 }
 
 func newEppoClient(
@@ -34,6 +55,7 @@ func newEppoClient(
 		logger:             assignmentLogger,
 		loggerContext:      assignmentLoggerContext,
 		applicationLogger:  applicationLogger,
+		unauthorizedCh:     make(chan struct{}),
 	}
 }
 
@@ -51,12 +73,69 @@ func (ec *EppoClient) Initialized() <-chan struct{} {
 	return ec.configurationStore.Initialized()
 }
 
+// ConfigurationMetadata returns information about the currently
+// active configuration.
+func (ec *EppoClient) ConfigurationMetadata() ConfigurationMetadata {
+	config := ec.configurationStore.getConfiguration()
+	return ConfigurationMetadata{
+		FetchedAt: config.fetchedAt,
+		FromCache: config.fromCache,
+	}
+}
+
+// SetConfiguration replaces the active configuration with the
+// provided one. See InitClientFromConfiguration for the description
+// of arguments.
+//
+// If the client is polling, the configuration is overwritten on the
+// next successful fetch.
+//
+// Configuration failing validation (see ValidateConfiguration) is
+// rejected with a *ValidationError and the active configuration is
+// kept.
+func (ec *EppoClient) SetConfiguration(flagsJSON, banditsJSON []byte) error {
+	configuration, err := newConfigurationFromJSON(flagsJSON, banditsJSON)
+	if err != nil {
+		return err
+	}
+
+	err = configuration.validate(len(banditsJSON) > 0, ec.applicationLogger)
+	if err != nil {
+		return err
+	}
+
+	ec.configurationStore.setConfiguration(configuration)
+	return nil
+}
+
+// OnConfigurationChange registers `callback` to be called after each
+// configuration update with the difference from the previous
+// configuration. The first call after initialization reports all
+// flags as added. Updates that do not change any flags or bandit
+// models are not reported.
+//
+// Callbacks are invoked on a dedicated goroutine per listener, so they
+// never block configuration updates. If a callback is slow, pending
+// updates are coalesced into a single change. Panics in `callback` are
+// recovered and logged.
+//
+// Flag keys of obfuscated configuration are not known, so updates to
+// obfuscated configuration are not reported (an error is logged
+// instead). The first plaintext configuration after an obfuscated
+// one reports all flags as added.
+//
+// Returns a function that unregisters the listener.
+func (ec *EppoClient) OnConfigurationChange(callback func(ConfigurationChange)) (unsubscribe func()) {
+	listener := newConfigurationListener(callback, ec.applicationLogger)
+	return ec.configurationStore.subscribe(listener)
+}
+
 func (ec *EppoClient) GetBoolAssignment(
 	flagKey, subjectKey string,
 	subjectAttributes Attributes,
 	defaultValue bool,
 ) (bool, error) {
-	return ec.getBoolAssignment(context.Background(), flagKey, subjectKey, subjectAttributes, defaultValue)
+	return ec.getBoolAssignment(context.Background(), flagKey, subjectKey, subjectAttributes, defaultValue, nil)
 }
 
 func (ec *EppoClient) GetBoolAssignmentContext(
@@ -65,7 +144,35 @@ func (ec *EppoClient) GetBoolAssignmentContext(
 	subjectAttributes Attributes,
 	defaultValue bool,
 ) (bool, error) {
-	return ec.getBoolAssignment(ctx, flagKey, subjectKey, subjectAttributes, defaultValue)
+	return ec.getBoolAssignment(ctx, flagKey, subjectKey, subjectAttributes, defaultValue, nil)
+}
+
+func (ec *EppoClient) GetBoolAssignmentDetails(
+	flagKey, subjectKey string,
+	subjectAttributes Attributes,
+	defaultValue bool,
+) (bool, EvaluationDetails, error) {
+	return ec.getBoolAssignmentDetails(context.Background(), flagKey, subjectKey, subjectAttributes, defaultValue)
+}
+
+func (ec *EppoClient) GetBoolAssignmentDetailsContext(
+	ctx context.Context,
+	flagKey, subjectKey string,
+	subjectAttributes Attributes,
+	defaultValue bool,
+) (bool, EvaluationDetails, error) {
+	return ec.getBoolAssignmentDetails(ctx, flagKey, subjectKey, subjectAttributes, defaultValue)
+}
+
+func (ec *EppoClient) getBoolAssignmentDetails(
+	ctx context.Context,
+	flagKey, subjectKey string,
+	subjectAttributes Attributes,
+	defaultValue bool,
+) (bool, EvaluationDetails, error) {
+	details := newEvaluationDetails(flagKey, subjectKey, subjectAttributes)
+	value, err := ec.getBoolAssignment(ctx, flagKey, subjectKey, subjectAttributes, defaultValue, &details)
+	return value, details, err
 }
 
 func (ec *EppoClient) getBoolAssignment(
@@ -73,15 +180,18 @@ func (ec *EppoClient) getBoolAssignment(
 	flagKey, subjectKey string,
 	subjectAttributes Attributes,
 	defaultValue bool,
+	details *EvaluationDetails,
 ) (bool, error) {
-	variation, err := ec.getAssignment(ctx, ec.configurationStore.getConfiguration(), flagKey, subjectKey, subjectAttributes, booleanVariation)
+	variation, err := ec.getAssignment(ctx, ec.configurationStore.getConfiguration(), flagKey, subjectKey, subjectAttributes, booleanVariation, details)
 	if err != nil || variation == nil {
 		return defaultValue, err
 	}
 	result, ok := variation.(bool)
 	if !ok {
 		ec.applicationLogger.Errorf("failed to cast %v to bool", variation)
-		return defaultValue, fmt.Errorf("failed to cast %v to bool", variation)
+		err := fmt.Errorf("failed to cast %v to bool", variation)
+		details.setError(EvaluationReasonTypeMismatch, err)
+		return defaultValue, err
 	}
 	return result, err
 }
@@ -91,7 +201,7 @@ func (ec *EppoClient) GetNumericAssignment(
 	subjectAttributes Attributes,
 	defaultValue float64,
 ) (float64, error) {
-	return ec.getNumericAssignment(context.Background(), flagKey, subjectKey, subjectAttributes, defaultValue)
+	return ec.getNumericAssignment(context.Background(), flagKey, subjectKey, subjectAttributes, defaultValue, nil)
 }
 
 func (ec *EppoClient) GetNumericAssignmentContext(
@@ -100,7 +210,35 @@ func (ec *EppoClient) GetNumericAssignmentContext(
 	subjectAttributes Attributes,
 	defaultValue float64,
 ) (float64, error) {
-	return ec.getNumericAssignment(ctx, flagKey, subjectKey, subjectAttributes, defaultValue)
+	return ec.getNumericAssignment(ctx, flagKey, subjectKey, subjectAttributes, defaultValue, nil)
+}
+
+func (ec *EppoClient) GetNumericAssignmentDetails(
+	flagKey, subjectKey string,
+	subjectAttributes Attributes,
+	defaultValue float64,
+) (float64, EvaluationDetails, error) {
+	return ec.getNumericAssignmentDetails(context.Background(), flagKey, subjectKey, subjectAttributes, defaultValue)
+}
+
+func (ec *EppoClient) GetNumericAssignmentDetailsContext(
+	ctx context.Context,
+	flagKey, subjectKey string,
+	subjectAttributes Attributes,
+	defaultValue float64,
+) (float64, EvaluationDetails, error) {
+	return ec.getNumericAssignmentDetails(ctx, flagKey, subjectKey, subjectAttributes, defaultValue)
+}
+
+func (ec *EppoClient) getNumericAssignmentDetails(
+	ctx context.Context,
+	flagKey, subjectKey string,
+	subjectAttributes Attributes,
+	defaultValue float64,
+) (float64, EvaluationDetails, error) {
+	details := newEvaluationDetails(flagKey, subjectKey, subjectAttributes)
+	value, err := ec.getNumericAssignment(ctx, flagKey, subjectKey, subjectAttributes, defaultValue, &details)
+	return value, details, err
 }
 
 func (ec *EppoClient) getNumericAssignment(
@@ -108,15 +246,18 @@ func (ec *EppoClient) getNumericAssignment(
 	flagKey, subjectKey string,
 	subjectAttributes Attributes,
 	defaultValue float64,
+	details *EvaluationDetails,
 ) (float64, error) {
-	variation, err := ec.getAssignment(ctx, ec.configurationStore.getConfiguration(), flagKey, subjectKey, subjectAttributes, numericVariation)
+	variation, err := ec.getAssignment(ctx, ec.configurationStore.getConfiguration(), flagKey, subjectKey, subjectAttributes, numericVariation, details)
 	if err != nil || variation == nil {
 		return defaultValue, err
 	}
 	result, ok := variation.(float64)
 	if !ok {
 		ec.applicationLogger.Errorf("failed to cast %v to float64", variation)
-		return defaultValue, fmt.Errorf("failed to cast %v to float64", variation)
+		err := fmt.Errorf("failed to cast %v to float64", variation)
+		details.setError(EvaluationReasonTypeMismatch, err)
+		return defaultValue, err
 	}
 	return result, err
 }
@@ -126,7 +267,7 @@ func (ec *EppoClient) GetIntegerAssignment(
 	subjectAttributes Attributes,
 	defaultValue int64,
 ) (int64, error) {
-	return ec.getIntegerAssignment(context.Background(), flagKey, subjectKey, subjectAttributes, defaultValue)
+	return ec.getIntegerAssignment(context.Background(), flagKey, subjectKey, subjectAttributes, defaultValue, nil)
 }
 
 func (ec *EppoClient) GetIntegerAssignmentContext(
@@ -135,7 +276,35 @@ func (ec *EppoClient) GetIntegerAssignmentContext(
 	subjectAttributes Attributes,
 	defaultValue int64,
 ) (int64, error) {
-	return ec.getIntegerAssignment(ctx, flagKey, subjectKey, subjectAttributes, defaultValue)
+	return ec.getIntegerAssignment(ctx, flagKey, subjectKey, subjectAttributes, defaultValue, nil)
+}
+
+func (ec *EppoClient) GetIntegerAssignmentDetails(
+	flagKey, subjectKey string,
+	subjectAttributes Attributes,
+	defaultValue int64,
+) (int64, EvaluationDetails, error) {
+	return ec.getIntegerAssignmentDetails(context.Background(), flagKey, subjectKey, subjectAttributes, defaultValue)
+}
+
+func (ec *EppoClient) GetIntegerAssignmentDetailsContext(
+	ctx context.Context,
+	flagKey, subjectKey string,
+	subjectAttributes Attributes,
+	defaultValue int64,
+) (int64, EvaluationDetails, error) {
+	return ec.getIntegerAssignmentDetails(ctx, flagKey, subjectKey, subjectAttributes, defaultValue)
+}
+
+func (ec *EppoClient) getIntegerAssignmentDetails(
+	ctx context.Context,
+	flagKey, subjectKey string,
+	subjectAttributes Attributes,
+	defaultValue int64,
+) (int64, EvaluationDetails, error) {
+	details := newEvaluationDetails(flagKey, subjectKey, subjectAttributes)
+	value, err := ec.getIntegerAssignment(ctx, flagKey, subjectKey, subjectAttributes, defaultValue, &details)
+	return value, details, err
 }
 
 func (ec *EppoClient) getIntegerAssignment(
@@ -143,15 +312,18 @@ func (ec *EppoClient) getIntegerAssignment(
 	flagKey, subjectKey string,
 	subjectAttributes Attributes,
 	defaultValue int64,
+	details *EvaluationDetails,
 ) (int64, error) {
-	variation, err := ec.getAssignment(ctx, ec.configurationStore.getConfiguration(), flagKey, subjectKey, subjectAttributes, integerVariation)
+	variation, err := ec.getAssignment(ctx, ec.configurationStore.getConfiguration(), flagKey, subjectKey, subjectAttributes, integerVariation, details)
 	if err != nil || variation == nil {
 		return defaultValue, err
 	}
 	result, ok := variation.(int64)
 	if !ok {
 		ec.applicationLogger.Errorf("failed to cast %v to int64", variation)
-		return defaultValue, fmt.Errorf("failed to cast %v to int64", variation)
+		err := fmt.Errorf("failed to cast %v to int64", variation)
+		details.setError(EvaluationReasonTypeMismatch, err)
+		return defaultValue, err
 	}
 	return result, err
 }
@@ -161,7 +333,7 @@ func (ec *EppoClient) GetStringAssignment(
 	subjectAttributes Attributes,
 	defaultValue string,
 ) (string, error) {
-	return ec.getStringAssignment(context.Background(), flagKey, subjectKey, subjectAttributes, defaultValue)
+	return ec.getStringAssignment(context.Background(), flagKey, subjectKey, subjectAttributes, defaultValue, nil)
 }
 
 func (ec *EppoClient) GetStringAssignmentContext(
@@ -170,7 +342,35 @@ func (ec *EppoClient) GetStringAssignmentContext(
 	subjectAttributes Attributes,
 	defaultValue string,
 ) (string, error) {
-	return ec.getStringAssignment(ctx, flagKey, subjectKey, subjectAttributes, defaultValue)
+	return ec.getStringAssignment(ctx, flagKey, subjectKey, subjectAttributes, defaultValue, nil)
+}
+
+func (ec *EppoClient) GetStringAssignmentDetails(
+	flagKey, subjectKey string,
+	subjectAttributes Attributes,
+	defaultValue string,
+) (string, EvaluationDetails, error) {
+	return ec.getStringAssignmentDetails(context.Background(), flagKey, subjectKey, subjectAttributes, defaultValue)
+}
+
+func (ec *EppoClient) GetStringAssignmentDetailsContext(
+	ctx context.Context,
+	flagKey, subjectKey string,
+	subjectAttributes Attributes,
+	defaultValue string,
+) (string, EvaluationDetails, error) {
+	return ec.getStringAssignmentDetails(ctx, flagKey, subjectKey, subjectAttributes, defaultValue)
+}
+
+func (ec *EppoClient) getStringAssignmentDetails(
+	ctx context.Context,
+	flagKey, subjectKey string,
+	subjectAttributes Attributes,
+	defaultValue string,
+) (string, EvaluationDetails, error) {
+	details := newEvaluationDetails(flagKey, subjectKey, subjectAttributes)
+	value, err := ec.getStringAssignment(ctx, flagKey, subjectKey, subjectAttributes, defaultValue, &details)
+	return value, details, err
 }
 
 func (ec *EppoClient) getStringAssignment(
@@ -178,15 +378,18 @@ func (ec *EppoClient) getStringAssignment(
 	flagKey, subjectKey string,
 	subjectAttributes Attributes,
 	defaultValue string,
+	details *EvaluationDetails,
 ) (string, error) {
-	variation, err := ec.getAssignment(ctx, ec.configurationStore.getConfiguration(), flagKey, subjectKey, subjectAttributes, stringVariation)
+	variation, err := ec.getAssignment(ctx, ec.configurationStore.getConfiguration(), flagKey, subjectKey, subjectAttributes, stringVariation, details)
 	if err != nil || variation == nil {
 		return defaultValue, err
 	}
 	result, ok := variation.(string)
 	if !ok {
 		ec.applicationLogger.Errorf("failed to cast %v to string", variation)
-		return defaultValue, fmt.Errorf("failed to cast %v to string", variation)
+		err := fmt.Errorf("failed to cast %v to string", variation)
+		details.setError(EvaluationReasonTypeMismatch, err)
+		return defaultValue, err
 	}
 	return result, err
 }
@@ -196,7 +399,7 @@ func (ec *EppoClient) GetJSONAssignment(
 	subjectAttributes Attributes,
 	defaultValue any,
 ) (any, error) {
-	return ec.getJSONAssignment(context.Background(), flagKey, subjectKey, subjectAttributes, defaultValue)
+	return ec.getJSONAssignment(context.Background(), flagKey, subjectKey, subjectAttributes, defaultValue, nil)
 }
 
 func (ec *EppoClient) GetJSONAssignmentContext(
@@ -205,7 +408,35 @@ func (ec *EppoClient) GetJSONAssignmentContext(
 	subjectAttributes Attributes,
 	defaultValue any,
 ) (any, error) {
-	return ec.getJSONAssignment(ctx, flagKey, subjectKey, subjectAttributes, defaultValue)
+	return ec.getJSONAssignment(ctx, flagKey, subjectKey, subjectAttributes, defaultValue, nil)
+}
+
+func (ec *EppoClient) GetJSONAssignmentDetails(
+	flagKey, subjectKey string,
+	subjectAttributes Attributes,
+	defaultValue any,
+) (any, EvaluationDetails, error) {
+	return ec.getJSONAssignmentDetails(context.Background(), flagKey, subjectKey, subjectAttributes, defaultValue)
+}
+
+func (ec *EppoClient) GetJSONAssignmentDetailsContext(
+	ctx context.Context,
+	flagKey, subjectKey string,
+	subjectAttributes Attributes,
+	defaultValue any,
+) (any, EvaluationDetails, error) {
+	return ec.getJSONAssignmentDetails(ctx, flagKey, subjectKey, subjectAttributes, defaultValue)
+}
+
+func (ec *EppoClient) getJSONAssignmentDetails(
+	ctx context.Context,
+	flagKey, subjectKey string,
+	subjectAttributes Attributes,
+	defaultValue any,
+) (any, EvaluationDetails, error) {
+	details := newEvaluationDetails(flagKey, subjectKey, subjectAttributes)
+	value, err := ec.getJSONAssignment(ctx, flagKey, subjectKey, subjectAttributes, defaultValue, &details)
+	return value, details, err
 }
 
 func (ec *EppoClient) getJSONAssignment(
@@ -213,15 +444,18 @@ func (ec *EppoClient) getJSONAssignment(
 	flagKey, subjectKey string,
 	subjectAttributes Attributes,
 	defaultValue any,
+	details *EvaluationDetails,
 ) (any, error) {
-	variation, err := ec.getAssignment(ctx, ec.configurationStore.getConfiguration(), flagKey, subjectKey, subjectAttributes, jsonVariation)
+	variation, err := ec.getAssignment(ctx, ec.configurationStore.getConfiguration(), flagKey, subjectKey, subjectAttributes, jsonVariation, details)
 	if err != nil || variation == nil {
 		return defaultValue, err
 	}
 	result, ok := variation.(jsonVariationValue)
 	if !ok {
 		ec.applicationLogger.Errorf("failed to cast %v to json. This should never happen. Please report bug to Eppo", variation)
-		return defaultValue, fmt.Errorf("failed to cast %v to json. This should never happen. Please report bug to Eppo", variation)
+		err := fmt.Errorf("failed to cast %v to json. This should never happen. Please report bug to Eppo", variation)
+		details.setError(EvaluationReasonTypeMismatch, err)
+		return defaultValue, err
 	}
 	return result.Parsed, err
 }
@@ -231,7 +465,7 @@ func (ec *EppoClient) GetJSONBytesAssignment(
 	subjectAttributes Attributes,
 	defaultValue []byte,
 ) ([]byte, error) {
-	return ec.getJSONBytesAssignment(context.Background(), flagKey, subjectKey, subjectAttributes, defaultValue)
+	return ec.getJSONBytesAssignment(context.Background(), flagKey, subjectKey, subjectAttributes, defaultValue, nil)
 }
 
 func (ec *EppoClient) GetJSONBytesAssignmentContext(
@@ -240,7 +474,35 @@ func (ec *EppoClient) GetJSONBytesAssignmentContext(
 	subjectAttributes Attributes,
 	defaultValue []byte,
 ) ([]byte, error) {
-	return ec.getJSONBytesAssignment(ctx, flagKey, subjectKey, subjectAttributes, defaultValue)
+	return ec.getJSONBytesAssignment(ctx, flagKey, subjectKey, subjectAttributes, defaultValue, nil)
+}
+
+func (ec *EppoClient) GetJSONBytesAssignmentDetails(
+	flagKey, subjectKey string,
+	subjectAttributes Attributes,
+	defaultValue []byte,
+) ([]byte, EvaluationDetails, error) {
+	return ec.getJSONBytesAssignmentDetails(context.Background(), flagKey, subjectKey, subjectAttributes, defaultValue)
+}
+
+func (ec *EppoClient) GetJSONBytesAssignmentDetailsContext(
+	ctx context.Context,
+	flagKey, subjectKey string,
+	subjectAttributes Attributes,
+	defaultValue []byte,
+) ([]byte, EvaluationDetails, error) {
+	return ec.getJSONBytesAssignmentDetails(ctx, flagKey, subjectKey, subjectAttributes, defaultValue)
+}
+
+func (ec *EppoClient) getJSONBytesAssignmentDetails(
+	ctx context.Context,
+	flagKey, subjectKey string,
+	subjectAttributes Attributes,
+	defaultValue []byte,
+) ([]byte, EvaluationDetails, error) {
+	details := newEvaluationDetails(flagKey, subjectKey, subjectAttributes)
+	value, err := ec.getJSONBytesAssignment(ctx, flagKey, subjectKey, subjectAttributes, defaultValue, &details)
+	return value, details, err
 }
 
 func (ec *EppoClient) getJSONBytesAssignment(
@@ -248,15 +510,18 @@ func (ec *EppoClient) getJSONBytesAssignment(
 	flagKey, subjectKey string,
 	subjectAttributes Attributes,
 	defaultValue []byte,
+	details *EvaluationDetails,
 ) ([]byte, error) {
-	variation, err := ec.getAssignment(ctx, ec.configurationStore.getConfiguration(), flagKey, subjectKey, subjectAttributes, jsonVariation)
+	variation, err := ec.getAssignment(ctx, ec.configurationStore.getConfiguration(), flagKey, subjectKey, subjectAttributes, jsonVariation, details)
 	if err != nil || variation == nil {
 		return defaultValue, err
 	}
 	result, ok := variation.(jsonVariationValue)
 	if !ok {
 		ec.applicationLogger.Errorf("failed to cast %v to json. This should never happen. Please report bug to Eppo", variation)
-		return defaultValue, fmt.Errorf("failed to cast %v to json. This should never happen. Please report bug to Eppo", variation)
+		err := fmt.Errorf("failed to cast %v to json. This should never happen. Please report bug to Eppo", variation)
+		details.setError(EvaluationReasonTypeMismatch, err)
+		return defaultValue, err
 	}
 	return result.Raw, err
 }
@@ -292,11 +557,119 @@ func (ec *EppoClient) getBanditAction(
 	actions map[string]ContextAttributes,
 	defaultVariation string,
 ) BanditResult {
+	if ec.tracer == nil {
+		return ec.runBanditAction(ctx, nil, flagKey, subjectKey, subjectAttributes, actions, defaultVariation)
+	}
+
+	ctx, span := ec.tracer.StartSpan(ctx, SpanGetBanditAction)
+	defer span.End()
+	span.SetAttribute(AttributeFlagKey, flagKey)
+
+	result := ec.runBanditAction(ctx, span, flagKey, subjectKey, subjectAttributes, actions, defaultVariation)
+	if result.Action != nil {
+		span.SetAttribute(AttributeBanditAction, *result.Action)
+	}
+	return result
+}
+
+// runBanditAction evaluates the bandit through hooks and logs the
+// bandit action.
+func (ec *EppoClient) runBanditAction(
+	ctx context.Context,
+	span Span,
+	flagKey, subjectKey string,
+	subjectAttributes ContextAttributes,
+	actions map[string]ContextAttributes,
+	defaultVariation string,
+) BanditResult {
+	if len(ec.hooks) == 0 {
+		result, evaluation, event := ec.evaluateBanditAction(ctx, span, flagKey, subjectKey, subjectAttributes, actions, defaultVariation)
+		ec.logAssignment(ctx, evaluation.event)
+		if event != nil {
+			ec.logBanditAction(ctx, *event)
+		}
+		return result
+	}
+
+	hookCtx := &HookContext{
+		FlagKey:                 flagKey,
+		SubjectKey:              subjectKey,
+		VariationType:           VariationTypeString,
+		Bandit:                  true,
+		BanditSubjectAttributes: subjectAttributes.copy(),
+		BanditActions:           copyActions(actions),
+	}
+
+	var result BanditResult
+	var evaluation flagEvaluation
+	var event *BanditEvent
+	overridden := false
+	if override := ec.runBeforeHooks(ctx, hookCtx); override != nil {
+		if variation, ok := override.Value.(string); ok {
+			result = BanditResult{Variation: variation, Action: override.Action}
+			overridden = true
+		} else {
+			ec.applicationLogger.Errorf("ignoring override of bandit flag %v: value %v is not a string", flagKey, override.Value)
+		}
+	}
+	if !overridden {
+		result, evaluation, event = ec.evaluateBanditAction(ctx, span, flagKey, subjectKey, hookCtx.BanditSubjectAttributes, hookCtx.BanditActions, defaultVariation)
+	}
+
+	hookResult := EvaluationResult{
+		Value:      result.Variation,
+		Action:     result.Action,
+		Overridden: overridden,
+	}
+	if evaluation.allocation != nil {
+		hookResult.AllocationKey = evaluation.allocation.Key
+	}
+	if evaluation.split != nil {
+		hookResult.VariationKey = evaluation.split.VariationKey
+	}
+	if event != nil {
+		hookResult.BanditKey = event.BanditKey
+	}
+	ec.runAfterHooks(ctx, hookCtx, hookResult)
+	if !hookCtx.SkipLogging {
+		ec.logAssignment(ctx, evaluation.event)
+		if event != nil {
+			ec.logBanditAction(ctx, *event)
+		}
+	}
+	ec.runFinallyHooks(ctx, hookCtx, hookResult)
+
+	return result
+}
+
+// evaluateBanditAction selects the variation and bandit action.
+// Returns the evaluation of the underlying flag, whose assignment is
+// not logged, and the bandit event to log, which is nil if no bandit
+// has been evaluated. `span` is optional and receives the key of the
+// evaluated bandit.
+//
+// The underlying flag is evaluated without hooks: hooks of the
+// bandit evaluation run instead.
+func (ec *EppoClient) evaluateBanditAction(
+	ctx context.Context,
+	span Span,
+	flagKey, subjectKey string,
+	subjectAttributes ContextAttributes,
+	actions map[string]ContextAttributes,
+	defaultVariation string,
+) (BanditResult, flagEvaluation, *BanditEvent) {
+	if ec.closed.Load() {
+		return BanditResult{
+			Variation: defaultVariation,
+			Action:    nil,
+		}, flagEvaluation{}, nil
+	}
+
 	config := ec.configurationStore.getConfiguration()
 
 	// ignoring the error here as we can always proceed with default variation
-	assignmentValue, _ := ec.getAssignment(ctx, config, flagKey, subjectKey, subjectAttributes.toGenericAttributes(), stringVariation)
-	variation, ok := assignmentValue.(string)
+	assignment, _ := ec.assign(ctx, config, flagKey, subjectKey, subjectAttributes.toGenericAttributes(), stringVariation, nil, assignOptions{withoutHooks: true})
+	variation, ok := assignment.value.(string)
 	if !ok {
 		variation = defaultVariation
 	}
@@ -306,7 +679,7 @@ func (ec *EppoClient) getBanditAction(
 		return BanditResult{
 			Variation: variation,
 			Action:    nil,
-		}
+		}, assignment, nil
 	}
 
 	banditVariation, ok := config.getBanditVariant(flagKey, variation)
@@ -314,7 +687,7 @@ func (ec *EppoClient) getBanditAction(
 		return BanditResult{
 			Variation: variation,
 			Action:    nil,
-		}
+		}, assignment, nil
 	}
 
 	bandit, err := config.getBanditConfiguration(banditVariation.Key)
@@ -323,17 +696,29 @@ func (ec *EppoClient) getBanditAction(
 		return BanditResult{
 			Variation: variation,
 			Action:    nil,
-		}
+		}, assignment, nil
 	}
 
+	if span != nil {
+		span.SetAttribute(AttributeBanditKey, bandit.BanditKey)
+	}
+
+	start := time.Now()
 	evaluation := bandit.ModelData.evaluate(banditEvaluationContext{
 		flagKey:           flagKey,
 		subjectKey:        subjectKey,
 		subjectAttributes: subjectAttributes,
 		actions:           actions,
 	})
+	if ec.metrics != nil {
+		ec.metrics.ObserveBanditEvaluation(BanditEvaluationMetric{
+			FlagKey:   flagKey,
+			BanditKey: bandit.BanditKey,
+			Duration:  time.Since(start),
+		})
+	}
 
-	ec.logBanditAction(ctx, BanditEvent{
+	event := &BanditEvent{
 		FlagKey:                      flagKey,
 		BanditKey:                    bandit.BanditKey,
 		Subject:                      subjectKey,
@@ -350,43 +735,169 @@ func (ec *EppoClient) getBanditAction(
 			"sdkLanguage": "go",
 			"sdkVersion":  __version__,
 		},
-	})
+	}
 
 	return BanditResult{
 		Variation: variation,
 		Action:    &evaluation.actionKey,
+	}, assignment, event
+}
+
+// getAssignment evaluates the flag and logs the assignment.
+//
+// `details` is optional and is populated with evaluation details if
+// not nil.
+func (ec *EppoClient) getAssignment(ctx context.Context, config configuration, flagKey string, subjectKey string, subjectAttributes Attributes, variationType variationType, details *EvaluationDetails) (interface{}, error) {
+	evaluation, err := ec.assign(ctx, config, flagKey, subjectKey, subjectAttributes, variationType, details, assignOptions{logging: true})
+	return evaluation.value, err
+}
+
+// assignOptions customizes assign.
+type assignOptions struct {
+	// logging enables logging of the assignment.
+	logging bool
+	// bulk is set when many flags are evaluated at once. Subjects
+	// not being assigned to a flag is expected then and is not
+	// logged as an error.
+	bulk bool
+	// withoutHooks skips hooks.
+	withoutHooks bool
+	// decode is optional and converts the assigned value to the
+	// type requested by the caller. If it fails, the assignment is
+	// not logged and its error is returned.
+	decode func(value interface{}) error
+	// augmentedSubjectAttributes are subject attributes augmented
+	// with the subject key (see `augmentWithSubjectKey`) computed
+	// once for many flags. Optional. Ignored when hooks run as
+	// before-hooks may change subject attributes.
+	augmentedSubjectAttributes Attributes
+}
+
+// assign evaluates the flag with metrics, tracing, hooks, and local
+// overrides.
+func (ec *EppoClient) assign(ctx context.Context, config configuration, flagKey string, subjectKey string, subjectAttributes Attributes, variationType variationType, details *EvaluationDetails, opts assignOptions) (flagEvaluation, error) {
+	if ec.metrics == nil && ec.tracer == nil {
+		return ec.runAssignment(ctx, config, flagKey, subjectKey, subjectAttributes, variationType, details, opts)
+	}
+
+	var span Span
+	if ec.tracer != nil {
+		ctx, span = ec.tracer.StartSpan(ctx, SpanGetAssignment)
+		defer span.End()
+	}
+
+	start := time.Now()
+	evaluation, err := ec.runAssignment(ctx, config, flagKey, subjectKey, subjectAttributes, variationType, details, opts)
+	if ec.metrics != nil {
+		ec.observeEvaluation(flagKey, err, time.Since(start))
+	}
+	if span != nil {
+		ec.traceEvaluation(span, flagKey, variationType, evaluation, err)
+	}
+	return evaluation, err
+}
+
+// runAssignment evaluates the flag through hooks and logs the
+// assignment if `opts.logging` is true.
+func (ec *EppoClient) runAssignment(ctx context.Context, config configuration, flagKey string, subjectKey string, subjectAttributes Attributes, variationType variationType, details *EvaluationDetails, opts assignOptions) (flagEvaluation, error) {
+	if len(ec.hooks) == 0 || opts.withoutHooks {
+		evaluation, err := ec.evaluateAssignment(config, flagKey, subjectKey, subjectAttributes, variationType, details, opts)
+		if err == nil && opts.decode != nil {
+			err = opts.decode(evaluation.value)
+		}
+		if err == nil && opts.logging {
+			ec.logAssignment(ctx, evaluation.event)
+		}
+		return evaluation, err
+	}
+
+	hookCtx := &HookContext{
+		FlagKey:           flagKey,
+		SubjectKey:        subjectKey,
+		VariationType:     variationType.toPublic(),
+		SubjectAttributes: subjectAttributes.copy(),
+	}
+
+	var evaluation flagEvaluation
+	var err error
+	overridden := false
+	if override := ec.runBeforeHooks(ctx, hookCtx); override != nil {
+		value, overrideErr := variationType.overrideValue(override.Value)
+		if overrideErr == nil {
+			evaluation = flagEvaluation{value: value}
+			overridden = true
+			details.setOverride()
+		} else {
+			ec.applicationLogger.Errorf("ignoring override of flag %v: %v", flagKey, overrideErr)
+		}
+	}
+	if !overridden {
+		opts.augmentedSubjectAttributes = nil
+		evaluation, err = ec.evaluateAssignment(config, flagKey, subjectKey, hookCtx.SubjectAttributes, variationType, details, opts)
 	}
+	if err == nil && opts.decode != nil {
+		err = opts.decode(evaluation.value)
+	}
+
+	result := newEvaluationResult(evaluation, overridden, err)
+	ec.runAfterHooks(ctx, hookCtx, result)
+	if err == nil && opts.logging && !hookCtx.SkipLogging {
+		ec.logAssignment(ctx, evaluation.event)
+	}
+	ec.runFinallyHooks(ctx, hookCtx, result)
+
+	return evaluation, err
 }
 
-func (ec *EppoClient) getAssignment(ctx context.Context, config configuration, flagKey string, subjectKey string, subjectAttributes Attributes, variationType variationType) (interface{}, error) {
+// evaluateAssignment evaluates the flag without logging the
+// assignment.
+func (ec *EppoClient) evaluateAssignment(config configuration, flagKey string, subjectKey string, subjectAttributes Attributes, variationType variationType, details *EvaluationDetails, opts assignOptions) (flagEvaluation, error) {
+	if ec.closed.Load() {
+		details.setError(EvaluationReasonDefaultUsed, ErrClientClosed)
+		return flagEvaluation{}, ErrClientClosed
+	}
+
 	if subjectKey == "" {
-		return nil, fmt.Errorf("no subject key provided")
+		details.setError(EvaluationReasonDefaultUsed, errNoSubjectKey)
+		return flagEvaluation{}, errNoSubjectKey
 	}
 
 	if flagKey == "" {
-		return nil, fmt.Errorf("no flag key provided")
+		details.setError(EvaluationReasonDefaultUsed, errNoFlagKey)
+		return flagEvaluation{}, errNoFlagKey
+	}
+
+	if ec.overrides != nil {
+		if evaluation, ok := ec.overrides.evaluate(flagKey, subjectKey, subjectAttributes, variationType, details); ok {
+			return evaluation, nil
+		}
 	}
 
 	flag, err := config.getFlagConfiguration(flagKey)
 	if err != nil {
 		ec.applicationLogger.Infof("failed to get flag configuration: %v", err)
-		return nil, err
+		details.setError(EvaluationReasonFlagNotFound, err)
+		return flagEvaluation{}, err
 	}
 
 	err = flag.verifyType(variationType)
 	if err != nil {
 		ec.applicationLogger.Warnf("failed to verify flag type: %v", err)
-		return nil, err
+		details.setError(EvaluationReasonTypeMismatch, err)
+		return flagEvaluation{}, err
 	}
 
-	assignmentValue, assignmentEvent, err := flag.eval(subjectKey, subjectAttributes, ec.applicationLogger)
+	evaluation, err := flag.evalAugmented(subjectKey, subjectAttributes, opts.augmentedSubjectAttributes, ec.applicationLogger, details)
 	if err != nil {
-		ec.applicationLogger.Errorf("failed to evaluate flag: %v", err)
-		return nil, err
+		if opts.bulk && (errors.Is(err, ErrFlagNotEnabled) || errors.Is(err, ErrSubjectAllocation)) {
+			ec.applicationLogger.Debug("failed to evaluate flag ", flagKey, ": ", err)
+		} else {
+			ec.applicationLogger.Errorf("failed to evaluate flag: %v", err)
+		}
+		return flagEvaluation{}, err
 	}
 
-	ec.logAssignment(ctx, assignmentEvent)
-	return assignmentValue, nil
+	return evaluation, nil
 }
 
 func (ec *EppoClient) logAssignment(ctx context.Context, event *AssignmentEvent) {
@@ -399,6 +910,7 @@ func (ec *EppoClient) logAssignment(ctx context.Context, event *AssignmentEvent)
 		r := recover()
 		if r != nil {
 			ec.applicationLogger.Errorf("panic occurred: %v", r)
+			ec.observeLoggerPanic(LoggerPanicMetric{Logger: LoggerAssignment})
 		}
 	}()
 
@@ -418,7 +930,8 @@ func (ec *EppoClient) logBanditAction(ctx context.Context, event BanditEvent) {
 	defer func() {
 		r := recover()
 		if r != nil {
-			fmt.Println("panic occurred:", r)
+			ec.applicationLogger.Errorf("panic occurred: %v", r)
+			ec.observeLoggerPanic(LoggerPanicMetric{Logger: LoggerBandit})
 		}
 	}()
 
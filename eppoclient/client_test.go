@@ -8,6 +8,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
 )
 
 var (
@@ -454,13 +455,15 @@ func Test_client_handlesBanditLoggerPanic(t *testing.T) {
 		},
 	}
 
-	client := newEppoClient(newConfigurationStoreWithConfig(configuration{flags: flags, bandits: bandits}), nil, nil, logger, nil, applicationLogger)
+	core, logs := observer.New(zap.ErrorLevel)
+	client := newEppoClient(newConfigurationStoreWithConfig(configuration{flags: flags, bandits: bandits}), nil, nil, logger, nil, NewZapLogger(zap.New(core)))
 	actions := map[string]ContextAttributes{
 		"action1": {},
 	}
 	client.GetBanditAction("testFlag", "subject", ContextAttributes{}, actions, "bandit")
 
 	logger.AssertNumberOfCalls(t, "LogBanditAction", 1)
+	assert.Equal(t, 1, logs.FilterMessage("panic occurred: logging panic").Len())
 }
 
 func Test_client_correctActionIsReturnedIfBanditLoggerPanics(t *testing.T) {
@@ -0,0 +1,46 @@
+package eppoclient
+
+import "context"
+
+// Client is the assignment API of EppoClient. Depend on Client
+// instead of *EppoClient to substitute a fake in tests (see package
+// eppotest).
+type Client interface {
+	GetBoolAssignment(flagKey, subjectKey string, subjectAttributes Attributes, defaultValue bool) (bool, error)
+	GetBoolAssignmentContext(ctx context.Context, flagKey, subjectKey string, subjectAttributes Attributes, defaultValue bool) (bool, error)
+	GetBoolAssignmentDetails(flagKey, subjectKey string, subjectAttributes Attributes, defaultValue bool) (bool, EvaluationDetails, error)
+	GetBoolAssignmentDetailsContext(ctx context.Context, flagKey, subjectKey string, subjectAttributes Attributes, defaultValue bool) (bool, EvaluationDetails, error)
+
+	GetNumericAssignment(flagKey, subjectKey string, subjectAttributes Attributes, defaultValue float64) (float64, error)
+	GetNumericAssignmentContext(ctx context.Context, flagKey, subjectKey string, subjectAttributes Attributes, defaultValue float64) (float64, error)
+	GetNumericAssignmentDetails(flagKey, subjectKey string, subjectAttributes Attributes, defaultValue float64) (float64, EvaluationDetails, error)
+	GetNumericAssignmentDetailsContext(ctx context.Context, flagKey, subjectKey string, subjectAttributes Attributes, defaultValue float64) (float64, EvaluationDetails, error)
+
+	GetIntegerAssignment(flagKey, subjectKey string, subjectAttributes Attributes, defaultValue int64) (int64, error)
+	GetIntegerAssignmentContext(ctx context.Context, flagKey, subjectKey string, subjectAttributes Attributes, defaultValue int64) (int64, error)
+	GetIntegerAssignmentDetails(flagKey, subjectKey string, subjectAttributes Attributes, defaultValue int64) (int64, EvaluationDetails, error)
+	GetIntegerAssignmentDetailsContext(ctx context.Context, flagKey, subjectKey string, subjectAttributes Attributes, defaultValue int64) (int64, EvaluationDetails, error)
+
+	GetStringAssignment(flagKey, subjectKey string, subjectAttributes Attributes, defaultValue string) (string, error)
+	GetStringAssignmentContext(ctx context.Context, flagKey, subjectKey string, subjectAttributes Attributes, defaultValue string) (string, error)
+	GetStringAssignmentDetails(flagKey, subjectKey string, subjectAttributes Attributes, defaultValue string) (string, EvaluationDetails, error)
+	GetStringAssignmentDetailsContext(ctx context.Context, flagKey, subjectKey string, subjectAttributes Attributes, defaultValue string) (string, EvaluationDetails, error)
+
+	GetJSONAssignment(flagKey, subjectKey string, subjectAttributes Attributes, defaultValue any) (any, error)
+	GetJSONAssignmentContext(ctx context.Context, flagKey, subjectKey string, subjectAttributes Attributes, defaultValue any) (any, error)
+	GetJSONAssignmentDetails(flagKey, subjectKey string, subjectAttributes Attributes, defaultValue any) (any, EvaluationDetails, error)
+	GetJSONAssignmentDetailsContext(ctx context.Context, flagKey, subjectKey string, subjectAttributes Attributes, defaultValue any) (any, EvaluationDetails, error)
+
+	GetJSONBytesAssignment(flagKey, subjectKey string, subjectAttributes Attributes, defaultValue []byte) ([]byte, error)
+	GetJSONBytesAssignmentContext(ctx context.Context, flagKey, subjectKey string, subjectAttributes Attributes, defaultValue []byte) ([]byte, error)
+	GetJSONBytesAssignmentDetails(flagKey, subjectKey string, subjectAttributes Attributes, defaultValue []byte) ([]byte, EvaluationDetails, error)
+	GetJSONBytesAssignmentDetailsContext(ctx context.Context, flagKey, subjectKey string, subjectAttributes Attributes, defaultValue []byte) ([]byte, EvaluationDetails, error)
+
+	GetBanditAction(flagKey, subjectKey string, subjectAttributes ContextAttributes, actions map[string]ContextAttributes, defaultVariation string) BanditResult
+	GetBanditActionContext(ctx context.Context, flagKey, subjectKey string, subjectAttributes ContextAttributes, actions map[string]ContextAttributes, defaultVariation string) BanditResult
+
+	GetAllAssignments(ctx context.Context, subjectKey string, subjectAttributes Attributes, opts ...GetAllAssignmentsOption) (map[string]AssignmentResult, error)
+	GetPrecomputedConfiguration(subjectKey string, subjectAttributes Attributes, banditActions map[string]map[string]ContextAttributes) ([]byte, error)
+}
+
+var _ Client = (*EppoClient)(nil)
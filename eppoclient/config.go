@@ -19,6 +19,66 @@ type Config struct {
 	PollerInterval          time.Duration
 	ApplicationLogger       ApplicationLogger
 	HttpClient              *http.Client
+	// PollerJitter is the maximum random duration subtracted from
+	// PollerInterval, so that multiple clients do not poll in
+	// lockstep. Defaults to 10% of PollerInterval and is limited to
+	// half of PollerInterval. Set to a negative value to disable
+	// jitter.
+	PollerJitter time.Duration
+	// PollerMaxBackoff is the maximum delay between polls after
+	// consecutive fetch failures. The delay doubles with every
+	// failure starting from PollerInterval and resets after a
+	// successful fetch. Defaults to 5 minutes. Set to a negative
+	// value to disable backoff.
+	PollerMaxBackoff time.Duration
+	// ConfigurationCacheDir enables on-disk configuration cache if
+	// set. Every successfully fetched configuration is written to
+	// this directory, and InitClient loads the cached
+	// configuration on startup, so the client can serve assignments
+	// before the first fetch completes.
+	ConfigurationCacheDir string
+	// ConfigurationCacheMaxAge is the maximum age of cached
+	// configuration to be used on startup. Zero means no limit.
+	ConfigurationCacheMaxAge time.Duration
+	// StreamingUrl enables streaming configuration updates if set.
+	// The client keeps a Server-Sent Events connection to this URL
+	// and applies configuration updates as soon as they are
+	// published. Polling is only used while the stream is
+	// unavailable.
+	StreamingUrl string
+	// UseAuthorizationHeader sends the SDK key in the Authorization
+	// header as a bearer token instead of the apiKey query
+	// parameter.
+	UseAuthorizationHeader bool
+	// OnUnauthorized is called when Eppo rejects the SDK key. The
+	// client stops polling at that point, as all further requests
+	// would be rejected as well. See also EppoClient.Status.
+	OnUnauthorized func(err error)
+	// Metrics receives telemetry about evaluations, configuration
+	// fetches, and assignment loggers. See NewPrometheusMetrics
+	// and NewExpvarMetrics.
+	Metrics Metrics
+	// Tracer creates spans around flag evaluations, bandit
+	// evaluations, and configuration fetches. Evaluation spans are
+	// children of the span carried by the context passed to
+	// `*Context` getters.
+	Tracer Tracer
+	// Hooks are called around every flag and bandit evaluation in
+	// the order they are listed. See EvaluationHook.
+	Hooks []EvaluationHook
+	// OverridesFile enables local overrides if set. The file (YAML
+	// or JSON) maps flag keys to forced values, optionally scoped
+	// to subject keys or attribute values, and takes precedence
+	// over the configuration fetched from Eppo. See README for the
+	// file format.
+	OverridesFile string
+	// OverridesReloadInterval is how often OverridesFile is checked
+	// for changes. Defaults to 1 second.
+	OverridesReloadInterval time.Duration
+	// LogOverriddenAssignments enables logging of assignments
+	// forced by OverridesFile. Such assignments are not logged by
+	// default.
+	LogOverriddenAssignments bool
 }
 
 func (cfg *Config) validate() error {
@@ -26,6 +86,28 @@ func (cfg *Config) validate() error {
 		return fmt.Errorf("SDK key not set")
 	}
 
+	return cfg.setDefaults()
+}
+
+func (cfg *Config) pollingPolicy() pollingPolicy {
+	policy := pollingPolicy{interval: cfg.PollerInterval}
+	if cfg.PollerJitter > 0 {
+		// Limit jitter, so clients never poll in a tight loop.
+		policy.jitter = cfg.PollerJitter
+		if policy.jitter > policy.interval/2 {
+			policy.jitter = policy.interval / 2
+		}
+	}
+	if cfg.PollerMaxBackoff > 0 {
+		policy.maxBackoff = cfg.PollerMaxBackoff
+	}
+	return policy
+}
+
+// setDefaults fills in default values for unset options. Unlike
+// `validate`, it does not require options used for fetching
+// configuration from Eppo (e.g., SDK key).
+func (cfg *Config) setDefaults() error {
 	if cfg.BaseUrl == "" {
 		cfg.BaseUrl = defaultBaseUrl
 	}
@@ -34,6 +116,18 @@ func (cfg *Config) validate() error {
 		cfg.PollerInterval = defaultPollerInterval
 	}
 
+	if cfg.PollerJitter == 0 {
+		cfg.PollerJitter = cfg.PollerInterval / 10
+	}
+
+	if cfg.PollerMaxBackoff == 0 {
+		cfg.PollerMaxBackoff = defaultPollerMaxBackoff
+	}
+
+	if cfg.OverridesReloadInterval <= 0 {
+		cfg.OverridesReloadInterval = defaultOverridesReloadInterval
+	}
+
 	if cfg.ApplicationLogger == nil {
 		defaultLogger, err := zap.NewProduction(zap.IncreaseLevel(zap.WarnLevel))
 		if err != nil {
@@ -16,3 +16,30 @@ func Test_config_defaultPollerInterval(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, 10*time.Second, cfg.PollerInterval)
 }
+
+func Test_config_defaultPollingPolicy(t *testing.T) {
+	cfg := Config{
+		SdkKey: "blah",
+	}
+
+	err := cfg.validate()
+	assert.NoError(t, err)
+	assert.Equal(t, pollingPolicy{
+		interval:   10 * time.Second,
+		jitter:     1 * time.Second,
+		maxBackoff: 5 * time.Minute,
+	}, cfg.pollingPolicy())
+}
+
+func Test_config_disabledJitterAndBackoff(t *testing.T) {
+	cfg := Config{
+		SdkKey:           "blah",
+		PollerInterval:   time.Minute,
+		PollerJitter:     -1,
+		PollerMaxBackoff: -1,
+	}
+
+	err := cfg.validate()
+	assert.NoError(t, err)
+	assert.Equal(t, pollingPolicy{interval: time.Minute}, cfg.pollingPolicy())
+}
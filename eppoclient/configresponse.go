@@ -3,18 +3,26 @@ package eppoclient
 import (
 	"encoding/json"
 	"fmt"
+	"sync"
 	"time"
 
 	semver "github.com/Masterminds/semver/v3"
 )
 
 type configResponse struct {
+	// Either "SERVER" (plaintext) or "CLIENT" (obfuscated, see
+	// configFormatClient). Empty is treated as "SERVER".
+	Format  string                        `json:"format,omitempty"`
 	Flags   map[string]*flagConfiguration `json:"flags"`
 	Bandits map[string][]banditVariation  `json:"bandits,omitempty"`
 }
 
 func (response *configResponse) precompute() {
+	obfuscated := response.isObfuscated()
 	for i := range response.Flags {
+		if obfuscated {
+			response.Flags[i].deobfuscate()
+		}
 		response.Flags[i].precompute()
 	}
 }
@@ -35,6 +43,10 @@ type flagConfiguration struct {
 	// - BOOLEAN -> bool
 	// - JSON -> jsonVariationValue
 	ParsedVariations map[string]interface{} `json:"-"`
+
+	// Set after obfuscated fields have been decoded, so they are
+	// not decoded twice.
+	deobfuscated bool
 }
 
 func (flag *flagConfiguration) precompute() {
@@ -59,6 +71,9 @@ type variation struct {
 type jsonVariationValue struct {
 	Raw    []byte
 	Parsed interface{}
+	// Cache of `Raw` decoded into user-supplied Go types (see
+	// `GetAssignment`). reflect.Type -> decoded value.
+	decoded *sync.Map
 }
 
 type variationType int
@@ -71,6 +86,35 @@ const (
 	jsonVariation
 )
 
+// VariationType is the type of flag variation values as configured
+// in Eppo.
+type VariationType string
+
+const (
+	VariationTypeString  VariationType = "STRING"
+	VariationTypeInteger VariationType = "INTEGER"
+	VariationTypeNumeric VariationType = "NUMERIC"
+	VariationTypeBoolean VariationType = "BOOLEAN"
+	VariationTypeJSON    VariationType = "JSON"
+)
+
+func (v variationType) toPublic() VariationType {
+	switch v {
+	case stringVariation:
+		return VariationTypeString
+	case integerVariation:
+		return VariationTypeInteger
+	case numericVariation:
+		return VariationTypeNumeric
+	case booleanVariation:
+		return VariationTypeBoolean
+	case jsonVariation:
+		return VariationTypeJSON
+	default:
+		return ""
+	}
+}
+
 func (v variationType) MarshalJSON() ([]byte, error) {
 	switch v {
 	case stringVariation:
@@ -157,7 +201,7 @@ func (ty variationType) parseVariationValue(value json.RawMessage) (interface{},
 			return nil, err
 		}
 
-		return jsonVariationValue{raw, parsed}, nil
+		return jsonVariationValue{Raw: raw, Parsed: parsed, decoded: &sync.Map{}}, nil
 	default:
 		return nil, fmt.Errorf("unexpected variation type: %v", ty)
 	}
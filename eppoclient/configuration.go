@@ -1,5 +1,11 @@
 package eppoclient
 
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
 type configuration struct {
 	flags   configResponse
 	bandits banditResponse
@@ -8,6 +14,66 @@ type configuration struct {
 	// This is cached from `flags` field for easier access in
 	// evaluation.
 	banditFlagAssociations map[string]map[string]banditVariation
+
+	// Time when configuration was fetched from the server.
+	fetchedAt time.Time
+	// Whether configuration was loaded from on-disk cache.
+	fromCache bool
+}
+
+// ConfigurationMetadata describes the currently active configuration.
+type ConfigurationMetadata struct {
+	// Time when configuration was fetched from Eppo. Zero if the
+	// client has no configuration yet.
+	FetchedAt time.Time
+	// Whether configuration was loaded from on-disk cache (see
+	// Config.ConfigurationCacheDir) and has not been refreshed
+	// from the network yet.
+	FromCache bool
+}
+
+// Age returns time elapsed since configuration was fetched.
+func (m ConfigurationMetadata) Age() time.Duration {
+	if m.FetchedAt.IsZero() {
+		return 0
+	}
+	return time.Since(m.FetchedAt)
+}
+
+// newConfigurationFromJSON parses UFC flags configuration and,
+// optionally, bandit models. `banditsJSON` may be nil if there are
+// no bandits.
+func newConfigurationFromJSON(flagsJSON, banditsJSON []byte) (configuration, error) {
+	var config configuration
+
+	err := json.Unmarshal(flagsJSON, &config.flags)
+	if err != nil {
+		return configuration{}, fmt.Errorf("failed to parse flags configuration: %w", err)
+	}
+	if config.flags.Flags == nil {
+		return configuration{}, fmt.Errorf("failed to parse flags configuration: missing \"flags\" field")
+	}
+
+	config.fetchedAt = time.Now()
+
+	if len(banditsJSON) > 0 {
+		err = json.Unmarshal(banditsJSON, &config.bandits)
+		if err != nil {
+			return configuration{}, fmt.Errorf("failed to parse bandits configuration: %w", err)
+		}
+	}
+
+	return config, nil
+}
+
+// validate runs validateConfiguration on the decoded configuration.
+// `hasBandits` tells whether bandits configuration has been provided.
+func (c *configuration) validate(hasBandits bool, applicationLogger ApplicationLogger) error {
+	var bandits *banditResponse
+	if hasBandits {
+		bandits = &c.bandits
+	}
+	return validateConfiguration(&c.flags, bandits, applicationLogger)
 }
 
 func (c *configuration) precompute() {
@@ -38,7 +104,25 @@ func (c configuration) getBanditVariant(flagKey, variation string) (result bandi
 	return result, ok
 }
 
+// flagKeys returns plaintext keys of all flags. Keys of obfuscated
+// configuration are hashed, so ErrObfuscatedConfiguration is
+// returned instead.
+func (c configuration) flagKeys() ([]string, error) {
+	if c.flags.isObfuscated() {
+		return nil, ErrObfuscatedConfiguration
+	}
+	keys := make([]string, 0, len(c.flags.Flags))
+	for key := range c.flags.Flags {
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
 func (c configuration) getFlagConfiguration(key string) (*flagConfiguration, error) {
+	if c.flags.isObfuscated() {
+		return c.getObfuscatedFlagConfiguration(key)
+	}
+
 	flag, ok := c.flags.Flags[key]
 	if !ok {
 		return nil, ErrFlagConfigurationNotFound
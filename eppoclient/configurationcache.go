@@ -0,0 +1,156 @@
+package eppoclient
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Version of the cache file format. Cache files with a different
+// version are ignored.
+const configurationCacheVersion = 1
+
+var errConfigurationCacheOutdated = errors.New("configuration cache is outdated")
+
+// `configurationCache` persists the last fetched configuration on disk
+// so that it can be used on startup before the first fetch completes.
+type configurationCache struct {
+	path   string
+	maxAge time.Duration
+}
+
+type configurationCacheFile struct {
+	Version    int             `json:"version"`
+	SdkVersion string          `json:"sdkVersion"`
+	FetchedAt  time.Time       `json:"fetchedAt"`
+	Checksum   string          `json:"checksum"`
+	Flags      json.RawMessage `json:"flags"`
+	Bandits    json.RawMessage `json:"bandits,omitempty"`
+}
+
+// newConfigurationCache creates a cache storing configuration in
+// `dir`. The file name is derived from the SDK key, so clients using
+// different keys do not overwrite each other's cache.
+//
+// Cache files older than `maxAge` are ignored. Zero `maxAge` means
+// no limit.
+func newConfigurationCache(dir string, sdkKey string, maxAge time.Duration) *configurationCache {
+	keyHash := sha256.Sum256([]byte(sdkKey))
+	fileName := "eppo-configuration-" + hex.EncodeToString(keyHash[:8]) + ".json"
+	return &configurationCache{
+		path:   filepath.Join(dir, fileName),
+		maxAge: maxAge,
+	}
+}
+
+// load reads configuration from the cache file. Returns an error if
+// the file is missing, corrupt, or outdated.
+func (c *configurationCache) load() (configuration, error) {
+	data, err := os.ReadFile(c.path)
+	if err != nil {
+		return configuration{}, err
+	}
+
+	var file configurationCacheFile
+	err = json.Unmarshal(data, &file)
+	if err != nil {
+		return configuration{}, fmt.Errorf("corrupt configuration cache: %w", err)
+	}
+
+	if file.Version != configurationCacheVersion {
+		return configuration{}, fmt.Errorf("%w: unsupported version %d", errConfigurationCacheOutdated, file.Version)
+	}
+	if file.Checksum != configurationChecksum(file.Flags, file.Bandits) {
+		return configuration{}, fmt.Errorf("corrupt configuration cache: checksum mismatch")
+	}
+	if c.maxAge > 0 && time.Since(file.FetchedAt) > c.maxAge {
+		return configuration{}, fmt.Errorf("%w: fetched at %v", errConfigurationCacheOutdated, file.FetchedAt)
+	}
+
+	config, err := newConfigurationFromJSON(file.Flags, file.Bandits)
+	if err != nil {
+		return configuration{}, fmt.Errorf("corrupt configuration cache: %w", err)
+	}
+	err = config.validate(len(file.Bandits) > 0, nil)
+	if err != nil {
+		return configuration{}, fmt.Errorf("invalid configuration cache: %w", err)
+	}
+	config.fetchedAt = file.FetchedAt
+	config.fromCache = true
+
+	return config, nil
+}
+
+// store atomically replaces the cache file with the given payload.
+func (c *configurationCache) store(payload configurationPayload, fetchedAt time.Time) error {
+	var bandits json.RawMessage
+	if len(payload.bandits) > 0 {
+		bandits = payload.bandits
+	}
+
+	data, err := json.Marshal(configurationCacheFile{
+		Version:    configurationCacheVersion,
+		SdkVersion: __version__,
+		FetchedAt:  fetchedAt.UTC(),
+		Checksum:   configurationChecksum(payload.flags, bandits),
+		Flags:      payload.flags,
+		Bandits:    bandits,
+	})
+	if err != nil {
+		return err
+	}
+
+	dir := filepath.Dir(c.path)
+	err = os.MkdirAll(dir, 0o755)
+	if err != nil {
+		return err
+	}
+
+	// Write to a temporary file and rename it, so readers never
+	// observe a partially written cache.
+	tmp, err := os.CreateTemp(dir, filepath.Base(c.path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	_, err = tmp.Write(data)
+	if err == nil {
+		err = tmp.Sync()
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), c.path)
+}
+
+func configurationChecksum(flags, bandits []byte) string {
+	hash := sha256.New()
+	// json.Marshal compacts embedded json.RawMessage, so compact
+	// payloads before hashing to get a stable checksum.
+	hash.Write(compactJSON(flags))
+	hash.Write([]byte{0})
+	hash.Write(compactJSON(bandits))
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+func compactJSON(data []byte) []byte {
+	if len(data) == 0 {
+		return nil
+	}
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, data); err != nil {
+		return data
+	}
+	return buf.Bytes()
+}
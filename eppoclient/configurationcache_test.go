@@ -0,0 +1,122 @@
+package eppoclient
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_configurationCache_storeAndLoad(t *testing.T) {
+	cache := newConfigurationCache(t.TempDir(), "sdk-key", 0)
+	fetchedAt := time.Now().Add(-time.Minute)
+
+	err := cache.store(configurationPayload{flags: []byte(initClientTestFlags)}, fetchedAt)
+	assert.NoError(t, err)
+
+	config, err := cache.load()
+	assert.NoError(t, err)
+	assert.True(t, config.fromCache)
+	assert.True(t, fetchedAt.Equal(config.fetchedAt))
+	assert.Contains(t, config.flags.Flags, "flag")
+}
+
+func Test_configurationCache_perSdkKey(t *testing.T) {
+	dir := t.TempDir()
+	err := newConfigurationCache(dir, "sdk-key", 0).store(configurationPayload{flags: []byte(initClientTestFlags)}, time.Now())
+	assert.NoError(t, err)
+
+	_, err = newConfigurationCache(dir, "other-sdk-key", 0).load()
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func Test_configurationCache_ignoresCorruptCache(t *testing.T) {
+	cache := newConfigurationCache(t.TempDir(), "sdk-key", 0)
+	err := cache.store(configurationPayload{flags: []byte(initClientTestFlags)}, time.Now())
+	assert.NoError(t, err)
+
+	data, err := os.ReadFile(cache.path)
+	assert.NoError(t, err)
+
+	// truncated file
+	assert.NoError(t, os.WriteFile(cache.path, data[:len(data)/2], 0o644))
+	_, err = cache.load()
+	assert.Error(t, err)
+
+	// tampered payload
+	tampered := strings.Replace(string(data), `"on"`, `"of"`, 1)
+	assert.NoError(t, os.WriteFile(cache.path, []byte(tampered), 0o644))
+	_, err = cache.load()
+	assert.ErrorContains(t, err, "checksum mismatch")
+}
+
+func Test_configurationCache_ignoresOutdatedCache(t *testing.T) {
+	cache := newConfigurationCache(t.TempDir(), "sdk-key", time.Hour)
+	err := cache.store(configurationPayload{flags: []byte(initClientTestFlags)}, time.Now().Add(-2*time.Hour))
+	assert.NoError(t, err)
+
+	_, err = cache.load()
+	assert.ErrorIs(t, err, errConfigurationCacheOutdated)
+
+	err = os.WriteFile(cache.path, []byte(`{"version": 0, "flags": {"flags": {}}}`), 0o644)
+	assert.NoError(t, err)
+
+	_, err = cache.load()
+	assert.ErrorIs(t, err, errConfigurationCacheOutdated)
+}
+
+func Test_InitClient_loadsConfigurationCache(t *testing.T) {
+	dir := t.TempDir()
+	err := newConfigurationCache(dir, "sdk-key", 0).store(configurationPayload{flags: []byte(initClientTestFlags)}, time.Now())
+	assert.NoError(t, err)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	client, err := InitClient(Config{
+		BaseUrl:               server.URL,
+		SdkKey:                "sdk-key",
+		ApplicationLogger:     applicationLogger,
+		ConfigurationCacheDir: dir,
+	})
+	assert.NoError(t, err)
+	defer client.poller.Stop()
+
+	select {
+	case <-client.Initialized():
+	default:
+		t.Fatal("client is not initialized from cache")
+	}
+	assert.True(t, client.ConfigurationMetadata().FromCache)
+
+	value, err := client.GetStringAssignment("flag", "subject", Attributes{}, "default")
+	assert.NoError(t, err)
+	assert.Equal(t, "on", value)
+}
+
+func Test_configurationRequestor_writesConfigurationCache(t *testing.T) {
+	var flags configResponse
+	assert.NoError(t, json.Unmarshal([]byte(initClientTestFlags), &flags))
+	server := newTestServer(flags, banditResponse{})
+	defer server.Close()
+
+	cache := newConfigurationCache(t.TempDir(), "sdk-key", 0)
+	sdkParams := SDKParams{sdkKey: "sdk-key", sdkName: "go", sdkVersion: __version__}
+	httpClient := newHttpClient(server.URL, &http.Client{Timeout: REQUEST_TIMEOUT_SECONDS}, sdkParams)
+	configurationStore := newConfigurationStore()
+	requestor := newConfigurationRequestor(*httpClient, configurationStore, applicationLogger, cache)
+
+	requestor.FetchAndStoreConfigurations()
+	assert.False(t, configurationStore.getConfiguration().fromCache)
+
+	config, err := cache.load()
+	assert.NoError(t, err)
+	assert.Contains(t, config.flags.Flags, "flag")
+}
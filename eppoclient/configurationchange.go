@@ -0,0 +1,226 @@
+package eppoclient
+
+import (
+	"bytes"
+	"reflect"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ConfigurationChange describes the difference between two
+// consecutive configurations.
+type ConfigurationChange struct {
+	// Time the new configuration was fetched.
+	FetchedAt time.Time
+	// Changed flags sorted by key.
+	Flags []FlagChange
+	// Changed bandits sorted by key.
+	Bandits []BanditChange
+}
+
+// FlagChange describes how a single flag has changed.
+type FlagChange struct {
+	FlagKey string `json:"flagKey"`
+	// Flag is present in the new configuration only.
+	Added bool `json:"added,omitempty"`
+	// Flag is present in the old configuration only.
+	Removed bool `json:"removed,omitempty"`
+	// Flag was disabled and is now enabled.
+	Enabled bool `json:"enabled,omitempty"`
+	// Flag was enabled and is now disabled.
+	Disabled bool `json:"disabled,omitempty"`
+	// Allocations (targeting rules, splits, or schedule) or total
+	// shards have changed.
+	AllocationsChanged bool `json:"allocationsChanged,omitempty"`
+	// Keys of variations that have been added, removed, or whose
+	// values have changed, sorted.
+	VariationsChanged []string `json:"variationsChanged,omitempty"`
+}
+
+// BanditChange describes how a bandit model has changed. Previous
+// version is empty if the bandit has been added; new version is
+// empty if it has been removed.
+type BanditChange struct {
+	BanditKey            string
+	PreviousModelVersion string
+	ModelVersion         string
+}
+
+// IsEmpty returns true if nothing has changed.
+func (c ConfigurationChange) IsEmpty() bool {
+	return len(c.Flags) == 0 && len(c.Bandits) == 0
+}
+
+// diffConfigurations computes the change from `old` to `new`.
+func diffConfigurations(old, new configuration) ConfigurationChange {
+	change := ConfigurationChange{FetchedAt: new.fetchedAt}
+
+	for key, newFlag := range new.flags.Flags {
+		oldFlag, ok := old.flags.Flags[key]
+		if !ok {
+			change.Flags = append(change.Flags, FlagChange{FlagKey: key, Added: true})
+			continue
+		}
+		if flagChange, changed := diffFlags(oldFlag, newFlag); changed {
+			change.Flags = append(change.Flags, flagChange)
+		}
+	}
+	for key := range old.flags.Flags {
+		if _, ok := new.flags.Flags[key]; !ok {
+			change.Flags = append(change.Flags, FlagChange{FlagKey: key, Removed: true})
+		}
+	}
+	sort.Slice(change.Flags, func(i, j int) bool {
+		return change.Flags[i].FlagKey < change.Flags[j].FlagKey
+	})
+
+	for key, newBandit := range new.bandits.Bandits {
+		oldBandit, ok := old.bandits.Bandits[key]
+		if !ok || oldBandit.ModelVersion != newBandit.ModelVersion {
+			change.Bandits = append(change.Bandits, BanditChange{
+				BanditKey:            key,
+				PreviousModelVersion: oldBandit.ModelVersion,
+				ModelVersion:         newBandit.ModelVersion,
+			})
+		}
+	}
+	for key, oldBandit := range old.bandits.Bandits {
+		if _, ok := new.bandits.Bandits[key]; !ok {
+			change.Bandits = append(change.Bandits, BanditChange{
+				BanditKey:            key,
+				PreviousModelVersion: oldBandit.ModelVersion,
+			})
+		}
+	}
+	sort.Slice(change.Bandits, func(i, j int) bool {
+		return change.Bandits[i].BanditKey < change.Bandits[j].BanditKey
+	})
+
+	return change
+}
+
+func diffFlags(old, new *flagConfiguration) (FlagChange, bool) {
+	change := FlagChange{
+		FlagKey:            new.Key,
+		Enabled:            !old.Enabled && new.Enabled,
+		Disabled:           old.Enabled && !new.Enabled,
+		AllocationsChanged: old.TotalShards != new.TotalShards || !reflect.DeepEqual(old.Allocations, new.Allocations),
+	}
+
+	for key, newVariation := range new.Variations {
+		oldVariation, ok := old.Variations[key]
+		if !ok || !bytes.Equal(compactJSON(oldVariation.Value), compactJSON(newVariation.Value)) {
+			change.VariationsChanged = append(change.VariationsChanged, key)
+		}
+	}
+	for key := range old.Variations {
+		if _, ok := new.Variations[key]; !ok {
+			change.VariationsChanged = append(change.VariationsChanged, key)
+		}
+	}
+	sort.Strings(change.VariationsChanged)
+
+	changed := change.Enabled || change.Disabled || change.AllocationsChanged ||
+		len(change.VariationsChanged) > 0 || old.VariationType != new.VariationType
+	return change, changed
+}
+
+// `configurationListener` delivers configuration changes to a
+// callback on a dedicated goroutine, so slow callbacks never block
+// configuration updates.
+//
+// If the callback is busy while several updates arrive, they are
+// coalesced into a single change from the oldest undelivered
+// configuration to the latest one.
+type configurationListener struct {
+	callback          func(ConfigurationChange)
+	applicationLogger ApplicationLogger
+
+	// `mu` guards `from` and `to`.
+	mu   sync.Mutex
+	from *configuration
+	to   *configuration
+
+	notifyCh chan struct{}
+	stopCh   chan struct{}
+	stopOnce sync.Once
+}
+
+func newConfigurationListener(callback func(ConfigurationChange), applicationLogger ApplicationLogger) *configurationListener {
+	listener := &configurationListener{
+		callback:          callback,
+		applicationLogger: applicationLogger,
+		notifyCh:          make(chan struct{}, 1),
+		stopCh:            make(chan struct{}),
+	}
+	go listener.run()
+	return listener
+}
+
+// notify schedules delivery of the change from `old` to `new`. Never
+// blocks.
+func (l *configurationListener) notify(old, new *configuration) {
+	l.mu.Lock()
+	if l.to == nil {
+		l.from = old
+	}
+	l.to = new
+	l.mu.Unlock()
+
+	select {
+	case l.notifyCh <- struct{}{}:
+	default:
+		// listener is already notified
+	}
+}
+
+func (l *configurationListener) stop() {
+	l.stopOnce.Do(func() { close(l.stopCh) })
+}
+
+func (l *configurationListener) run() {
+	for {
+		select {
+		case <-l.stopCh:
+			return
+		case <-l.notifyCh:
+		}
+
+		l.mu.Lock()
+		from, to := l.from, l.to
+		l.from, l.to = nil, nil
+		l.mu.Unlock()
+
+		if to == nil {
+			continue
+		}
+		if to.flags.isObfuscated() {
+			if l.applicationLogger != nil {
+				l.applicationLogger.Error("configuration change is not reported: flag keys of obfuscated configuration are not known")
+			}
+			continue
+		}
+		var old configuration
+		if from != nil && !from.flags.isObfuscated() {
+			old = *from
+		}
+
+		change := diffConfigurations(old, *to)
+		if !change.IsEmpty() {
+			l.deliver(change)
+		}
+	}
+}
+
+func (l *configurationListener) deliver(change ConfigurationChange) {
+	// need to catch panics from callback and continue
+	defer func() {
+		r := recover()
+		if r != nil && l.applicationLogger != nil {
+			l.applicationLogger.Errorf("panic occurred in configuration change listener: %v", r)
+		}
+	}()
+
+	l.callback(change)
+}
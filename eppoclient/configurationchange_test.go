@@ -0,0 +1,158 @@
+package eppoclient
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_diffConfigurations(t *testing.T) {
+	old := newPrecomputedTestConfiguration()
+	new := newPrecomputedTestConfiguration()
+
+	// json-flag: variation value changed
+	new.flags.Flags["json-flag"].Variations = map[string]variation{"on": {Key: "on", Value: []byte(`"{\"a\": 2}"`)}}
+	// bool-flag: disabled
+	new.flags.Flags["bool-flag"].Enabled = false
+	// targeted-flag: targeting changed
+	new.flags.Flags["targeted-flag"].Allocations[0].Rules[0].Conditions[0].Value = []string{"another-subject"}
+	// disabled-flag: removed
+	delete(new.flags.Flags, "disabled-flag")
+	// new-flag: added
+	new.flags.Flags["new-flag"] = &flagConfiguration{Key: "new-flag"}
+	// bandit: new model version
+	new.bandits.Bandits = map[string]banditConfiguration{
+		"bandit": {BanditKey: "bandit", ModelVersion: "v124"},
+	}
+
+	change := diffConfigurations(old, new)
+
+	assert.Equal(t, []FlagChange{
+		{FlagKey: "bool-flag", Disabled: true},
+		{FlagKey: "disabled-flag", Removed: true},
+		{FlagKey: "json-flag", VariationsChanged: []string{"on"}},
+		{FlagKey: "new-flag", Added: true},
+		{FlagKey: "targeted-flag", AllocationsChanged: true},
+	}, change.Flags)
+	assert.Equal(t, []BanditChange{
+		{BanditKey: "bandit", PreviousModelVersion: "v123", ModelVersion: "v124"},
+	}, change.Bandits)
+}
+
+func Test_diffConfigurations_totalShards(t *testing.T) {
+	old := newPrecomputedTestConfiguration()
+	new := newPrecomputedTestConfiguration()
+	// Shard ranges are relative to totalShards, so changing it
+	// reassigns subjects.
+	new.flags.Flags["bool-flag"].TotalShards *= 2
+
+	change := diffConfigurations(old, new)
+	assert.Equal(t, []FlagChange{{FlagKey: "bool-flag", AllocationsChanged: true}}, change.Flags)
+}
+
+func Test_diffConfigurations_unchanged(t *testing.T) {
+	change := diffConfigurations(newPrecomputedTestConfiguration(), newPrecomputedTestConfiguration())
+	assert.True(t, change.IsEmpty())
+}
+
+func Test_OnConfigurationChange(t *testing.T) {
+	client, err := InitClientFromConfiguration(Config{ApplicationLogger: applicationLogger}, []byte(`{"flags": {}}`), nil)
+	assert.NoError(t, err)
+
+	changes := make(chan ConfigurationChange, 10)
+	unsubscribe := client.OnConfigurationChange(func(change ConfigurationChange) {
+		changes <- change
+	})
+
+	assert.NoError(t, client.SetConfiguration([]byte(initClientTestFlags), nil))
+	change := receiveChange(t, changes)
+	assert.Equal(t, []FlagChange{{FlagKey: "flag", Added: true}}, change.Flags)
+
+	// identical configuration is not reported
+	assert.NoError(t, client.SetConfiguration([]byte(initClientTestFlags), nil))
+	assert.NoError(t, client.SetConfiguration([]byte(`{"flags": {}}`), nil))
+	change = receiveChange(t, changes)
+	assert.Equal(t, []FlagChange{{FlagKey: "flag", Removed: true}}, change.Flags)
+
+	unsubscribe()
+	assert.NoError(t, client.SetConfiguration([]byte(initClientTestFlags), nil))
+	select {
+	case change := <-changes:
+		t.Fatalf("unexpected change after unsubscribe: %v", change)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func Test_OnConfigurationChange_doesNotBlockUpdates(t *testing.T) {
+	client, err := InitClientFromConfiguration(Config{ApplicationLogger: applicationLogger}, []byte(`{"flags": {}}`), nil)
+	assert.NoError(t, err)
+	defer client.configurationStore.unsubscribeAll()
+
+	release := make(chan struct{})
+	changes := make(chan ConfigurationChange, 10)
+	client.OnConfigurationChange(func(change ConfigurationChange) {
+		<-release
+		changes <- change
+	})
+
+	// These updates must not wait for the blocked listener.
+	done := make(chan struct{})
+	go func() {
+		for i := 0; i < 10; i++ {
+			assert.NoError(t, client.SetConfiguration([]byte(initClientTestFlags), nil))
+			assert.NoError(t, client.SetConfiguration([]byte(`{"flags": {}}`), nil))
+		}
+		assert.NoError(t, client.SetConfiguration([]byte(initClientTestFlags), nil))
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("configuration updates are blocked by listener")
+	}
+
+	close(release)
+	// Updates are coalesced, so the listener eventually observes
+	// the latest configuration.
+	var last ConfigurationChange
+	assert.Eventually(t, func() bool {
+		for {
+			select {
+			case last = <-changes:
+			default:
+				return len(last.Flags) == 1 && last.Flags[0].Added
+			}
+		}
+	}, time.Second, 10*time.Millisecond)
+}
+
+func Test_OnConfigurationChange_recoversFromPanic(t *testing.T) {
+	client, err := InitClientFromConfiguration(Config{ApplicationLogger: applicationLogger}, []byte(`{"flags": {}}`), nil)
+	assert.NoError(t, err)
+	defer client.configurationStore.unsubscribeAll()
+
+	changes := make(chan ConfigurationChange, 10)
+	client.OnConfigurationChange(func(change ConfigurationChange) {
+		changes <- change
+		panic("listener panic")
+	})
+
+	assert.NoError(t, client.SetConfiguration([]byte(initClientTestFlags), nil))
+	receiveChange(t, changes)
+
+	// listener keeps working after panic
+	assert.NoError(t, client.SetConfiguration([]byte(`{"flags": {}}`), nil))
+	receiveChange(t, changes)
+}
+
+func receiveChange(t *testing.T, changes <-chan ConfigurationChange) ConfigurationChange {
+	t.Helper()
+	select {
+	case change := <-changes:
+		return change
+	case <-time.After(time.Second):
+		t.Fatal("configuration change not received")
+		return ConfigurationChange{}
+	}
+}
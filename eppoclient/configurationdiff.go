@@ -0,0 +1,228 @@
+package eppoclient
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"time"
+)
+
+// ConfigurationDiff is a detailed comparison of two flags
+// configurations. See DiffConfigurations.
+type ConfigurationDiff struct {
+	// Changed flags sorted by key.
+	Flags []FlagDiff `json:"flags"`
+}
+
+// FlagDiff describes how a single flag differs between two
+// configurations.
+type FlagDiff struct {
+	FlagChange
+	// Individual differences sorted by path. Empty for added and
+	// removed flags.
+	Differences []Difference `json:"differences,omitempty"`
+}
+
+// Difference is a single changed value of a flag.
+type Difference struct {
+	// Location of the value within the flag. Allocations are
+	// addressed by key, e.g.,
+	// "allocations.rollout.splits[0].shards[0].ranges[0].end", while
+	// "allocations" itself lists allocation keys in evaluation order.
+	Path string `json:"path"`
+	// Values are JSON-like: nil, bool, float64, string,
+	// []interface{}, or map[string]interface{}. Old is nil if the
+	// value has been added and New is nil if it has been removed.
+	Old interface{} `json:"old"`
+	New interface{} `json:"new"`
+}
+
+// DiffConfigurations compares flags of two configurations (as served
+// at CONFIG_ENDPOINT) and reports what has changed in each flag:
+// state, variation values, allocation order, rules, schedule, splits,
+// and shard ranges.
+//
+// Obfuscated configurations are compared after decoding, except that
+// flag keys stay hashed.
+func DiffConfigurations(oldFlagsJSON, newFlagsJSON []byte) (ConfigurationDiff, error) {
+	oldConfig, newConfig, err := parseConfigurationPair(oldFlagsJSON, newFlagsJSON)
+	if err != nil {
+		return ConfigurationDiff{}, err
+	}
+	change := diffConfigurations(oldConfig, newConfig)
+	diff := ConfigurationDiff{Flags: make([]FlagDiff, 0, len(change.Flags))}
+	for _, flagChange := range change.Flags {
+		flagDiff := FlagDiff{FlagChange: flagChange}
+		oldFlag, newFlag := oldConfig.flags.Flags[flagChange.FlagKey], newConfig.flags.Flags[flagChange.FlagKey]
+		if oldFlag != nil && newFlag != nil {
+			flagDiff.Differences = diffFlagValues(oldFlag, newFlag)
+		}
+		diff.Flags = append(diff.Flags, flagDiff)
+	}
+	return diff, nil
+}
+
+func parseConfigurationPair(oldFlagsJSON, newFlagsJSON []byte) (oldConfig, newConfig configuration, err error) {
+	oldConfig, err = newConfigurationFromJSON(oldFlagsJSON, nil)
+	if err != nil {
+		return oldConfig, newConfig, fmt.Errorf("old configuration: %w", err)
+	}
+	newConfig, err = newConfigurationFromJSON(newFlagsJSON, nil)
+	if err != nil {
+		return oldConfig, newConfig, fmt.Errorf("new configuration: %w", err)
+	}
+	oldConfig.precompute()
+	newConfig.precompute()
+	return oldConfig, newConfig, nil
+}
+
+func diffFlagValues(old, new *flagConfiguration) []Difference {
+	var differences []Difference
+	diffValues("", old.comparable(), new.comparable(), &differences)
+
+	oldAllocations, newAllocations := old.comparableAllocations(), new.comparableAllocations()
+	if oldKeys, newKeys := allocationKeys(old), allocationKeys(new); !reflect.DeepEqual(oldKeys, newKeys) {
+		differences = append(differences, Difference{Path: "allocations", Old: oldKeys, New: newKeys})
+	}
+	for key, newAllocation := range newAllocations {
+		diffValues("allocations."+key, oldAllocations[key], newAllocation, &differences)
+	}
+	for key, oldAllocation := range oldAllocations {
+		if _, ok := newAllocations[key]; !ok {
+			differences = append(differences, Difference{Path: "allocations." + key, Old: oldAllocation})
+		}
+	}
+
+	sort.Slice(differences, func(i, j int) bool {
+		return differences[i].Path < differences[j].Path
+	})
+	return differences
+}
+
+// diffValues appends differences between two JSON-like values.
+// Maps are compared key by key and lists of equal length element by
+// element; other values are compared as a whole.
+func diffValues(path string, old, new interface{}, differences *[]Difference) {
+	switch oldValue := old.(type) {
+	case map[string]interface{}:
+		newValue, ok := new.(map[string]interface{})
+		if !ok {
+			break
+		}
+		for key, n := range newValue {
+			diffValues(joinPath(path, key), oldValue[key], n, differences)
+		}
+		for key, o := range oldValue {
+			if _, ok := newValue[key]; !ok {
+				*differences = append(*differences, Difference{Path: joinPath(path, key), Old: o})
+			}
+		}
+		return
+	case []interface{}:
+		newValue, ok := new.([]interface{})
+		if !ok || len(oldValue) != len(newValue) {
+			break
+		}
+		for i := range oldValue {
+			diffValues(fmt.Sprintf("%s[%d]", path, i), oldValue[i], newValue[i], differences)
+		}
+		return
+	}
+
+	if !reflect.DeepEqual(old, new) {
+		*differences = append(*differences, Difference{Path: path, Old: old, New: new})
+	}
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+func allocationKeys(flag *flagConfiguration) []interface{} {
+	keys := make([]interface{}, len(flag.Allocations))
+	for i, a := range flag.Allocations {
+		keys[i] = a.Key
+	}
+	return keys
+}
+
+// comparable converts flag fields other than allocations into
+// JSON-like values for diffValues.
+func (flag *flagConfiguration) comparable() map[string]interface{} {
+	variations := make(map[string]interface{}, len(flag.Variations))
+	for key, v := range flag.Variations {
+		var value interface{}
+		if err := json.Unmarshal(v.Value, &value); err != nil {
+			value = string(v.Value)
+		}
+		variations[key] = value
+	}
+	return map[string]interface{}{
+		"enabled":       flag.Enabled,
+		"variationType": string(flag.VariationType.toPublic()),
+		"totalShards":   float64(flag.TotalShards),
+		"variations":    variations,
+	}
+}
+
+// comparableAllocations converts allocations into JSON-like values
+// for diffValues, keyed by allocation key.
+func (flag *flagConfiguration) comparableAllocations() map[string]interface{} {
+	allocations := make(map[string]interface{}, len(flag.Allocations))
+	for _, a := range flag.Allocations {
+		rules := make([]interface{}, len(a.Rules))
+		for i, r := range a.Rules {
+			conditions := make([]interface{}, len(r.Conditions))
+			for j, c := range r.Conditions {
+				conditions[j] = map[string]interface{}{
+					"attribute": c.Attribute,
+					"operator":  c.Operator,
+					"value":     c.Value,
+				}
+			}
+			rules[i] = map[string]interface{}{"conditions": conditions}
+		}
+
+		splits := make([]interface{}, len(a.Splits))
+		for i, s := range a.Splits {
+			shards := make([]interface{}, len(s.Shards))
+			for j, sh := range s.Shards {
+				ranges := make([]interface{}, len(sh.Ranges))
+				for k, r := range sh.Ranges {
+					ranges[k] = map[string]interface{}{"start": float64(r.Start), "end": float64(r.End)}
+				}
+				shards[j] = map[string]interface{}{"salt": sh.Salt, "ranges": ranges}
+			}
+			extraLogging := make(map[string]interface{}, len(s.ExtraLogging))
+			for key, value := range s.ExtraLogging {
+				extraLogging[key] = value
+			}
+			splits[i] = map[string]interface{}{
+				"variationKey": s.VariationKey,
+				"shards":       shards,
+				"extraLogging": extraLogging,
+			}
+		}
+
+		doLog := a.DoLog == nil || *a.DoLog
+		allocations[a.Key] = map[string]interface{}{
+			"rules":   rules,
+			"startAt": comparableTime(a.StartAt),
+			"endAt":   comparableTime(a.EndAt),
+			"splits":  splits,
+			"doLog":   doLog,
+		}
+	}
+	return allocations
+}
+
+func comparableTime(t time.Time) interface{} {
+	if t.IsZero() {
+		return nil
+	}
+	return t.UTC().Format(time.RFC3339Nano)
+}
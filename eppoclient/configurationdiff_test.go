@@ -0,0 +1,176 @@
+package eppoclient
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// diffTestFlags returns a configuration with a "rollout" flag serving
+// "on" to internal users and to shards [0, rolloutEnd) of everyone
+// else.
+func diffTestFlags(rolloutEnd int, extraFlags string) []byte {
+	return []byte(fmt.Sprintf(`{
+  "flags": {
+    %s
+    "rollout": {
+      "key": "rollout",
+      "enabled": true,
+      "variationType": "BOOLEAN",
+      "totalShards": 10000,
+      "variations": {"on": {"key": "on", "value": true}, "off": {"key": "off", "value": false}},
+      "allocations": [
+        {
+          "key": "internal",
+          "rules": [{"conditions": [{"attribute": "email", "operator": "MATCHES", "value": "@example\\.com$"}]}],
+          "splits": [{"variationKey": "on", "shards": []}]
+        },
+        {
+          "key": "rollout",
+          "splits": [
+            {"variationKey": "on", "shards": [{"salt": "rollout", "ranges": [{"start": 0, "end": %d}]}]},
+            {"variationKey": "off", "shards": [{"salt": "rollout", "ranges": [{"start": %d, "end": 10000}]}]}
+          ]
+        }
+      ]
+    }
+  }
+}`, extraFlags, rolloutEnd, rolloutEnd))
+}
+
+const diffTestStaticFlag = `"static": {
+      "key": "static",
+      "enabled": true,
+      "variationType": "STRING",
+      "totalShards": 10000,
+      "variations": {"a": {"key": "a", "value": "a"}},
+      "allocations": [{"key": "all", "splits": [{"variationKey": "a", "shards": []}]}]
+    },`
+
+func Test_DiffConfigurations(t *testing.T) {
+	oldFlags := diffTestFlags(5000, strings.Replace(diffTestStaticFlag, `"static"`, `"removed"`, 2))
+	newFlags := diffTestFlags(8000, diffTestStaticFlag)
+
+	diff, err := DiffConfigurations(oldFlags, newFlags)
+	assert.NoError(t, err)
+	assert.Equal(t, []FlagDiff{
+		{FlagChange: FlagChange{FlagKey: "removed", Removed: true}},
+		{
+			FlagChange: FlagChange{FlagKey: "rollout", AllocationsChanged: true},
+			Differences: []Difference{
+				{Path: "allocations.rollout.splits[0].shards[0].ranges[0].end", Old: 5000.0, New: 8000.0},
+				{Path: "allocations.rollout.splits[1].shards[0].ranges[0].start", Old: 5000.0, New: 8000.0},
+			},
+		},
+		{FlagChange: FlagChange{FlagKey: "static", Added: true}},
+	}, diff.Flags)
+}
+
+func Test_DiffConfigurations_flagFields(t *testing.T) {
+	oldFlags := diffTestFlags(5000, "")
+	newFlags := []byte(strings.NewReplacer(
+		`"value": false`, `"value": true`,
+		`"enabled": true`, `"enabled": false`,
+		`"key": "internal"`, `"key": "employees"`,
+		`"shards": [{"salt": "rollout", "ranges": [{"start": 0, "end": 5000}]}]`, `"shards": []`,
+	).Replace(string(oldFlags)))
+
+	diff, err := DiffConfigurations(oldFlags, newFlags)
+	assert.NoError(t, err)
+	if !assert.Len(t, diff.Flags, 1) {
+		return
+	}
+	assert.Equal(t, FlagChange{FlagKey: "rollout", Disabled: true, AllocationsChanged: true, VariationsChanged: []string{"off"}}, diff.Flags[0].FlagChange)
+
+	paths := make([]string, len(diff.Flags[0].Differences))
+	for i, d := range diff.Flags[0].Differences {
+		paths[i] = d.Path
+	}
+	assert.Equal(t, []string{
+		"allocations",
+		"allocations.employees",
+		"allocations.internal",
+		"allocations.rollout.splits[0].shards",
+		"enabled",
+		"variations.off",
+	}, paths)
+	assert.Equal(t, Difference{Path: "allocations", Old: []interface{}{"internal", "rollout"}, New: []interface{}{"employees", "rollout"}}, diff.Flags[0].Differences[0])
+	assert.Nil(t, diff.Flags[0].Differences[1].Old)
+	assert.Nil(t, diff.Flags[0].Differences[2].New)
+}
+
+func Test_DiffConfigurations_totalShards(t *testing.T) {
+	oldFlags := diffTestFlags(5000, "")
+	newFlags := []byte(strings.Replace(string(oldFlags), `"totalShards": 10000`, `"totalShards": 20000`, 1))
+
+	diff, err := DiffConfigurations(oldFlags, newFlags)
+	assert.NoError(t, err)
+	if assert.Len(t, diff.Flags, 1) {
+		assert.True(t, diff.Flags[0].AllocationsChanged)
+		assert.Equal(t, []Difference{{Path: "totalShards", Old: 10000.0, New: 20000.0}}, diff.Flags[0].Differences)
+	}
+}
+
+func Test_DiffConfigurations_unchanged(t *testing.T) {
+	diff, err := DiffConfigurations(diffTestFlags(5000, ""), diffTestFlags(5000, ""))
+	assert.NoError(t, err)
+	assert.Empty(t, diff.Flags)
+
+	_, err = DiffConfigurations([]byte(`{}`), diffTestFlags(5000, ""))
+	assert.ErrorContains(t, err, "old configuration")
+}
+
+func Test_AnalyzeAssignmentImpact(t *testing.T) {
+	var subjects []Subject
+	expectedChanged := 0
+	for i := 0; i < 1000; i++ {
+		key := fmt.Sprintf("subject-%d", i)
+		subject := Subject{Key: key}
+		if i%10 == 0 {
+			// Internal users are served "on" by both configurations.
+			subject.Attributes = Attributes{"email": key + "@example.com"}
+		} else if shard := getShard("rollout-"+key, 10000); shard >= 5000 && shard < 8000 {
+			expectedChanged++
+		}
+		subjects = append(subjects, subject)
+	}
+
+	impacts, err := AnalyzeAssignmentImpact(diffTestFlags(5000, diffTestStaticFlag), diffTestFlags(8000, ""), subjects)
+	assert.NoError(t, err)
+	if !assert.Len(t, impacts, 2) {
+		return
+	}
+
+	assert.Equal(t, "rollout", impacts[0].FlagKey)
+	assert.Equal(t, 1000, impacts[0].Subjects)
+	assert.Equal(t, expectedChanged, impacts[0].Changed)
+	assert.Greater(t, expectedChanged, 200)
+	assert.Equal(t, []VariationTransition{{From: "off", To: "on", Subjects: expectedChanged}}, impacts[0].Transitions)
+
+	assert.Equal(t, FlagImpact{
+		FlagKey:     "static",
+		Subjects:    1000,
+		Changed:     1000,
+		Transitions: []VariationTransition{{From: "a", To: "", Subjects: 1000}},
+	}, impacts[1])
+}
+
+func Test_ReadSubjects(t *testing.T) {
+	subjects, err := ReadSubjects(strings.NewReader(`{"key": "alice", "attributes": {"country": "US", "age": 30}}
+
+{"key": "bob"}
+`))
+	assert.NoError(t, err)
+	assert.Equal(t, []Subject{
+		{Key: "alice", Attributes: Attributes{"country": "US", "age": 30.0}},
+		{Key: "bob"},
+	}, subjects)
+
+	_, err = ReadSubjects(strings.NewReader(`{"key": "alice"}` + "\n" + `{"attributes": {}}`))
+	assert.EqualError(t, err, `subject 2: missing "key"`)
+
+	_, err = ReadSubjects(strings.NewReader(`{"key": `))
+	assert.ErrorContains(t, err, "subject 1")
+}
@@ -1,64 +1,281 @@
 package eppoclient
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
+	"sync"
+	"time"
 )
 
 const CONFIG_ENDPOINT = "/flag-config/v1/config"
 const BANDIT_ENDPOINT = "/flag-config/v1/bandits"
 
+// Raw configuration payloads as received from the server.
+type configurationPayload struct {
+	flags   []byte
+	bandits []byte
+
+	flagsVersion   resourceVersion
+	banditsVersion resourceVersion
+}
+
 type configurationRequestor struct {
 	httpClient        httpClient
 	configStore       *configurationStore
 	applicationLogger ApplicationLogger
+	// Optional on-disk cache. nil if disabled.
+	cache *configurationCache
+
+	// `mu` serializes fetches and guards fields below.
+	mu sync.Mutex
+	// Raw payload and resource versions of the last stored
+	// configuration. Used to make conditional requests.
+	lastPayload configurationPayload
+	// Bandits of the last stored configuration. Reused if bandits
+	// endpoint responds with 304 Not Modified.
+	lastBandits banditResponse
+	// Error of the last fetch. nil if the last fetch has succeeded.
+	lastErr error
+
+	// Called when the server rejects the SDK key. Optional.
+	onUnauthorized func()
+	// Optional tracer. nil if disabled.
+	tracer Tracer
 }
 
-func newConfigurationRequestor(httpClient httpClient, configStore *configurationStore, applicationLogger ApplicationLogger) *configurationRequestor {
+func newConfigurationRequestor(httpClient httpClient, configStore *configurationStore, applicationLogger ApplicationLogger, cache *configurationCache) *configurationRequestor {
 	return &configurationRequestor{
 		httpClient:        httpClient,
 		configStore:       configStore,
 		applicationLogger: applicationLogger,
+		cache:             cache,
 	}
 }
 
-func (cr *configurationRequestor) FetchAndStoreConfigurations() {
-	configuration, err := cr.fetchConfiguration()
+// FetchAndStoreConfigurations fetches configuration and stores it in
+// the configuration store. Returns an error if fetching has failed.
+// Configuration that has not been modified is not considered an error.
+func (cr *configurationRequestor) FetchAndStoreConfigurations() error {
+	err := cr.fetchAndStoreConfigurations()
+	cr.reportError(err)
+	return err
+}
+
+func (cr *configurationRequestor) fetchAndStoreConfigurations() error {
+	cr.mu.Lock()
+	defer cr.mu.Unlock()
+
+	configuration, payload, err := cr.tracedFetchConfiguration()
+	if errors.Is(err, errNotModified) {
+		cr.applicationLogger.Debug("configuration not modified")
+		return nil
+	}
 	if err != nil {
 		cr.applicationLogger.Error("Failed to fetch UFC response", err)
-		return
+		return err
 	}
 
-	cr.configStore.setConfiguration(configuration)
+	cr.storeConfiguration(configuration, payload)
+	return nil
 }
 
-func (cr *configurationRequestor) fetchConfiguration() (configuration, error) {
+// reportError records the result of communicating with the server and
+// notifies `onUnauthorized` if the SDK key has been rejected.
+func (cr *configurationRequestor) reportError(err error) {
+	cr.mu.Lock()
+	cr.lastErr = err
+	cr.mu.Unlock()
+
+	if errors.Is(err, ErrUnauthorized) && cr.onUnauthorized != nil {
+		cr.onUnauthorized()
+	}
+}
+
+// lastError returns the error of the last fetch.
+func (cr *configurationRequestor) lastError() error {
+	cr.mu.Lock()
+	defer cr.mu.Unlock()
+	return cr.lastErr
+}
+
+// StorePushedConfiguration stores configuration pushed by the server
+// (e.g., over a streaming connection). If `bandits` is nil, bandits
+// of the last stored configuration are reused.
+func (cr *configurationRequestor) StorePushedConfiguration(flags, bandits []byte) error {
+	cr.mu.Lock()
+	defer cr.mu.Unlock()
+
 	var config configuration
 	var err error
 
-	config.flags, err = cr.fetchConfig()
+	// Pushed payloads have no cache validators, so the next
+	// conditional fetch is unconditional.
+	payload := configurationPayload{flags: flags, bandits: bandits}
+
+	config.fetchedAt = time.Now()
+	config.flags, err = cr.parseConfig(flags)
 	if err != nil {
-		return configuration{}, err
+		return err
 	}
 
-	if config.flags.Bandits != nil {
-		config.bandits, err = cr.fetchBandits()
+	hasBandits := bandits != nil
+	if hasBandits {
+		config.bandits, err = cr.parseBandits(bandits)
 		if err != nil {
-			return configuration{}, err
+			return err
 		}
+	} else if config.flags.Bandits != nil {
+		payload.bandits = cr.lastPayload.bandits
+		config.bandits = cr.lastBandits
+		hasBandits = payload.bandits != nil
 	}
 
-	return config, nil
+	err = config.validate(hasBandits, cr.applicationLogger)
+	if err != nil {
+		return err
+	}
+
+	cr.storeConfiguration(config, payload)
+	return nil
+}
+
+// `mu` must be held.
+func (cr *configurationRequestor) storeConfiguration(configuration configuration, payload configurationPayload) {
+	cr.configStore.setConfiguration(configuration)
+	cr.lastPayload = payload
+	cr.lastBandits = configuration.bandits
+
+	if cr.cache != nil {
+		err := cr.cache.store(payload, configuration.fetchedAt)
+		if err != nil {
+			cr.applicationLogger.Warnf("failed to write configuration cache: %v", err)
+		}
+	}
 }
 
-func (cr *configurationRequestor) fetchConfig() (configResponse, error) {
-	result, err := cr.httpClient.get(CONFIG_ENDPOINT)
+// loadFromCache loads configuration from the on-disk cache into the
+// configuration store. Missing, corrupt, or outdated cache is
+// ignored.
+func (cr *configurationRequestor) loadFromCache() {
+	if cr.cache == nil {
+		return
+	}
+
+	configuration, err := cr.cache.load()
 	if err != nil {
+		cr.applicationLogger.Infof("not using configuration cache: %v", err)
+		return
+	}
+
+	// Do not overwrite configuration if it has been fetched
+	// already.
+	if cr.configStore.setConfigurationIfEmpty(configuration) {
+		cr.applicationLogger.Infof("loaded configuration from cache (age: %v)", time.Since(configuration.fetchedAt))
+	}
+}
+
+// tracedFetchConfiguration wraps fetchConfiguration in a span if
+// tracing is enabled.
+func (cr *configurationRequestor) tracedFetchConfiguration() (configuration, configurationPayload, error) {
+	if cr.tracer == nil {
+		return cr.fetchConfiguration()
+	}
+
+	_, span := cr.tracer.StartSpan(context.Background(), SpanFetchConfiguration)
+	defer span.End()
+
+	config, payload, err := cr.fetchConfiguration()
+	switch {
+	case errors.Is(err, errNotModified):
+		span.SetAttribute(AttributeConfigurationModified, false)
+	case err != nil:
+		span.RecordError(err)
+	default:
+		span.SetAttribute(AttributeConfigurationModified, true)
+	}
+	return config, payload, err
+}
+
+// fetchConfiguration fetches flags and bandits using conditional
+// requests. Returns `errNotModified` if neither has changed since the
+// last stored configuration.
+func (cr *configurationRequestor) fetchConfiguration() (configuration, configurationPayload, error) {
+	var config configuration
+	var payload configurationPayload
+	var err error
+
+	config.fetchedAt = time.Now()
+
+	flagsModified := true
+	payload.flags, payload.flagsVersion, err = cr.httpClient.getIfModified(CONFIG_ENDPOINT, cr.lastPayload.flagsVersion)
+	if errors.Is(err, errNotModified) {
+		flagsModified = false
+		payload.flags, payload.flagsVersion = cr.lastPayload.flags, cr.lastPayload.flagsVersion
+	} else if err != nil {
 		cr.applicationLogger.Error("Failed to fetch config response", err)
-		return configResponse{}, err
+		return configuration{}, configurationPayload{}, err
 	}
 
+	var hasBandits bool
+	if flagsModified {
+		config.flags, err = cr.parseConfig(payload.flags)
+		if err != nil {
+			return configuration{}, configurationPayload{}, err
+		}
+		hasBandits = config.flags.Bandits != nil
+	} else {
+		// Bandits are only fetched if flags reference them.
+		hasBandits = cr.lastPayload.bandits != nil
+	}
+
+	banditsModified := false
+	if hasBandits {
+		payload.bandits, payload.banditsVersion, err = cr.httpClient.getIfModified(BANDIT_ENDPOINT, cr.lastPayload.banditsVersion)
+		if errors.Is(err, errNotModified) {
+			payload.bandits, payload.banditsVersion = cr.lastPayload.bandits, cr.lastPayload.banditsVersion
+			config.bandits = cr.lastBandits
+		} else if err != nil {
+			cr.applicationLogger.Error("Failed to fetch bandit response", err)
+			return configuration{}, configurationPayload{}, err
+		} else {
+			banditsModified = true
+			config.bandits, err = cr.parseBandits(payload.bandits)
+			if err != nil {
+				return configuration{}, configurationPayload{}, err
+			}
+		}
+	}
+
+	if !flagsModified && !banditsModified {
+		return configuration{}, configurationPayload{}, errNotModified
+	}
+
+	// Invalid configuration is not stored, so the last valid one
+	// stays active. Resource versions of the rejected payload are
+	// not remembered either, so it is validated again on the next
+	// fetch.
+	if !flagsModified {
+		// Flags are mutated by `precompute`, so they cannot be
+		// shared with the active configuration and need to be
+		// parsed again.
+		config.flags, err = cr.parseConfig(payload.flags)
+		if err != nil {
+			return configuration{}, configurationPayload{}, err
+		}
+	}
+
+	err = config.validate(hasBandits, cr.applicationLogger)
+	if err != nil {
+		return configuration{}, configurationPayload{}, err
+	}
+
+	return config, payload, nil
+}
+
+func (cr *configurationRequestor) parseConfig(result []byte) (configResponse, error) {
 	var response configResponse
-	err = json.Unmarshal(result, &response)
+	err := json.Unmarshal(result, &response)
 	if err != nil {
 		cr.applicationLogger.Error("Failed to unmarshal config response JSON", result)
 		cr.applicationLogger.Error(err)
@@ -68,15 +285,9 @@ func (cr *configurationRequestor) fetchConfig() (configResponse, error) {
 	return response, nil
 }
 
-func (cr *configurationRequestor) fetchBandits() (banditResponse, error) {
-	result, err := cr.httpClient.get(BANDIT_ENDPOINT)
-	if err != nil {
-		cr.applicationLogger.Error("Failed to fetch bandit response", err)
-		return banditResponse{}, err
-	}
-
+func (cr *configurationRequestor) parseBandits(result []byte) (banditResponse, error) {
 	var response banditResponse
-	err = json.Unmarshal(result, &response)
+	err := json.Unmarshal(result, &response)
 	if err != nil {
 		cr.applicationLogger.Error("Failed to unmarshal bandit response JSON", result)
 		cr.applicationLogger.Error(err)
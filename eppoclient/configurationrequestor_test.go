@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -18,7 +19,7 @@ func Test_configurationRequestor_requestBandits(t *testing.T) {
 	sdkParams := SDKParams{sdkKey: "blah", sdkName: "go", sdkVersion: __version__}
 	httpClient := newHttpClient(server.URL, &http.Client{Timeout: REQUEST_TIMEOUT_SECONDS}, sdkParams)
 	configurationStore := newConfigurationStore()
-	configurationRequestor := newConfigurationRequestor(*httpClient, configurationStore, applicationLogger)
+	configurationRequestor := newConfigurationRequestor(*httpClient, configurationStore, applicationLogger, nil)
 
 	configurationRequestor.FetchAndStoreConfigurations()
 
@@ -37,7 +38,7 @@ func Test_configurationRequestor_shouldNotRequestBanditsIfNotPresentInFlags(t *t
 	sdkParams := SDKParams{sdkKey: "blah", sdkName: "go", sdkVersion: __version__}
 	httpClient := newHttpClient(server.URL, &http.Client{Timeout: REQUEST_TIMEOUT_SECONDS}, sdkParams)
 	configurationStore := newConfigurationStore()
-	configurationRequestor := newConfigurationRequestor(*httpClient, configurationStore, applicationLogger)
+	configurationRequestor := newConfigurationRequestor(*httpClient, configurationStore, applicationLogger, nil)
 
 	configurationRequestor.FetchAndStoreConfigurations()
 
@@ -64,3 +65,113 @@ func newTestServer(configResponse configResponse, banditsResponse banditResponse
 		}
 	}))
 }
+
+// `conditionalTestServer` serves configuration with ETag support and
+// records conditional request headers.
+type conditionalTestServer struct {
+	*httptest.Server
+	flags, bandits         string
+	flagsETag, banditsETag string
+	ifNoneMatch            map[string][]string
+	flagsNotModified       int
+	banditsNotModified     int
+}
+
+func newConditionalTestServer(flags, bandits string) *conditionalTestServer {
+	s := &conditionalTestServer{
+		flags:       flags,
+		bandits:     bandits,
+		flagsETag:   `"flags-1"`,
+		banditsETag: `"bandits-1"`,
+		ifNoneMatch: map[string][]string{},
+	}
+	serve := func(w http.ResponseWriter, r *http.Request, body, eTag string, notModified *int) {
+		s.ifNoneMatch[r.URL.Path] = append(s.ifNoneMatch[r.URL.Path], r.Header.Get("If-None-Match"))
+		if r.Header.Get("If-None-Match") == eTag {
+			*notModified++
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", eTag)
+		_, _ = w.Write([]byte(body))
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case CONFIG_ENDPOINT:
+			serve(w, r, s.flags, s.flagsETag, &s.flagsNotModified)
+		case BANDIT_ENDPOINT:
+			serve(w, r, s.bandits, s.banditsETag, &s.banditsNotModified)
+		default:
+			http.NotFoundHandler().ServeHTTP(w, r)
+		}
+	}))
+	return s
+}
+
+const conditionalTestBanditFlags = `{
+  "flags": {},
+  "bandits": {
+    "bandit": [{"key": "bandit", "flagKey": "flag", "variationKey": "bandit", "variationValue": "bandit"}]
+  }
+}`
+
+func newConditionalTestRequestor(server *conditionalTestServer) (*configurationRequestor, *configurationStore) {
+	sdkParams := SDKParams{sdkKey: "blah", sdkName: "go", sdkVersion: __version__}
+	httpClient := newHttpClient(server.URL, &http.Client{Timeout: REQUEST_TIMEOUT_SECONDS}, sdkParams)
+	configurationStore := newConfigurationStore()
+	return newConfigurationRequestor(*httpClient, configurationStore, applicationLogger, nil), configurationStore
+}
+
+func Test_configurationRequestor_skipsNotModifiedConfiguration(t *testing.T) {
+	server := newConditionalTestServer(conditionalTestBanditFlags, `{"bandits": {}}`)
+	defer server.Close()
+	requestor, store := newConditionalTestRequestor(server)
+
+	requestor.FetchAndStoreConfigurations()
+	first := store.configuration.Load()
+	assert.NotNil(t, first)
+
+	requestor.FetchAndStoreConfigurations()
+
+	assert.Same(t, first, store.configuration.Load())
+	assert.Equal(t, []string{"", `"flags-1"`}, server.ifNoneMatch[CONFIG_ENDPOINT])
+	assert.Equal(t, []string{"", `"bandits-1"`}, server.ifNoneMatch[BANDIT_ENDPOINT])
+	assert.Equal(t, 1, server.flagsNotModified)
+	assert.Equal(t, 1, server.banditsNotModified)
+}
+
+func Test_configurationRequestor_refetchesModifiedBandits(t *testing.T) {
+	server := newConditionalTestServer(conditionalTestBanditFlags, `{"bandits": {}}`)
+	defer server.Close()
+	requestor, store := newConditionalTestRequestor(server)
+
+	requestor.FetchAndStoreConfigurations()
+	first := store.configuration.Load()
+
+	server.bandits = `{"bandits": {"bandit": {"banditKey": "bandit", "modelVersion": "v2"}}}`
+	server.banditsETag = `"bandits-2"`
+	requestor.FetchAndStoreConfigurations()
+
+	second := store.configuration.Load()
+	assert.NotSame(t, first, second)
+	assert.Equal(t, "v2", second.bandits.Bandits["bandit"].ModelVersion)
+	assert.Contains(t, second.flags.Bandits, "bandit")
+	assert.Equal(t, 1, server.flagsNotModified)
+}
+
+func Test_configurationRequestor_reusesNotModifiedBandits(t *testing.T) {
+	server := newConditionalTestServer(conditionalTestBanditFlags, `{"bandits": {"bandit": {"banditKey": "bandit", "modelVersion": "v1"}}}`)
+	defer server.Close()
+	requestor, store := newConditionalTestRequestor(server)
+
+	requestor.FetchAndStoreConfigurations()
+
+	server.flags = strings.Replace(conditionalTestBanditFlags, `"flags": {}`, `"flags": {"flag": {"key": "flag", "enabled": true, "variationType": "STRING", "totalShards": 10000}}`, 1)
+	server.flagsETag = `"flags-2"`
+	requestor.FetchAndStoreConfigurations()
+
+	config := store.getConfiguration()
+	assert.Contains(t, config.flags.Flags, "flag")
+	assert.Equal(t, "v1", config.bandits.Bandits["bandit"].ModelVersion)
+	assert.Equal(t, 1, server.banditsNotModified)
+}
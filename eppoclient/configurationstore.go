@@ -1,6 +1,7 @@
 package eppoclient
 
 import (
+	"sync"
 	"sync/atomic"
 )
 
@@ -17,6 +18,14 @@ type configurationStore struct {
 	// `isInitialized` is used to protect `initializedCh`, so we
 	// don’t double-close it (which is an error in Go).
 	isInitialized atomic.Bool
+
+	// `mu` serializes configuration updates, so listeners observe
+	// them in order, and guards `listeners`.
+	mu        sync.Mutex
+	listeners map[*configurationListener]struct{}
+
+	// Optional metrics sink. nil if disabled.
+	metrics Metrics
 }
 
 func newConfigurationStore() *configurationStore {
@@ -42,10 +51,83 @@ func (cs *configurationStore) getConfiguration() configuration {
 
 func (cs *configurationStore) setConfiguration(configuration configuration) {
 	configuration.precompute()
-	cs.configuration.Store(&configuration)
+
+	cs.mu.Lock()
+	old := cs.configuration.Swap(&configuration)
+	cs.notifyListeners(old, &configuration)
+	cs.mu.Unlock()
+
+	cs.observeUpdate(configuration)
+
 	cs.setInitialized()
 }
 
+// setConfigurationIfEmpty sets configuration only if no
+// configuration has been set yet. Returns true if configuration was
+// set.
+func (cs *configurationStore) setConfigurationIfEmpty(configuration configuration) bool {
+	configuration.precompute()
+
+	cs.mu.Lock()
+	if !cs.configuration.CompareAndSwap(nil, &configuration) {
+		cs.mu.Unlock()
+		return false
+	}
+	cs.notifyListeners(nil, &configuration)
+	cs.mu.Unlock()
+
+	cs.observeUpdate(configuration)
+
+	cs.setInitialized()
+	return true
+}
+
+// subscribe registers a listener notified after each configuration
+// update. Returns a function that unregisters the listener.
+func (cs *configurationStore) subscribe(listener *configurationListener) func() {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+
+	if cs.listeners == nil {
+		cs.listeners = make(map[*configurationListener]struct{})
+	}
+	cs.listeners[listener] = struct{}{}
+
+	return func() {
+		cs.mu.Lock()
+		delete(cs.listeners, listener)
+		cs.mu.Unlock()
+		listener.stop()
+	}
+}
+
+// unsubscribeAll unregisters and stops all listeners.
+func (cs *configurationStore) unsubscribeAll() {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+
+	for listener := range cs.listeners {
+		listener.stop()
+	}
+	cs.listeners = nil
+}
+
+// `mu` must be held.
+func (cs *configurationStore) notifyListeners(old, new *configuration) {
+	for listener := range cs.listeners {
+		listener.notify(old, new)
+	}
+}
+
+func (cs *configurationStore) observeUpdate(configuration configuration) {
+	if cs.metrics != nil {
+		cs.metrics.ObserveConfigurationUpdate(ConfigurationUpdateMetric{
+			FetchedAt: configuration.fetchedAt,
+			FromCache: configuration.fromCache,
+		})
+	}
+}
+
 // Set `initialized` flag to `true` notifying anyone waiting on it.
 func (cs *configurationStore) setInitialized() {
 	if cs.isInitialized.CompareAndSwap(false, true) {
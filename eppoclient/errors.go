@@ -7,4 +7,20 @@ var (
 	ErrFlagNotEnabled              = errors.New("the experiment or flag is not enabled")
 	ErrFlagConfigurationNotFound   = errors.New("flag configuration not found")
 	ErrBanditConfigurationNotFound = errors.New("bandit configuration not found")
+	ErrVariationDecode             = errors.New("failed to decode variation value")
+	ErrClientClosed                = errors.New("client is closed")
+	// ErrUnauthorized is returned when Eppo rejects the SDK key.
+	ErrUnauthorized = errors.New("unauthorized access")
+	// ErrInvalidConfiguration is returned when configuration is
+	// rejected by validation. See ValidateConfiguration.
+	ErrInvalidConfiguration = errors.New("invalid configuration")
+	// ErrObfuscatedConfiguration is returned by functions that
+	// enumerate all flags when the active configuration is
+	// obfuscated, as plaintext flag keys are not known.
+	ErrObfuscatedConfiguration = errors.New("not supported for obfuscated configuration")
+)
+
+var (
+	errNoSubjectKey = errors.New("no subject key provided")
+	errNoFlagKey    = errors.New("no flag key provided")
 )
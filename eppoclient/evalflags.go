@@ -9,34 +9,92 @@ func (flag flagConfiguration) verifyType(ty variationType) error {
 	if flag.VariationType == ty {
 		return nil
 	} else {
-		return fmt.Errorf("unexpected variation type (expected: %v, actual: %v)", ty, flag.VariationType)
+		return typeMismatchError{expected: ty, actual: flag.VariationType}
 	}
 }
 
-func (flag flagConfiguration) eval(subjectKey string, subjectAttributes Attributes, applicationLogger ApplicationLogger) (interface{}, *AssignmentEvent, error) {
+type typeMismatchError struct {
+	expected variationType
+	actual   variationType
+}
+
+func (e typeMismatchError) Error() string {
+	return fmt.Sprintf("unexpected variation type (expected: %v, actual: %v)", e.expected, e.actual)
+}
+
+// flagEvaluation is the result of successful flag evaluation.
+type flagEvaluation struct {
+	// Variation value parsed according to flag's VariationType.
+	value      interface{}
+	allocation *allocation
+	split      *split
+	// nil if assignment should not be logged.
+	event *AssignmentEvent
+}
+
+// eval evaluates the flag for the given subject.
+//
+// If `details` is not nil, it is populated with the explanation of
+// the evaluation. Passing nil skips collecting details, which is
+// the fast path used by regular getters.
+func (flag flagConfiguration) eval(subjectKey string, subjectAttributes Attributes, applicationLogger ApplicationLogger, details *EvaluationDetails) (flagEvaluation, error) {
+	return flag.evalAugmented(subjectKey, subjectAttributes, nil, applicationLogger, details)
+}
+
+// evalAugmented is the same as eval but allows callers evaluating
+// multiple flags to pass `augmentedSubjectAttributes` (see
+// `augmentWithSubjectKey`) computed once. If nil, it is computed
+// from `subjectAttributes`.
+func (flag flagConfiguration) evalAugmented(subjectKey string, subjectAttributes Attributes, augmentedSubjectAttributes Attributes, applicationLogger ApplicationLogger, details *EvaluationDetails) (flagEvaluation, error) {
+	if details != nil {
+		details.Allocations = make([]AllocationEvaluation, len(flag.Allocations))
+		for i, a := range flag.Allocations {
+			details.Allocations[i] = AllocationEvaluation{Key: a.Key, Reason: EvaluationReasonUnevaluated}
+		}
+	}
+
 	if !flag.Enabled {
-		return nil, nil, ErrFlagNotEnabled
+		details.setError(EvaluationReasonFlagDisabled, ErrFlagNotEnabled)
+		return flagEvaluation{}, ErrFlagNotEnabled
 	}
 
 	now := time.Now()
-	augmentedSubjectAttributes := augmentWithSubjectKey(subjectAttributes, subjectKey)
+	if augmentedSubjectAttributes == nil {
+		augmentedSubjectAttributes = augmentWithSubjectKey(subjectAttributes, subjectKey)
+	}
+	if details != nil {
+		details.SubjectAttributes = augmentedSubjectAttributes
+	}
 
 	var allocation *allocation
 	var split *split
-	for _, a := range flag.Allocations {
-		s := a.findMatchingSplit(subjectKey, augmentedSubjectAttributes, flag.TotalShards, now, applicationLogger)
+	for i, a := range flag.Allocations {
+		var allocationDetails *AllocationEvaluation
+		if details != nil {
+			allocationDetails = &details.Allocations[i]
+		}
+		s := a.findMatchingSplit(subjectKey, augmentedSubjectAttributes, flag.TotalShards, now, applicationLogger, allocationDetails)
 		if s != nil {
 			allocation, split = &a, s
 			break
 		}
 	}
 	if allocation == nil || split == nil {
-		return nil, nil, ErrSubjectAllocation
+		details.setError(EvaluationReasonNoAllocationMatched, ErrSubjectAllocation)
+		return flagEvaluation{}, ErrSubjectAllocation
 	}
 
 	assignmentValue, ok := flag.ParsedVariations[split.VariationKey]
 	if !ok {
-		return nil, nil, fmt.Errorf("cannot find variation: %v", split.VariationKey)
+		err := fmt.Errorf("cannot find variation: %v", split.VariationKey)
+		details.setError(EvaluationReasonDefaultUsed, err)
+		return flagEvaluation{}, err
+	}
+
+	if details != nil {
+		details.AllocationKey = allocation.Key
+		details.VariationKey = split.VariationKey
+		details.Reason = EvaluationReasonMatch
 	}
 
 	var assignmentEvent *AssignmentEvent
@@ -57,7 +115,12 @@ func (flag flagConfiguration) eval(subjectKey string, subjectAttributes Attribut
 		}
 	}
 
-	return assignmentValue, assignmentEvent, nil
+	return flagEvaluation{
+		value:      assignmentValue,
+		allocation: allocation,
+		split:      split,
+		event:      assignmentEvent,
+	}, nil
 }
 
 // Augment `subjectAttributes` by setting "id" attribute to
@@ -79,47 +142,94 @@ func augmentWithSubjectKey(subjectAttributes Attributes, subjectKey string) Attr
 	return augmentedSubjectAttributes
 }
 
-func (allocation allocation) findMatchingSplit(subjectKey string, augmentedSubjectAttributes Attributes, totalShards int64, now time.Time, applicationLogger ApplicationLogger) *split {
+// findMatchingSplit returns the split matching the subject or nil if
+// subject does not match the allocation.
+//
+// If `details` is not nil, it is populated with rule and shard
+// evaluation results.
+func (allocation allocation) findMatchingSplit(subjectKey string, augmentedSubjectAttributes Attributes, totalShards int64, now time.Time, applicationLogger ApplicationLogger, details *AllocationEvaluation) *split {
 	if !allocation.StartAt.IsZero() && now.Before(allocation.StartAt) {
+		if details != nil {
+			details.Reason = EvaluationReasonBeforeStartTime
+		}
 		return nil
 	}
 	if !allocation.EndAt.IsZero() && now.After(allocation.EndAt) {
+		if details != nil {
+			details.Reason = EvaluationReasonAfterEndTime
+		}
 		return nil
 	}
 
 	matchesRule := false
-	for _, rule := range allocation.Rules {
-		if rule.matches(augmentedSubjectAttributes, applicationLogger) {
-			matchesRule = true
-			break
+	if details != nil {
+		// Evaluate all rules without short-circuiting, so that
+		// details include results for every rule and condition.
+		details.Rules = make([]RuleEvaluation, len(allocation.Rules))
+		for i, rule := range allocation.Rules {
+			details.Rules[i] = rule.evaluate(augmentedSubjectAttributes, applicationLogger)
+			matchesRule = matchesRule || details.Rules[i].Matched
+		}
+	} else {
+		for _, rule := range allocation.Rules {
+			if rule.matches(augmentedSubjectAttributes, applicationLogger) {
+				matchesRule = true
+				break
+			}
 		}
 	}
 
 	if len(allocation.Rules) > 0 && !matchesRule {
 		// Forbidden by rules
+		if details != nil {
+			details.Reason = EvaluationReasonRuleFailed
+		}
 		return nil
 	}
 
 	for _, split := range allocation.Splits {
-		if split.matches(subjectKey, totalShards) {
+		var splitDetails *SplitEvaluation
+		if details != nil {
+			details.Splits = append(details.Splits, SplitEvaluation{VariationKey: split.VariationKey})
+			splitDetails = &details.Splits[len(details.Splits)-1]
+		}
+		if split.matches(subjectKey, totalShards, splitDetails) {
+			if details != nil {
+				details.Reason = EvaluationReasonMatch
+			}
 			return &split
 		}
 	}
 
+	if details != nil {
+		details.Reason = EvaluationReasonShardMiss
+	}
 	return nil
 }
 
-func (split split) matches(subjectKey string, totalShards int64) bool {
+func (split split) matches(subjectKey string, totalShards int64, details *SplitEvaluation) bool {
 	for _, shard := range split.Shards {
-		if !shard.matches(subjectKey, totalShards) {
+		if details != nil {
+			shardDetails := shard.evaluate(subjectKey, totalShards)
+			details.Shards = append(details.Shards, shardDetails)
+			if !shardDetails.Matched {
+				return false
+			}
+		} else if !shard.matches(subjectKey, totalShards) {
 			return false
 		}
 	}
+	if details != nil {
+		details.Matched = true
+	}
 	return true
 }
 
 func (shard shard) matches(subjectKey string, totalShards int64) bool {
-	s := getShard(shard.Salt+"-"+subjectKey, totalShards)
+	return shard.containsShard(getShard(shard.Salt+"-"+subjectKey, totalShards))
+}
+
+func (shard shard) containsShard(s int64) bool {
 	for _, r := range shard.Ranges {
 		if isShardInRange(s, r) {
 			return true
@@ -0,0 +1,167 @@
+package eppoclient
+
+// EvaluationReason describes why an evaluation (or a single allocation
+// within it) produced its outcome.
+type EvaluationReason string
+
+const (
+	// The subject matched an allocation and was assigned a variation.
+	EvaluationReasonMatch EvaluationReason = "MATCH"
+	// The flag exists but is disabled.
+	EvaluationReasonFlagDisabled EvaluationReason = "FLAG_DISABLED"
+	// The flag is not present in the current configuration.
+	EvaluationReasonFlagNotFound EvaluationReason = "FLAG_NOT_FOUND"
+	// The subject fell through all allocations.
+	EvaluationReasonNoAllocationMatched EvaluationReason = "NO_ALLOCATION_MATCHED"
+	// The flag variation type does not match the requested type.
+	EvaluationReasonTypeMismatch EvaluationReason = "TYPE_MISMATCH"
+	// The default value was returned for any other reason (e.g.,
+	// invalid arguments or a variation that cannot be found).
+	EvaluationReasonDefaultUsed EvaluationReason = "DEFAULT_USED"
+	// The value was provided by an EvaluationHook.
+	EvaluationReasonHookOverride EvaluationReason = "HOOK_OVERRIDE"
+	// The value was forced by the local overrides file (see
+	// Config.OverridesFile).
+	EvaluationReasonLocalOverride EvaluationReason = "LOCAL_OVERRIDE"
+
+	// Allocation-level reasons.
+
+	// The allocation was not evaluated because an earlier allocation
+	// matched or the flag was not evaluated at all.
+	EvaluationReasonUnevaluated EvaluationReason = "UNEVALUATED"
+	// The evaluation happened before the allocation's StartAt.
+	EvaluationReasonBeforeStartTime EvaluationReason = "BEFORE_START_TIME"
+	// The evaluation happened after the allocation's EndAt.
+	EvaluationReasonAfterEndTime EvaluationReason = "AFTER_END_TIME"
+	// None of the allocation rules matched subject attributes.
+	EvaluationReasonRuleFailed EvaluationReason = "FAILING_RULE"
+	// The subject's shard did not fall into any split.
+	EvaluationReasonShardMiss EvaluationReason = "TRAFFIC_EXPOSURE_MISS"
+)
+
+// EvaluationDetails explains how an assignment was made.
+type EvaluationDetails struct {
+	FlagKey    string
+	SubjectKey string
+	// Attributes used for evaluation, including the implicit "id"
+	// attribute.
+	SubjectAttributes Attributes
+	// Key of the matched allocation. Empty if no allocation matched.
+	AllocationKey string
+	// Key of the assigned variation. Empty if default value was used.
+	VariationKey string
+	Reason       EvaluationReason
+	// Error returned alongside the assignment, if any.
+	Error error
+	// Per-allocation results in the order allocations were
+	// evaluated.
+	Allocations []AllocationEvaluation
+}
+
+type AllocationEvaluation struct {
+	Key string
+	// One of EvaluationReasonMatch, EvaluationReasonUnevaluated,
+	// EvaluationReasonBeforeStartTime, EvaluationReasonAfterEndTime,
+	// EvaluationReasonRuleFailed, EvaluationReasonShardMiss.
+	Reason EvaluationReason
+	Rules  []RuleEvaluation
+	Splits []SplitEvaluation
+}
+
+type RuleEvaluation struct {
+	Matched    bool
+	Conditions []ConditionEvaluation
+}
+
+type ConditionEvaluation struct {
+	Attribute string
+	Operator  string
+	// Value as found in the condition.
+	Value interface{}
+	// Value of the subject attribute. nil if missing.
+	SubjectValue interface{}
+	Matched      bool
+}
+
+type SplitEvaluation struct {
+	VariationKey string
+	Matched      bool
+	// Evaluated shards. Evaluation stops at the first shard that
+	// does not match, so not all split shards may be listed.
+	Shards []ShardEvaluation
+}
+
+type ShardEvaluation struct {
+	Salt        string
+	ShardValue  int64
+	TotalShards int64
+	Ranges      []ShardRange
+	Matched     bool
+}
+
+// ShardRange is a half-open [Start, End) range of shards.
+type ShardRange struct {
+	Start int64
+	End   int64
+}
+
+func (details *EvaluationDetails) setError(reason EvaluationReason, err error) {
+	if details == nil {
+		return
+	}
+	details.Reason = reason
+	details.Error = err
+}
+
+// setOverride marks the evaluation as short-circuited by a hook.
+func (details *EvaluationDetails) setOverride() {
+	if details == nil {
+		return
+	}
+	details.Reason = EvaluationReasonHookOverride
+}
+
+func (r rule) evaluate(subjectAttributes Attributes, applicationLogger ApplicationLogger) RuleEvaluation {
+	result := RuleEvaluation{
+		Matched:    true,
+		Conditions: make([]ConditionEvaluation, len(r.Conditions)),
+	}
+	for i, c := range r.Conditions {
+		matched := c.matches(subjectAttributes, applicationLogger)
+		result.Conditions[i] = ConditionEvaluation{
+			Attribute:    c.Attribute,
+			Operator:     c.Operator,
+			Value:        c.Value,
+			SubjectValue: subjectAttributes[c.Attribute],
+			Matched:      matched,
+		}
+		if !matched {
+			result.Matched = false
+		}
+	}
+	return result
+}
+
+func (s shard) evaluate(subjectKey string, totalShards int64) ShardEvaluation {
+	value := getShard(s.Salt+"-"+subjectKey, totalShards)
+	result := ShardEvaluation{
+		Salt:        s.Salt,
+		ShardValue:  value,
+		TotalShards: totalShards,
+		Ranges:      make([]ShardRange, len(s.Ranges)),
+		Matched:     s.containsShard(value),
+	}
+	for i, r := range s.Ranges {
+		result.Ranges[i] = ShardRange{Start: r.Start, End: r.End}
+	}
+	return result
+}
+
+func newEvaluationDetails(flagKey, subjectKey string, subjectAttributes Attributes) EvaluationDetails {
+	return EvaluationDetails{
+		FlagKey:           flagKey,
+		SubjectKey:        subjectKey,
+		SubjectAttributes: subjectAttributes,
+		Reason:            EvaluationReasonDefaultUsed,
+	}
+}
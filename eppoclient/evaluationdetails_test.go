@@ -0,0 +1,145 @@
+package eppoclient
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newDetailsTestConfiguration() configuration {
+	return configuration{flags: configResponse{
+		Flags: map[string]*flagConfiguration{
+			"flag": {
+				Key:           "flag",
+				Enabled:       true,
+				TotalShards:   10000,
+				VariationType: stringVariation,
+				Variations: map[string]variation{
+					"a": {Key: "a", Value: []byte(`"a"`)},
+					"b": {Key: "b", Value: []byte(`"b"`)},
+				},
+				Allocations: []allocation{
+					{
+						Key:     "expired",
+						EndAt:   time.Now().Add(-time.Hour),
+						Splits:  []split{{VariationKey: "a"}},
+						Rules:   nil,
+						StartAt: time.Time{},
+					},
+					{
+						Key: "targeted",
+						Rules: []rule{{Conditions: []condition{
+							{Operator: "ONE_OF", Attribute: "country", Value: []string{"US"}},
+						}}},
+						Splits: []split{{VariationKey: "a"}},
+					},
+					{
+						Key: "rollout",
+						Splits: []split{{
+							VariationKey: "b",
+							Shards: []shard{{
+								Salt:   "salt",
+								Ranges: []shardRange{{Start: 0, End: 10000}},
+							}},
+						}},
+					},
+				},
+			},
+			"disabled": {
+				Key:           "disabled",
+				Enabled:       false,
+				VariationType: stringVariation,
+				Allocations:   []allocation{{Key: "allocation"}},
+			},
+		},
+	}}
+}
+
+func Test_GetStringAssignmentDetails_match(t *testing.T) {
+	client := newEppoClient(newConfigurationStoreWithConfig(newDetailsTestConfiguration()), nil, nil, nil, nil, applicationLogger)
+
+	value, details, err := client.GetStringAssignmentDetails("flag", "subject", Attributes{"country": "UK"}, "default")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "b", value)
+	assert.Equal(t, EvaluationReasonMatch, details.Reason)
+	assert.Equal(t, "flag", details.FlagKey)
+	assert.Equal(t, "subject", details.SubjectKey)
+	assert.Equal(t, "rollout", details.AllocationKey)
+	assert.Equal(t, "b", details.VariationKey)
+	assert.Equal(t, "subject", details.SubjectAttributes["id"])
+
+	assert.Len(t, details.Allocations, 3)
+	assert.Equal(t, EvaluationReasonAfterEndTime, details.Allocations[0].Reason)
+
+	assert.Equal(t, EvaluationReasonRuleFailed, details.Allocations[1].Reason)
+	assert.Len(t, details.Allocations[1].Rules, 1)
+	assert.False(t, details.Allocations[1].Rules[0].Matched)
+	assert.Equal(t, ConditionEvaluation{
+		Attribute:    "country",
+		Operator:     "ONE_OF",
+		Value:        []string{"US"},
+		SubjectValue: "UK",
+		Matched:      false,
+	}, details.Allocations[1].Rules[0].Conditions[0])
+
+	rollout := details.Allocations[2]
+	assert.Equal(t, EvaluationReasonMatch, rollout.Reason)
+	assert.Len(t, rollout.Splits, 1)
+	assert.True(t, rollout.Splits[0].Matched)
+	assert.Len(t, rollout.Splits[0].Shards, 1)
+	shardDetails := rollout.Splits[0].Shards[0]
+	assert.Equal(t, getShard("salt-subject", 10000), shardDetails.ShardValue)
+	assert.Equal(t, []ShardRange{{Start: 0, End: 10000}}, shardDetails.Ranges)
+	assert.True(t, shardDetails.Matched)
+}
+
+func Test_GetStringAssignmentDetails_earlierAllocationMatches(t *testing.T) {
+	client := newEppoClient(newConfigurationStoreWithConfig(newDetailsTestConfiguration()), nil, nil, nil, nil, applicationLogger)
+
+	value, details, err := client.GetStringAssignmentDetails("flag", "subject", Attributes{"country": "US"}, "default")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "a", value)
+	assert.Equal(t, "targeted", details.AllocationKey)
+	assert.Equal(t, EvaluationReasonMatch, details.Allocations[1].Reason)
+	assert.Equal(t, EvaluationReasonUnevaluated, details.Allocations[2].Reason)
+}
+
+func Test_GetStringAssignmentDetails_shardMiss(t *testing.T) {
+	config := newDetailsTestConfiguration()
+	config.flags.Flags["flag"].Allocations[2].Splits[0].Shards[0].Ranges = []shardRange{}
+	client := newEppoClient(newConfigurationStoreWithConfig(config), nil, nil, nil, nil, applicationLogger)
+
+	value, details, err := client.GetStringAssignmentDetails("flag", "subject", Attributes{}, "default")
+
+	assert.ErrorIs(t, err, ErrSubjectAllocation)
+	assert.Equal(t, "default", value)
+	assert.Equal(t, EvaluationReasonNoAllocationMatched, details.Reason)
+	assert.Equal(t, EvaluationReasonShardMiss, details.Allocations[2].Reason)
+	assert.False(t, details.Allocations[2].Splits[0].Matched)
+}
+
+func Test_GetAssignmentDetails_errors(t *testing.T) {
+	client := newEppoClient(newConfigurationStoreWithConfig(newDetailsTestConfiguration()), nil, nil, nil, nil, applicationLogger)
+
+	_, details, err := client.GetStringAssignmentDetails("disabled", "subject", Attributes{}, "default")
+	assert.ErrorIs(t, err, ErrFlagNotEnabled)
+	assert.Equal(t, EvaluationReasonFlagDisabled, details.Reason)
+	assert.Equal(t, []AllocationEvaluation{{Key: "allocation", Reason: EvaluationReasonUnevaluated}}, details.Allocations)
+
+	_, details, err = client.GetStringAssignmentDetails("unknown", "subject", Attributes{}, "default")
+	assert.ErrorIs(t, err, ErrFlagConfigurationNotFound)
+	assert.Equal(t, EvaluationReasonFlagNotFound, details.Reason)
+
+	value, details, err := client.GetBoolAssignmentDetails("flag", "subject", Attributes{}, true)
+	assert.Error(t, err)
+	assert.True(t, value)
+	assert.Equal(t, EvaluationReasonTypeMismatch, details.Reason)
+	assert.Equal(t, err, details.Error)
+
+	_, details, err = client.GetStringAssignmentDetails("flag", "", Attributes{}, "default")
+	assert.Error(t, err)
+	assert.Equal(t, EvaluationReasonDefaultUsed, details.Reason)
+}
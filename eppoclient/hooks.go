@@ -0,0 +1,276 @@
+package eppoclient
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// EvaluationHook adds cross-cutting behavior to flag and bandit
+// evaluations, e.g., overriding values for internal users,
+// suppressing logging of synthetic traffic, or recording audit
+// trails.
+//
+// Hooks are registered with Config.Hooks. For every evaluation, all
+// BeforeEvaluation methods are called in registration order, then
+// the flag is evaluated, then AfterEvaluation and FinallyEvaluation
+// methods are called in registration order. Panics in hooks are
+// recovered and logged, so a failing hook never breaks evaluation.
+//
+// GetBanditAction runs hooks for the bandit evaluation
+// (HookContext.Bandit is true) around hooks for evaluation of the
+// underlying flag.
+//
+// Embed BaseEvaluationHook to implement only some of the stages.
+type EvaluationHook interface {
+	// BeforeEvaluation is called before the flag is evaluated. It
+	// may modify subject attributes in `hookCtx`, which are then
+	// used for evaluation and visible to subsequent hooks.
+	//
+	// Returning a non-nil HookOverride short-circuits evaluation:
+	// remaining BeforeEvaluation methods are skipped and the
+	// override value is returned to the caller. Overridden
+	// assignments are not logged.
+	BeforeEvaluation(ctx context.Context, hookCtx *HookContext) *HookOverride
+	// AfterEvaluation is called with the result of evaluation
+	// before the assignment is logged, including when evaluation
+	// has failed or has been short-circuited.
+	AfterEvaluation(ctx context.Context, hookCtx *HookContext, result EvaluationResult)
+	// FinallyEvaluation is called after the assignment has been
+	// logged.
+	FinallyEvaluation(ctx context.Context, hookCtx *HookContext, result EvaluationResult)
+}
+
+// BaseEvaluationHook implements all EvaluationHook stages as no-ops.
+type BaseEvaluationHook struct{}
+
+func (BaseEvaluationHook) BeforeEvaluation(context.Context, *HookContext) *HookOverride {
+	return nil
+}
+
+func (BaseEvaluationHook) AfterEvaluation(context.Context, *HookContext, EvaluationResult) {}
+
+func (BaseEvaluationHook) FinallyEvaluation(context.Context, *HookContext, EvaluationResult) {}
+
+// HookContext describes the evaluation passed to hooks. The same
+// instance is passed to all stages of a single evaluation. Attributes
+// are copies of the caller's, so hooks may mutate them.
+type HookContext struct {
+	FlagKey    string
+	SubjectKey string
+	// Type of the requested variation. Always
+	// VariationTypeString for bandit evaluations.
+	VariationType VariationType
+	// Subject attributes of a flag evaluation. nil for bandit
+	// evaluations.
+	SubjectAttributes Attributes
+
+	// Bandit is true for GetBanditAction evaluations.
+	Bandit bool
+	// Subject attributes of a bandit evaluation.
+	BanditSubjectAttributes ContextAttributes
+	// Actions of a bandit evaluation.
+	BanditActions map[string]ContextAttributes
+
+	// SkipLogging suppresses logging of the assignment (and bandit
+	// action) if set by BeforeEvaluation or AfterEvaluation.
+	SkipLogging bool
+}
+
+// HookOverride is returned by EvaluationHook.BeforeEvaluation to
+// short-circuit evaluation.
+type HookOverride struct {
+	// Value returned instead of the evaluated one. For flag
+	// evaluations, it must be of the type matching the requested
+	// variation type: string, int64, float64, or bool. For JSON
+	// flags, it is either raw JSON ([]byte or json.RawMessage) or
+	// a value that is marshaled to JSON. For bandit evaluations, it
+	// is the variation string.
+	//
+	// Overrides of a mismatching type are ignored.
+	Value interface{}
+	// Action returned by GetBanditAction. Ignored for flag
+	// evaluations.
+	Action *string
+}
+
+// EvaluationResult is the result of evaluation passed to hooks.
+type EvaluationResult struct {
+	// Assigned value in the form returned by the getters (parsed
+	// value for JSON flags; variation for bandit evaluations). nil
+	// if the caller receives the default value.
+	Value interface{}
+	// Empty if no allocation has matched or evaluation has been
+	// short-circuited.
+	VariationKey  string
+	AllocationKey string
+	// Bandit evaluations only.
+	BanditKey string
+	Action    *string
+	// Overridden is true if the value was provided by a
+	// BeforeEvaluation hook.
+	Overridden bool
+	// Error returned alongside the assignment, if any.
+	Error error
+}
+
+func newEvaluationResult(evaluation flagEvaluation, overridden bool, err error) EvaluationResult {
+	result := EvaluationResult{
+		Value:      evaluation.value,
+		Overridden: overridden,
+		Error:      err,
+	}
+	if value, ok := evaluation.value.(jsonVariationValue); ok {
+		result.Value = value.Parsed
+	}
+	if evaluation.allocation != nil {
+		result.AllocationKey = evaluation.allocation.Key
+	}
+	if evaluation.split != nil {
+		result.VariationKey = evaluation.split.VariationKey
+	}
+	return result
+}
+
+// copy returns a shallow copy of the attributes, so that hooks
+// mutating them don't change the caller's map.
+func (a Attributes) copy() Attributes {
+	if a == nil {
+		return nil
+	}
+	result := make(Attributes, len(a))
+	for k, v := range a {
+		result[k] = v
+	}
+	return result
+}
+
+// copy returns a copy of the attributes, so that hooks mutating them
+// don't change the caller's maps.
+func (a ContextAttributes) copy() ContextAttributes {
+	var result ContextAttributes
+	if a.Numeric != nil {
+		result.Numeric = make(map[string]float64, len(a.Numeric))
+		for k, v := range a.Numeric {
+			result.Numeric[k] = v
+		}
+	}
+	if a.Categorical != nil {
+		result.Categorical = make(map[string]string, len(a.Categorical))
+		for k, v := range a.Categorical {
+			result.Categorical[k] = v
+		}
+	}
+	return result
+}
+
+// copyActions returns a copy of bandit actions and their attributes.
+func copyActions(actions map[string]ContextAttributes) map[string]ContextAttributes {
+	if actions == nil {
+		return nil
+	}
+	result := make(map[string]ContextAttributes, len(actions))
+	for k, v := range actions {
+		result[k] = v.copy()
+	}
+	return result
+}
+
+// overrideValue converts a HookOverride value to the internal
+// representation of variation values of type `ty`.
+func (ty variationType) overrideValue(value interface{}) (interface{}, error) {
+	switch ty {
+	case stringVariation:
+		if s, ok := value.(string); ok {
+			return s, nil
+		}
+	case integerVariation:
+		switch v := value.(type) {
+		case int64:
+			return v, nil
+		case int:
+			return int64(v), nil
+		}
+	case numericVariation:
+		switch v := value.(type) {
+		case float64:
+			return v, nil
+		case int64:
+			return float64(v), nil
+		case int:
+			return float64(v), nil
+		}
+	case booleanVariation:
+		if b, ok := value.(bool); ok {
+			return b, nil
+		}
+	case jsonVariation:
+		var raw []byte
+		switch v := value.(type) {
+		case []byte:
+			raw = v
+		case json.RawMessage:
+			raw = v
+		default:
+			var err error
+			raw, err = json.Marshal(v)
+			if err != nil {
+				return nil, err
+			}
+		}
+		var parsed interface{}
+		if err := json.Unmarshal(raw, &parsed); err != nil {
+			return nil, err
+		}
+		return jsonVariationValue{Parsed: parsed, Raw: raw}, nil
+	}
+	return nil, fmt.Errorf("override value %v (%T) does not match variation type %v", value, value, ty.toPublic())
+}
+
+// runBeforeHooks calls BeforeEvaluation of all hooks until one of
+// them returns an override.
+func (ec *EppoClient) runBeforeHooks(ctx context.Context, hookCtx *HookContext) *HookOverride {
+	for _, hook := range ec.hooks {
+		if override := ec.callBeforeHook(ctx, hook, hookCtx); override != nil {
+			return override
+		}
+	}
+	return nil
+}
+
+func (ec *EppoClient) callBeforeHook(ctx context.Context, hook EvaluationHook, hookCtx *HookContext) (override *HookOverride) {
+	// need to catch panics from hooks and continue
+	defer func() {
+		r := recover()
+		if r != nil {
+			ec.applicationLogger.Errorf("panic occurred in evaluation hook: %v", r)
+			override = nil
+		}
+	}()
+
+	return hook.BeforeEvaluation(ctx, hookCtx)
+}
+
+func (ec *EppoClient) runAfterHooks(ctx context.Context, hookCtx *HookContext, result EvaluationResult) {
+	for _, hook := range ec.hooks {
+		ec.callHook(func() { hook.AfterEvaluation(ctx, hookCtx, result) })
+	}
+}
+
+func (ec *EppoClient) runFinallyHooks(ctx context.Context, hookCtx *HookContext, result EvaluationResult) {
+	for _, hook := range ec.hooks {
+		ec.callHook(func() { hook.FinallyEvaluation(ctx, hookCtx, result) })
+	}
+}
+
+func (ec *EppoClient) callHook(f func()) {
+	// need to catch panics from hooks and continue
+	defer func() {
+		r := recover()
+		if r != nil {
+			ec.applicationLogger.Errorf("panic occurred in evaluation hook: %v", r)
+		}
+	}()
+
+	f()
+}
@@ -0,0 +1,288 @@
+package eppoclient
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// testHook records calls and delegates to optional functions.
+type testHook struct {
+	name   string
+	calls  *[]string
+	before func(hookCtx *HookContext) *HookOverride
+	after  func(hookCtx *HookContext, result EvaluationResult)
+}
+
+func (h testHook) BeforeEvaluation(ctx context.Context, hookCtx *HookContext) *HookOverride {
+	*h.calls = append(*h.calls, fmt.Sprintf("%s.before(%s)", h.name, hookCtx.FlagKey))
+	if h.before != nil {
+		return h.before(hookCtx)
+	}
+	return nil
+}
+
+func (h testHook) AfterEvaluation(ctx context.Context, hookCtx *HookContext, result EvaluationResult) {
+	*h.calls = append(*h.calls, fmt.Sprintf("%s.after(%s)", h.name, hookCtx.FlagKey))
+	if h.after != nil {
+		h.after(hookCtx, result)
+	}
+}
+
+func (h testHook) FinallyEvaluation(ctx context.Context, hookCtx *HookContext, result EvaluationResult) {
+	*h.calls = append(*h.calls, fmt.Sprintf("%s.finally(%s)", h.name, hookCtx.FlagKey))
+}
+
+func newHooksTestClient(config configuration, logger IAssignmentLogger, hooks ...EvaluationHook) *EppoClient {
+	client := newEppoClient(newConfigurationStoreWithConfig(config), nil, nil, logger, nil, applicationLogger)
+	client.hooks = hooks
+	return client
+}
+
+func Test_Hooks_order(t *testing.T) {
+	var calls []string
+	var results []EvaluationResult
+	logger := new(mockLogger)
+	logger.Mock.On("LogAssignment", mock.Anything).Run(func(args mock.Arguments) {
+		calls = append(calls, "log")
+	})
+
+	client := newHooksTestClient(newAllAssignmentsTestConfiguration(), logger,
+		testHook{name: "first", calls: &calls, after: func(hookCtx *HookContext, result EvaluationResult) {
+			results = append(results, result)
+		}},
+		testHook{name: "second", calls: &calls},
+	)
+
+	value, err := client.GetBoolAssignment("bool-flag", "subject", Attributes{}, false)
+	assert.NoError(t, err)
+	assert.True(t, value)
+
+	assert.Equal(t, []string{
+		"first.before(bool-flag)",
+		"second.before(bool-flag)",
+		"first.after(bool-flag)",
+		"second.after(bool-flag)",
+		"log",
+		"first.finally(bool-flag)",
+		"second.finally(bool-flag)",
+	}, calls)
+	assert.Equal(t, []EvaluationResult{{
+		Value:         true,
+		VariationKey:  "on",
+		AllocationKey: "bool-allocation",
+	}}, results)
+}
+
+func Test_Hooks_mutateAttributes(t *testing.T) {
+	var calls []string
+	client := newHooksTestClient(newAllAssignmentsTestConfiguration(), nil,
+		testHook{name: "hook", calls: &calls, before: func(hookCtx *HookContext) *HookOverride {
+			hookCtx.SubjectAttributes = Attributes{"id": "other-subject"}
+			return nil
+		}},
+	)
+
+	value, details, err := client.GetStringAssignmentDetails("targeted-flag", "subject", Attributes{}, "default")
+	assert.NoError(t, err)
+	assert.Equal(t, "on", value)
+	assert.Equal(t, EvaluationReasonMatch, details.Reason)
+	assert.Equal(t, Attributes{"id": "other-subject"}, details.SubjectAttributes)
+}
+
+func Test_Hooks_mutateAttributesInPlace(t *testing.T) {
+	var calls []string
+	client := newHooksTestClient(newPrecomputedTestConfiguration(), nil,
+		testHook{name: "hook", calls: &calls, before: func(hookCtx *HookContext) *HookOverride {
+			if hookCtx.Bandit {
+				hookCtx.BanditSubjectAttributes.Numeric["added"] = 1
+				hookCtx.BanditActions["action"].Categorical["added"] = "yes"
+				delete(hookCtx.BanditActions, "other-action")
+			} else {
+				hookCtx.SubjectAttributes["id"] = "other-subject"
+			}
+			return nil
+		}},
+	)
+
+	attributes := Attributes{}
+	value, err := client.GetStringAssignment("targeted-flag", "subject", attributes, "default")
+	assert.NoError(t, err)
+	assert.Equal(t, "on", value)
+	// Hooks receive a copy of the caller's attributes.
+	assert.Equal(t, Attributes{}, attributes)
+
+	subjectAttributes := ContextAttributes{Numeric: map[string]float64{}, Categorical: map[string]string{}}
+	actions := map[string]ContextAttributes{
+		"action":       {Numeric: map[string]float64{}, Categorical: map[string]string{}},
+		"other-action": {},
+	}
+	client.GetBanditAction("bandit-flag", "subject", subjectAttributes, actions, "default")
+	assert.Empty(t, subjectAttributes.Numeric)
+	assert.Empty(t, actions["action"].Categorical)
+	assert.Contains(t, actions, "other-action")
+}
+
+func Test_Hooks_override(t *testing.T) {
+	var calls []string
+	logger := new(mockLogger)
+
+	client := newHooksTestClient(newAllAssignmentsTestConfiguration(), logger,
+		testHook{name: "first", calls: &calls, before: func(hookCtx *HookContext) *HookOverride {
+			switch hookCtx.FlagKey {
+			case "bool-flag":
+				return &HookOverride{Value: false}
+			case "json-flag":
+				return &HookOverride{Value: map[string]interface{}{"b": 2}}
+			case "targeted-flag":
+				// mismatching type is ignored
+				return &HookOverride{Value: 42}
+			}
+			return nil
+		}},
+		testHook{name: "second", calls: &calls},
+	)
+
+	value, details, err := client.GetBoolAssignmentDetails("bool-flag", "subject", Attributes{}, true)
+	assert.NoError(t, err)
+	assert.False(t, value)
+	assert.Equal(t, EvaluationReasonHookOverride, details.Reason)
+	assert.Equal(t, []string{
+		"first.before(bool-flag)",
+		"first.after(bool-flag)",
+		"second.after(bool-flag)",
+		"first.finally(bool-flag)",
+		"second.finally(bool-flag)",
+	}, calls)
+
+	jsonValue, err := client.GetJSONBytesAssignment("json-flag", "subject", Attributes{}, nil)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"b": 2}`, string(jsonValue))
+
+	stringValue, err := client.GetStringAssignment("targeted-flag", "other-subject", Attributes{}, "default")
+	assert.NoError(t, err)
+	assert.Equal(t, "on", stringValue)
+
+	// Overridden assignments are not logged.
+	logger.AssertNotCalled(t, "LogAssignment", mock.Anything)
+}
+
+func Test_Hooks_skipLogging(t *testing.T) {
+	var calls []string
+	logger := new(mockLogger)
+
+	client := newHooksTestClient(newAllAssignmentsTestConfiguration(), logger,
+		testHook{name: "hook", calls: &calls, after: func(hookCtx *HookContext, result EvaluationResult) {
+			hookCtx.SkipLogging = result.VariationKey == "on"
+		}},
+	)
+
+	value, err := client.GetBoolAssignment("bool-flag", "subject", Attributes{}, false)
+	assert.NoError(t, err)
+	assert.True(t, value)
+	logger.AssertNotCalled(t, "LogAssignment", mock.Anything)
+}
+
+func Test_Hooks_error(t *testing.T) {
+	var calls []string
+	var results []EvaluationResult
+	client := newHooksTestClient(newAllAssignmentsTestConfiguration(), nil,
+		testHook{name: "hook", calls: &calls, after: func(hookCtx *HookContext, result EvaluationResult) {
+			results = append(results, result)
+		}},
+	)
+
+	_, err := client.GetStringAssignment("missing-flag", "subject", Attributes{}, "default")
+	assert.Error(t, err)
+	assert.Len(t, results, 1)
+	assert.Nil(t, results[0].Value)
+	assert.ErrorIs(t, results[0].Error, ErrFlagConfigurationNotFound)
+	assert.Contains(t, calls, "hook.finally(missing-flag)")
+}
+
+func Test_Hooks_panic(t *testing.T) {
+	var calls []string
+	logger := new(mockLogger)
+	logger.Mock.On("LogAssignment", mock.Anything)
+
+	client := newHooksTestClient(newAllAssignmentsTestConfiguration(), logger,
+		testHook{name: "panicking", calls: &calls,
+			before: func(hookCtx *HookContext) *HookOverride { panic("before") },
+			after:  func(hookCtx *HookContext, result EvaluationResult) { panic("after") },
+		},
+		testHook{name: "next", calls: &calls},
+	)
+
+	value, err := client.GetBoolAssignment("bool-flag", "subject", Attributes{}, false)
+	assert.NoError(t, err)
+	assert.True(t, value)
+	assert.Equal(t, []string{
+		"panicking.before(bool-flag)",
+		"next.before(bool-flag)",
+		"panicking.after(bool-flag)",
+		"next.after(bool-flag)",
+		"panicking.finally(bool-flag)",
+		"next.finally(bool-flag)",
+	}, calls)
+	logger.AssertNumberOfCalls(t, "LogAssignment", 1)
+}
+
+func Test_Hooks_bandit(t *testing.T) {
+	var calls []string
+	var banditResults []EvaluationResult
+	logger := new(mockLogger)
+	logger.Mock.On("LogAssignment", mock.Anything).Return()
+
+	client := newHooksTestClient(newPrecomputedTestConfiguration(), logger,
+		testHook{name: "hook", calls: &calls, after: func(hookCtx *HookContext, result EvaluationResult) {
+			if hookCtx.Bandit {
+				banditResults = append(banditResults, result)
+				hookCtx.SkipLogging = true
+			}
+		}},
+	)
+
+	result := client.GetBanditAction("bandit-flag", "subject", ContextAttributes{}, map[string]ContextAttributes{"action": {}}, "default")
+	assert.Equal(t, "bandit", result.Variation)
+	assert.Equal(t, "action", *result.Action)
+
+	// Hooks run once: the underlying flag is evaluated without
+	// hooks.
+	assert.Equal(t, []string{
+		"hook.before(bandit-flag)",
+		"hook.after(bandit-flag)",
+		"hook.finally(bandit-flag)",
+	}, calls)
+	assert.Len(t, banditResults, 1)
+	assert.Equal(t, "bandit", banditResults[0].BanditKey)
+	assert.Equal(t, "action", *banditResults[0].Action)
+	assert.Equal(t, "bandit", banditResults[0].VariationKey)
+	assert.Equal(t, "bandit-allocation", banditResults[0].AllocationKey)
+	logger.AssertNotCalled(t, "LogAssignment", mock.Anything)
+	logger.AssertNotCalled(t, "LogBanditAction", mock.Anything)
+}
+
+func Test_Hooks_banditOverride(t *testing.T) {
+	var calls []string
+	action := "overridden-action"
+	client := newHooksTestClient(newPrecomputedTestConfiguration(), nil,
+		testHook{name: "hook", calls: &calls, before: func(hookCtx *HookContext) *HookOverride {
+			if hookCtx.Bandit {
+				return &HookOverride{Value: "control", Action: &action}
+			}
+			return nil
+		}},
+	)
+
+	result := client.GetBanditAction("bandit-flag", "subject", ContextAttributes{}, map[string]ContextAttributes{"action": {}}, "default")
+	assert.Equal(t, "control", result.Variation)
+	assert.Equal(t, &action, result.Action)
+	assert.Equal(t, []string{
+		"hook.before(bandit-flag)",
+		"hook.after(bandit-flag)",
+		"hook.finally(bandit-flag)",
+	}, calls)
+}
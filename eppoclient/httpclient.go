@@ -1,6 +1,8 @@
 package eppoclient
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"io"
 	"time"
@@ -10,44 +12,71 @@ import (
 
 const REQUEST_TIMEOUT_SECONDS = time.Duration(10 * time.Second)
 
+// errNotModified is returned by `getIfModified` when the server
+// responds with 304 Not Modified.
+var errNotModified = errors.New("not modified")
+
 type httpClient struct {
-	baseUrl        string
-	sdkParams      SDKParams
-	isUnauthorized bool
-	client         *http.Client
+	baseUrl   string
+	sdkParams SDKParams
+	client    *http.Client
+	// Optional metrics sink. nil if disabled.
+	metrics Metrics
 }
 
 type SDKParams struct {
 	sdkKey     string
 	sdkName    string
 	sdkVersion string
+	// Send SDK key in Authorization header instead of query
+	// string.
+	useAuthorizationHeader bool
 }
 
 func newHttpClient(baseUrl string, client *http.Client, sdkParams SDKParams) *httpClient {
 	var hc = &httpClient{
-		baseUrl:        baseUrl,
-		sdkParams:      sdkParams,
-		isUnauthorized: false,
-		client:         client,
+		baseUrl:   baseUrl,
+		sdkParams: sdkParams,
+		client:    client,
 	}
 	return hc
 }
 
+// resourceVersion holds cache validators of a previously fetched
+// resource.
+type resourceVersion struct {
+	eTag         string
+	lastModified string
+}
+
 func (hc *httpClient) get(resource string) ([]byte, error) {
-	url := hc.baseUrl + resource
+	result, _, err := hc.getIfModified(resource, resourceVersion{})
+	return result, err
+}
 
-	req, err := http.NewRequest(http.MethodGet, url, nil)
+// getIfModified fetches the resource using conditional request
+// headers derived from `version`. Returns the new version of the
+// resource, or `errNotModified` if the resource has not changed.
+func (hc *httpClient) getIfModified(resource string, version resourceVersion) ([]byte, resourceVersion, error) {
+	start := time.Now()
+	statusCode, size := 0, 0
+	if hc.metrics != nil {
+		defer func() {
+			hc.observeFetch(metricsResourceName(resource), statusCode, size, start)
+		}()
+	}
+
+	req, err := hc.newRequest(context.Background(), hc.baseUrl+resource)
 	if err != nil {
-		return nil, err
+		return nil, resourceVersion{}, err
 	}
 	req.Header.Set("Content-Type", "application/json; charset=UTF-8")
-
-	q := req.URL.Query()
-	// todo: migrate to bearer token authorization header
-	q.Add("apiKey", hc.sdkParams.sdkKey) // origin server uses apiKey
-	q.Add("sdkName", hc.sdkParams.sdkName)
-	q.Add("sdkVersion", hc.sdkParams.sdkVersion)
-	req.URL.RawQuery = q.Encode()
+	if version.eTag != "" {
+		req.Header.Set("If-None-Match", version.eTag)
+	}
+	if version.lastModified != "" {
+		req.Header.Set("If-Modified-Since", version.lastModified)
+	}
 
 	resp, err := hc.client.Do(req)
 	if err != nil {
@@ -60,22 +89,108 @@ func (hc *httpClient) get(resource string) ([]byte, error) {
 		//
 		// We should almost never expect to see this condition be executed.
 		// Scrub the error to prevent SDK key exposure in error messages.
-		return nil, fmt.Errorf("%s", maskSensitiveInfo(err.Error()))
+		return nil, resourceVersion{}, fmt.Errorf("%s", maskSensitiveInfo(err.Error()))
 	}
 	defer resp.Body.Close()
+	statusCode = resp.StatusCode
+
+	if resp.StatusCode == http.StatusNotModified {
+		return nil, version, errNotModified
+	}
 
 	if resp.StatusCode == 401 {
-		hc.isUnauthorized = true
-		return nil, fmt.Errorf("unauthorized access")
+		return nil, resourceVersion{}, ErrUnauthorized
 	}
 
 	if resp.StatusCode >= 500 {
-		return nil, fmt.Errorf("server error: %d", resp.StatusCode)
+		return nil, resourceVersion{}, fmt.Errorf("server error: %d", resp.StatusCode)
 	}
 
 	b, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return nil, fmt.Errorf("server error: unreadable body")
+		return nil, resourceVersion{}, fmt.Errorf("server error: unreadable body")
 	}
-	return b, nil
+	size = len(b)
+
+	newVersion := resourceVersion{
+		eTag:         resp.Header.Get("ETag"),
+		lastModified: resp.Header.Get("Last-Modified"),
+	}
+	return b, newVersion, nil
+}
+
+// openStream opens a Server-Sent Events stream at `url`. The caller
+// is responsible for closing the response body. Cancel `ctx` to close
+// the stream.
+func (hc *httpClient) openStream(ctx context.Context, url string) (*http.Response, error) {
+	req, err := hc.newRequest(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "text/event-stream")
+	req.Header.Set("Cache-Control", "no-cache")
+
+	start := time.Now()
+	resp, err := hc.client.Do(req)
+	if hc.metrics != nil {
+		statusCode := 0
+		if resp != nil {
+			statusCode = resp.StatusCode
+		}
+		hc.observeFetch(ResourceStream, statusCode, 0, start)
+	}
+	if err != nil {
+		// Scrub the error to prevent SDK key exposure in error messages.
+		return nil, fmt.Errorf("%s", maskSensitiveInfo(err.Error()))
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		if resp.StatusCode == 401 {
+			return nil, ErrUnauthorized
+		}
+		return nil, fmt.Errorf("unexpected stream response status: %d", resp.StatusCode)
+	}
+
+	return resp, nil
+}
+
+func (hc *httpClient) observeFetch(resource string, statusCode, size int, start time.Time) {
+	hc.metrics.ObserveConfigurationFetch(ConfigurationFetchMetric{
+		Resource:   resource,
+		StatusCode: statusCode,
+		Bytes:      size,
+		Duration:   time.Since(start),
+	})
+}
+
+func metricsResourceName(resource string) string {
+	switch resource {
+	case CONFIG_ENDPOINT:
+		return ResourceFlags
+	case BANDIT_ENDPOINT:
+		return ResourceBandits
+	default:
+		return resource
+	}
+}
+
+// newRequest creates a GET request to `url` with SDK parameters.
+func (hc *httpClient) newRequest(ctx context.Context, url string) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	q := req.URL.Query()
+	if hc.sdkParams.useAuthorizationHeader {
+		req.Header.Set("Authorization", "Bearer "+hc.sdkParams.sdkKey)
+	} else {
+		q.Add("apiKey", hc.sdkParams.sdkKey) // origin server uses apiKey
+	}
+	q.Add("sdkName", hc.sdkParams.sdkName)
+	q.Add("sdkVersion", hc.sdkParams.sdkVersion)
+	req.URL.RawQuery = q.Encode()
+
+	return req, nil
 }
@@ -2,6 +2,7 @@ package eppoclient
 
 import (
 	"bytes"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -85,3 +86,74 @@ func TestHttpClientGet(t *testing.T) {
 		})
 	}
 }
+
+func TestHttpClientGetIfModified(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") == `"v1"` || r.Header.Get("If-Modified-Since") == "Wed, 21 Oct 2015 07:28:00 GMT" {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		w.Header().Set("Last-Modified", "Wed, 21 Oct 2015 07:28:00 GMT")
+		_, _ = w.Write([]byte(`OK`))
+	}))
+	defer server.Close()
+
+	hc := newHttpClient(server.URL, &http.Client{}, SDKParams{})
+
+	result, version, err := hc.getIfModified("/test", resourceVersion{})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !bytes.Equal(result, []byte("OK")) {
+		t.Errorf("Expected result OK, got %v", result)
+	}
+	expectedVersion := resourceVersion{eTag: `"v1"`, lastModified: "Wed, 21 Oct 2015 07:28:00 GMT"}
+	if version != expectedVersion {
+		t.Errorf("Expected version %v, got %v", expectedVersion, version)
+	}
+
+	for _, v := range []resourceVersion{expectedVersion, {eTag: `"v1"`}, {lastModified: expectedVersion.lastModified}} {
+		result, version, err = hc.getIfModified("/test", v)
+		if !errors.Is(err, errNotModified) {
+			t.Errorf("Expected errNotModified, got %v", err)
+		}
+		if result != nil {
+			t.Errorf("Expected empty result, got %v", result)
+		}
+		if version != v {
+			t.Errorf("Expected version %v, got %v", v, version)
+		}
+	}
+}
+
+func TestHttpClientAuthorizationHeader(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer testSdkKey" || r.URL.Query().Has("apiKey") {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_, _ = w.Write([]byte(`OK`))
+	}))
+	defer server.Close()
+
+	hc := newHttpClient(server.URL, &http.Client{}, SDKParams{
+		sdkKey:                 "testSdkKey",
+		sdkName:                "testSdkName",
+		sdkVersion:             "testSdkVersion",
+		useAuthorizationHeader: true,
+	})
+	result, err := hc.get("/test")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if string(result) != "OK" {
+		t.Errorf("Expected result OK, got %s", result)
+	}
+
+	hc.sdkParams.useAuthorizationHeader = false
+	_, err = hc.get("/test")
+	if !errors.Is(err, ErrUnauthorized) {
+		t.Errorf("Expected ErrUnauthorized, got %v", err)
+	}
+}
@@ -11,7 +11,12 @@ func InitClient(config Config) (*EppoClient, error) {
 	if err != nil {
 		return nil, err
 	}
-	sdkParams := SDKParams{sdkKey: config.SdkKey, sdkName: "go", sdkVersion: __version__}
+	sdkParams := SDKParams{
+		sdkKey:                 config.SdkKey,
+		sdkName:                "go",
+		sdkVersion:             __version__,
+		useAuthorizationHeader: config.UseAuthorizationHeader,
+	}
 
 	// Wrap the user's logger with ScrubbingLogger to prevent SDK key exposure in logs
 	var applicationLogger ApplicationLogger
@@ -26,10 +31,18 @@ func InitClient(config Config) (*EppoClient, error) {
 		httpClientInstance = &http.Client{Timeout: REQUEST_TIMEOUT_SECONDS}
 	}
 	httpClient := newHttpClient(config.BaseUrl, httpClientInstance, sdkParams)
+	httpClient.metrics = config.Metrics
 	configStore := newConfigurationStore()
-	requestor := newConfigurationRequestor(*httpClient, configStore, applicationLogger)
+	configStore.metrics = config.Metrics
+
+	var cache *configurationCache
+	if config.ConfigurationCacheDir != "" {
+		cache = newConfigurationCache(config.ConfigurationCacheDir, config.SdkKey, config.ConfigurationCacheMaxAge)
+	}
+	requestor := newConfigurationRequestor(*httpClient, configStore, applicationLogger, cache)
+	requestor.loadFromCache()
 
-	poller := newPoller(config.PollerInterval, requestor.FetchAndStoreConfigurations, applicationLogger)
+	poller := newPoller(config.pollingPolicy(), requestor.FetchAndStoreConfigurations, applicationLogger)
 	client := newEppoClient(
 		configStore,
 		requestor,
@@ -38,8 +51,89 @@ func InitClient(config Config) (*EppoClient, error) {
 		config.AssignmentLoggerContext,
 		applicationLogger,
 	)
+	client.onUnauthorized = config.OnUnauthorized
+	client.metrics = config.Metrics
+	client.tracer = config.Tracer
+	client.hooks = config.Hooks
+	requestor.tracer = config.Tracer
+	requestor.onUnauthorized = client.handleUnauthorized
+
+	if err := client.initOverrides(config, applicationLogger); err != nil {
+		return nil, err
+	}
+
+	if config.StreamingUrl != "" {
+		client.streamer = newStreamer(config.StreamingUrl, *httpClient, requestor, poller, applicationLogger)
+		client.streamer.Start()
+	} else {
+		client.poller.Start()
+	}
+
+	return client, nil
+}
+
+// InitClientFromConfiguration creates an instance of EppoClient
+// from configuration provided in memory, without making any network
+// requests.
+//
+// `flagsJSON` is the flags configuration in UFC format (as served at
+// CONFIG_ENDPOINT) and `banditsJSON` is the optional bandit models
+// configuration (as served at BANDIT_ENDPOINT). SdkKey, BaseUrl,
+// PollerInterval, and HttpClient options are ignored.
+//
+// The client is initialized immediately and never polls for updates.
+// Use SetConfiguration to replace the configuration manually.
+func InitClientFromConfiguration(config Config, flagsJSON, banditsJSON []byte) (*EppoClient, error) {
+	err := config.setDefaults()
+	if err != nil {
+		return nil, err
+	}
+
+	configuration, err := newConfigurationFromJSON(flagsJSON, banditsJSON)
+	if err != nil {
+		return nil, err
+	}
+
+	err = configuration.validate(len(banditsJSON) > 0, config.ApplicationLogger)
+	if err != nil {
+		return nil, err
+	}
+
+	configStore := newConfigurationStore()
+	configStore.metrics = config.Metrics
+	configStore.setConfiguration(configuration)
+
+	client := newEppoClient(
+		configStore,
+		nil,
+		nil,
+		config.AssignmentLogger,
+		config.AssignmentLoggerContext,
+		NewScrubbingLogger(config.ApplicationLogger),
+	)
+	client.metrics = config.Metrics
+	client.tracer = config.Tracer
+	client.hooks = config.Hooks
 
-	client.poller.Start()
+	if err := client.initOverrides(config, client.applicationLogger); err != nil {
+		return nil, err
+	}
 
 	return client, nil
 }
+
+// initOverrides loads local overrides and starts watching the
+// overrides file if enabled.
+func (ec *EppoClient) initOverrides(config Config, applicationLogger ApplicationLogger) error {
+	if config.OverridesFile == "" {
+		return nil
+	}
+
+	overrides, err := newOverrideStore(config.OverridesFile, config.OverridesReloadInterval, config.LogOverriddenAssignments, applicationLogger)
+	if err != nil {
+		return err
+	}
+	ec.overrides = overrides
+	ec.overrides.poller.Start()
+	return nil
+}
@@ -0,0 +1,69 @@
+package eppoclient
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const initClientTestFlags = `{
+  "flags": {
+    "flag": {
+      "key": "flag",
+      "enabled": true,
+      "variationType": "STRING",
+      "totalShards": 10000,
+      "variations": {"on": {"key": "on", "value": "on"}, "off": {"key": "off", "value": "off"}},
+      "allocations": [{"key": "allocation", "splits": [{"variationKey": "on", "shards": []}]}]
+    }
+  }
+}`
+
+func Test_InitClientFromConfiguration(t *testing.T) {
+	client, err := InitClientFromConfiguration(Config{ApplicationLogger: applicationLogger}, []byte(initClientTestFlags), nil)
+	assert.NoError(t, err)
+
+	select {
+	case <-client.Initialized():
+	default:
+		t.Fatal("client is not initialized")
+	}
+	assert.Nil(t, client.poller)
+
+	value, err := client.GetStringAssignment("flag", "subject", Attributes{}, "default")
+	assert.NoError(t, err)
+	assert.Equal(t, "on", value)
+}
+
+func Test_InitClientFromConfiguration_invalidConfiguration(t *testing.T) {
+	_, err := InitClientFromConfiguration(Config{ApplicationLogger: applicationLogger}, []byte(`{"flags": `), nil)
+	assert.Error(t, err)
+
+	_, err = InitClientFromConfiguration(Config{ApplicationLogger: applicationLogger}, []byte(`{}`), nil)
+	assert.Error(t, err)
+
+	_, err = InitClientFromConfiguration(Config{ApplicationLogger: applicationLogger}, []byte(initClientTestFlags), []byte(`[]`))
+	assert.Error(t, err)
+}
+
+func Test_SetConfiguration(t *testing.T) {
+	client, err := InitClientFromConfiguration(Config{ApplicationLogger: applicationLogger}, []byte(`{"flags": {}}`), nil)
+	assert.NoError(t, err)
+
+	value, err := client.GetStringAssignment("flag", "subject", Attributes{}, "default")
+	assert.ErrorIs(t, err, ErrFlagConfigurationNotFound)
+	assert.Equal(t, "default", value)
+
+	err = client.SetConfiguration([]byte(initClientTestFlags), nil)
+	assert.NoError(t, err)
+
+	value, err = client.GetStringAssignment("flag", "subject", Attributes{}, "default")
+	assert.NoError(t, err)
+	assert.Equal(t, "on", value)
+
+	// invalid configuration keeps the previous one
+	err = client.SetConfiguration([]byte(`not json`), nil)
+	assert.Error(t, err)
+	value, _ = client.GetStringAssignment("flag", "subject", Attributes{}, "default")
+	assert.Equal(t, "on", value)
+}
@@ -0,0 +1,109 @@
+package eppoclient
+
+import (
+	"context"
+	"fmt"
+)
+
+// Close stops polling (and watching the local overrides file), waits
+// for an in-flight configuration fetch to complete, and flushes
+// assignment loggers implementing LoggerFlusher.
+//
+// Configuration change listeners are unregistered.
+//
+// After Close is called, assignment functions return the default
+// value with ErrClientClosed and bandit functions return the default
+// variation without an action. Nothing is logged.
+//
+// Close returns ctx.Err() if `ctx` is done before the in-flight fetch
+// completes or loggers are flushed. Calling Close more than once is a
+// no-op.
+func (ec *EppoClient) Close(ctx context.Context) error {
+	if !ec.closed.CompareAndSwap(false, true) {
+		return nil
+	}
+
+	ec.configurationStore.unsubscribeAll()
+
+	// Overrides file watcher is stopped even if stopping polling
+	// fails.
+	err := ec.StopPolling(ctx)
+	if ec.overrides != nil {
+		if overridesErr := ec.overrides.poller.StopAndWait(ctx); err == nil {
+			err = overridesErr
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	return ec.flushLoggers(ctx)
+}
+
+// StopPolling stops configuration polling (and streaming, if
+// enabled) and waits for an in-flight fetch to complete or `ctx` to be
+// done. The client keeps serving assignments from the last fetched
+// configuration.
+//
+// Polling may be resumed with StartPolling.
+func (ec *EppoClient) StopPolling(ctx context.Context) error {
+	// Streamer must be stopped first, as it may restart the poller.
+	if ec.streamer != nil {
+		if err := ec.streamer.StopAndWait(ctx); err != nil {
+			return err
+		}
+	}
+	if ec.poller != nil {
+		return ec.poller.StopAndWait(ctx)
+	}
+	return nil
+}
+
+// StartPolling resumes configuration polling (or streaming, if
+// enabled) stopped with StopPolling. Does nothing if the client is
+// already polling.
+//
+// Returns an error if the client has been closed or was initialized
+// without polling (see InitClientFromConfiguration).
+func (ec *EppoClient) StartPolling() error {
+	if ec.closed.Load() {
+		return ErrClientClosed
+	}
+	if ec.poller == nil {
+		return fmt.Errorf("client was initialized without polling")
+	}
+	if ec.streamer != nil {
+		ec.streamer.Start()
+	} else {
+		ec.poller.Start()
+	}
+	return nil
+}
+
+func (ec *EppoClient) flushLoggers(ctx context.Context) error {
+	var firstErr error
+	flush := func(logger interface{}) {
+		flusher, ok := logger.(LoggerFlusher)
+		if !ok {
+			return
+		}
+		// need to catch panics from Logger and continue
+		defer func() {
+			if r := recover(); r != nil {
+				ec.applicationLogger.Errorf("panic occurred while flushing logger: %v", r)
+			}
+		}()
+		if err := flusher.Flush(ctx); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+
+	if ec.loggerContext != nil {
+		flush(ec.loggerContext)
+	}
+	if ec.logger != nil {
+		flush(ec.logger)
+	}
+
+	return firstErr
+}
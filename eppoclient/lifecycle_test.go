@@ -0,0 +1,130 @@
+package eppoclient
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func Test_Close(t *testing.T) {
+	logger := new(mockFlushingLogger)
+	logger.Mock.On("LogAssignment", mock.Anything).Return()
+	logger.Mock.On("Flush", mock.Anything).Return(nil)
+
+	poller := newPoller(pollingPolicy{interval: 1 * time.Hour}, func() error { return nil }, applicationLogger)
+	poller.Start()
+	client := newEppoClient(newConfigurationStoreWithConfig(newPrecomputedTestConfiguration()), nil, poller, logger, nil, applicationLogger)
+
+	boolValue, err := client.GetBoolAssignment("bool-flag", "subject", Attributes{}, false)
+	assert.NoError(t, err)
+	assert.True(t, boolValue)
+
+	assert.NoError(t, client.Close(context.Background()))
+	logger.AssertNumberOfCalls(t, "Flush", 1)
+
+	value, err := client.GetStringAssignment("bandit-flag", "subject", Attributes{}, "default")
+	assert.ErrorIs(t, err, ErrClientClosed)
+	assert.Equal(t, "default", value)
+
+	_, details, err := client.GetStringAssignmentDetails("bandit-flag", "subject", Attributes{}, "default")
+	assert.ErrorIs(t, err, ErrClientClosed)
+	assert.Equal(t, EvaluationReasonDefaultUsed, details.Reason)
+
+	result := client.GetBanditAction("bandit-flag", "subject", ContextAttributes{}, map[string]ContextAttributes{"action": {}}, "default")
+	assert.Equal(t, BanditResult{Variation: "default"}, result)
+
+	_, err = client.GetAllAssignments(context.Background(), "subject", Attributes{})
+	assert.ErrorIs(t, err, ErrClientClosed)
+
+	logger.AssertNumberOfCalls(t, "LogAssignment", 1)
+
+	// Close is idempotent
+	assert.NoError(t, client.Close(context.Background()))
+	logger.AssertNumberOfCalls(t, "Flush", 1)
+
+	assert.ErrorIs(t, client.StartPolling(), ErrClientClosed)
+}
+
+func Test_Close_stopsOverridesIfStopPollingFails(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+	started := make(chan struct{})
+	poller := newPoller(pollingPolicy{interval: 1 * time.Hour}, func() error {
+		close(started)
+		<-release
+		return nil
+	}, applicationLogger)
+	poller.Start()
+	<-started
+
+	client := newEppoClient(newConfigurationStore(), nil, poller, nil, nil, applicationLogger)
+	client.overrides = &overrideStore{poller: newPoller(pollingPolicy{interval: 1 * time.Hour}, func() error { return nil }, applicationLogger)}
+	client.overrides.poller.Start()
+
+	// The in-flight fetch never completes.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.ErrorIs(t, client.Close(ctx), context.Canceled)
+
+	client.overrides.poller.mu.Lock()
+	defer client.overrides.poller.mu.Unlock()
+	assert.Nil(t, client.overrides.poller.stopCh)
+}
+
+func Test_Close_withoutPoller(t *testing.T) {
+	client, err := InitClientFromConfiguration(Config{ApplicationLogger: applicationLogger}, []byte(initClientTestFlags), nil)
+	assert.NoError(t, err)
+
+	assert.NoError(t, client.Close(context.Background()))
+	assert.Error(t, client.StartPolling())
+}
+
+func Test_Close_flushesWrappedLogger(t *testing.T) {
+	inner := new(mockFlushingLogger)
+	inner.Mock.On("Flush", mock.Anything).Return(nil)
+	logger, err := NewLruAssignmentLogger(inner, 100)
+	assert.NoError(t, err)
+
+	client := newEppoClient(newConfigurationStore(), nil, nil, logger, nil, applicationLogger)
+	assert.NoError(t, client.Close(context.Background()))
+	inner.AssertNumberOfCalls(t, "Flush", 1)
+}
+
+func Test_StopPolling_StartPolling(t *testing.T) {
+	var flags configResponse
+	assert.NoError(t, json.Unmarshal([]byte(initClientTestFlags), &flags))
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		assert.NoError(t, json.NewEncoder(w).Encode(flags))
+	}))
+	defer server.Close()
+
+	client, err := InitClient(Config{
+		BaseUrl:           server.URL,
+		SdkKey:            "sdk-key",
+		ApplicationLogger: applicationLogger,
+		PollerInterval:    1 * time.Hour,
+	})
+	assert.NoError(t, err)
+	defer client.Close(context.Background())
+
+	<-client.Initialized()
+	assert.NoError(t, client.StopPolling(context.Background()))
+	assert.Equal(t, int32(1), requests.Load())
+
+	// Client keeps serving the last configuration while stopped.
+	value, err := client.GetStringAssignment("flag", "subject", Attributes{}, "default")
+	assert.NoError(t, err)
+	assert.Equal(t, "on", value)
+
+	assert.NoError(t, client.StartPolling())
+	assert.Eventually(t, func() bool { return requests.Load() == 2 }, time.Second, 10*time.Millisecond)
+}
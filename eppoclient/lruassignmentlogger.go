@@ -1,6 +1,7 @@
 package eppoclient
 
 import (
+	"context"
 	"fmt"
 
 	lru "github.com/hashicorp/golang-lru/v2"
@@ -56,3 +57,11 @@ func (lal *LruAssignmentLogger) LogBanditAction(event BanditEvent) {
 		logger.LogBanditAction(event)
 	}
 }
+
+// Flush flushes the underlying logger if it implements LoggerFlusher.
+func (lal *LruAssignmentLogger) Flush(ctx context.Context) error {
+	if inner, ok := lal.inner.(LoggerFlusher); ok {
+		return inner.Flush(ctx)
+	}
+	return nil
+}
@@ -1,6 +1,7 @@
 package eppoclient
 
 import (
+	"context"
 	"fmt"
 
 	lru "github.com/hashicorp/golang-lru/v2"
@@ -59,3 +60,11 @@ func (logger *LruBanditLogger) LogBanditAction(event BanditEvent) {
 		logger.cache.Add(key, value)
 	}
 }
+
+// Flush flushes the underlying logger if it implements LoggerFlusher.
+func (logger *LruBanditLogger) Flush(ctx context.Context) error {
+	if inner, ok := logger.inner.(LoggerFlusher); ok {
+		return inner.Flush(ctx)
+	}
+	return nil
+}
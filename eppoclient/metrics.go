@@ -0,0 +1,143 @@
+package eppoclient
+
+import (
+	"errors"
+	"time"
+)
+
+// Metrics receives telemetry about SDK operation. See
+// NewPrometheusMetrics and NewExpvarMetrics for built-in
+// implementations.
+//
+// Methods are called synchronously on the evaluation path, so
+// implementations must be fast and safe for concurrent use.
+type Metrics interface {
+	// ObserveEvaluation is called after each flag evaluation.
+	ObserveEvaluation(EvaluationMetric)
+	// ObserveBanditEvaluation is called after each bandit
+	// evaluation.
+	ObserveBanditEvaluation(BanditEvaluationMetric)
+	// ObserveConfigurationFetch is called after each request to
+	// Eppo.
+	ObserveConfigurationFetch(ConfigurationFetchMetric)
+	// ObserveConfigurationUpdate is called when new configuration
+	// becomes active.
+	ObserveConfigurationUpdate(ConfigurationUpdateMetric)
+	// ObserveLoggerPanic is called when a panic in an assignment
+	// logger is recovered.
+	ObserveLoggerPanic(LoggerPanicMetric)
+}
+
+// EvaluationOutcome is the outcome of flag evaluation.
+type EvaluationOutcome string
+
+const (
+	// Subject has been assigned a variation.
+	EvaluationOutcomeAssigned EvaluationOutcome = "assigned"
+	// Default value has been returned because the flag is disabled
+	// or no allocation matched the subject.
+	EvaluationOutcomeDefault EvaluationOutcome = "default"
+	// Default value has been returned due to an error. See
+	// EvaluationMetric.ErrorKind.
+	EvaluationOutcomeError EvaluationOutcome = "error"
+)
+
+// Kinds of evaluation errors.
+const (
+	EvaluationErrorFlagNotFound    = "flag_not_found"
+	EvaluationErrorTypeMismatch    = "type_mismatch"
+	EvaluationErrorInvalidArgument = "invalid_argument"
+	EvaluationErrorClientClosed    = "client_closed"
+	EvaluationErrorOther           = "other"
+)
+
+type EvaluationMetric struct {
+	FlagKey string
+	Outcome EvaluationOutcome
+	// One of EvaluationError* constants if Outcome is
+	// EvaluationOutcomeError. Empty otherwise.
+	ErrorKind string
+	// Time taken by evaluation, including assignment logging.
+	Duration time.Duration
+}
+
+type BanditEvaluationMetric struct {
+	FlagKey   string
+	BanditKey string
+	Duration  time.Duration
+}
+
+// Resources reported in ConfigurationFetchMetric.
+const (
+	ResourceFlags   = "flags"
+	ResourceBandits = "bandits"
+	ResourceStream  = "stream"
+)
+
+type ConfigurationFetchMetric struct {
+	// One of Resource* constants.
+	Resource string
+	// HTTP status code. Zero if the request has failed without a
+	// response (e.g., network error).
+	StatusCode int
+	// Size of the response body in bytes.
+	Bytes    int
+	Duration time.Duration
+}
+
+type ConfigurationUpdateMetric struct {
+	// Time when the configuration was fetched from Eppo. Use it to
+	// compute configuration age.
+	FetchedAt time.Time
+	// Whether configuration was loaded from on-disk cache.
+	FromCache bool
+}
+
+// Loggers reported in LoggerPanicMetric.
+const (
+	LoggerAssignment = "assignment"
+	LoggerBandit     = "bandit"
+)
+
+type LoggerPanicMetric struct {
+	// One of Logger* constants.
+	Logger string
+}
+
+func (ec *EppoClient) observeEvaluation(flagKey string, err error, duration time.Duration) {
+	outcome, errorKind := classifyEvaluationError(err)
+	ec.metrics.ObserveEvaluation(EvaluationMetric{
+		FlagKey:   flagKey,
+		Outcome:   outcome,
+		ErrorKind: errorKind,
+		Duration:  duration,
+	})
+}
+
+// classifyEvaluationError maps the error returned by evaluation to
+// its outcome and one of EvaluationError* kinds.
+func classifyEvaluationError(err error) (EvaluationOutcome, string) {
+	var typeMismatch typeMismatchError
+	switch {
+	case err == nil:
+		return EvaluationOutcomeAssigned, ""
+	case errors.Is(err, ErrFlagNotEnabled), errors.Is(err, ErrSubjectAllocation):
+		return EvaluationOutcomeDefault, ""
+	case errors.Is(err, ErrFlagConfigurationNotFound):
+		return EvaluationOutcomeError, EvaluationErrorFlagNotFound
+	case errors.As(err, &typeMismatch):
+		return EvaluationOutcomeError, EvaluationErrorTypeMismatch
+	case errors.Is(err, errNoSubjectKey), errors.Is(err, errNoFlagKey):
+		return EvaluationOutcomeError, EvaluationErrorInvalidArgument
+	case errors.Is(err, ErrClientClosed):
+		return EvaluationOutcomeError, EvaluationErrorClientClosed
+	default:
+		return EvaluationOutcomeError, EvaluationErrorOther
+	}
+}
+
+func (ec *EppoClient) observeLoggerPanic(metric LoggerPanicMetric) {
+	if ec.metrics != nil {
+		ec.metrics.ObserveLoggerPanic(metric)
+	}
+}
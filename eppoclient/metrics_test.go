@@ -0,0 +1,155 @@
+package eppoclient
+
+import (
+	"encoding/json"
+	"expvar"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func Test_Metrics_evaluations(t *testing.T) {
+	metrics := NewPrometheusMetrics()
+	logger := new(mockLogger)
+	logger.Mock.On("LogAssignment", mock.Anything).Panic("logger panic")
+
+	client, err := InitClientFromConfiguration(Config{
+		AssignmentLogger:  logger,
+		ApplicationLogger: applicationLogger,
+		Metrics:           metrics,
+	}, []byte(initClientTestFlags), nil)
+	assert.NoError(t, err)
+
+	_, _ = client.GetStringAssignment("flag", "subject", Attributes{}, "default")
+	_, _ = client.GetStringAssignment("flag", "subject", Attributes{}, "default")
+	_, _ = client.GetStringAssignment("missing-flag", "subject", Attributes{}, "default")
+	_, _ = client.GetBoolAssignment("flag", "subject", Attributes{}, false)
+	_, _ = client.GetStringAssignment("flag", "", Attributes{}, "default")
+
+	assert.Equal(t, []counterSample{
+		{labels: labels{"flag", "assigned", ""}, value: 2},
+		{labels: labels{"flag", "error", "invalid_argument"}, value: 1},
+		{labels: labels{"flag", "error", "type_mismatch"}, value: 1},
+		{labels: labels{"missing-flag", "error", "flag_not_found"}, value: 1},
+	}, metrics.evaluations.snapshot())
+	assert.Equal(t, uint64(5), metrics.evaluationDuration.snapshot().count)
+	assert.Equal(t, []counterSample{{labels: labels{"assignment"}, value: 2}}, metrics.loggerPanics.snapshot())
+	assert.Equal(t, uint64(1), metrics.configurationUpdates.Load())
+}
+
+func Test_Metrics_defaultOutcome(t *testing.T) {
+	metrics := NewPrometheusMetrics()
+	client := newEppoClient(newConfigurationStoreWithConfig(newAllAssignmentsTestConfiguration()), nil, nil, nil, nil, applicationLogger)
+	client.metrics = metrics
+
+	_, _ = client.GetStringAssignment("targeted-flag", "subject", Attributes{}, "default")
+	_, _ = client.GetStringAssignment("disabled-flag", "subject", Attributes{}, "default")
+
+	assert.Equal(t, []counterSample{
+		{labels: labels{"disabled-flag", "default", ""}, value: 1},
+		{labels: labels{"targeted-flag", "default", ""}, value: 1},
+	}, metrics.evaluations.snapshot())
+}
+
+func Test_Metrics_fetches(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, initClientTestFlags)
+	}))
+	defer server.Close()
+
+	metrics := NewPrometheusMetrics()
+	client, err := InitClient(Config{
+		BaseUrl:           server.URL,
+		SdkKey:            "sdk-key",
+		ApplicationLogger: applicationLogger,
+		Metrics:           metrics,
+	})
+	assert.NoError(t, err)
+	defer client.poller.Stop()
+	<-client.Initialized()
+
+	assert.Equal(t, []counterSample{{labels: labels{"flags", "200"}, value: 1}}, metrics.fetches.snapshot())
+	assert.Equal(t, []counterSample{{labels: labels{"flags"}, value: uint64(len(initClientTestFlags))}}, metrics.fetchBytes.snapshot())
+	age, ok := metrics.configurationAge()
+	assert.True(t, ok)
+	assert.Less(t, age, 1.0)
+}
+
+func Test_PrometheusMetrics_ServeHTTP(t *testing.T) {
+	metrics := NewPrometheusMetrics()
+	metrics.ObserveEvaluation(EvaluationMetric{FlagKey: `a"b`, Outcome: EvaluationOutcomeAssigned, Duration: 3 * time.Microsecond})
+	metrics.ObserveConfigurationFetch(ConfigurationFetchMetric{Resource: ResourceFlags, StatusCode: 200, Bytes: 10, Duration: 20 * time.Millisecond})
+	metrics.ObserveConfigurationFetch(ConfigurationFetchMetric{Resource: ResourceFlags, Duration: time.Second})
+
+	recorder := httptest.NewRecorder()
+	metrics.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	body := recorder.Body.String()
+
+	assert.Contains(t, recorder.Header().Get("Content-Type"), "text/plain")
+	for _, line := range []string{
+		"# TYPE eppo_evaluations_total counter",
+		`eppo_evaluations_total{flag="a\"b",outcome="assigned",error_kind=""} 1`,
+		"# TYPE eppo_evaluation_duration_seconds histogram",
+		`eppo_evaluation_duration_seconds_bucket{le="2.5e-06"} 0`,
+		`eppo_evaluation_duration_seconds_bucket{le="5e-06"} 1`,
+		`eppo_evaluation_duration_seconds_bucket{le="+Inf"} 1`,
+		"eppo_evaluation_duration_seconds_count 1",
+		`eppo_configuration_fetches_total{resource="flags",status="200"} 1`,
+		`eppo_configuration_fetches_total{resource="flags",status="error"} 1`,
+		`eppo_configuration_fetch_bytes_total{resource="flags"} 10`,
+		`eppo_configuration_fetch_duration_seconds_bucket{resource="flags",le="0.025"} 1`,
+		`eppo_configuration_fetch_duration_seconds_bucket{resource="flags",le="1"} 2`,
+		`eppo_configuration_fetch_duration_seconds_sum{resource="flags"} 1.02`,
+		"eppo_configuration_updates_total 0",
+	} {
+		assert.Contains(t, body, line+"\n")
+	}
+	// no configuration yet
+	assert.NotContains(t, body, "eppo_configuration_age_seconds")
+}
+
+func Test_ExpvarMetrics(t *testing.T) {
+	metrics := NewExpvarMetrics("eppo_test_metrics")
+	metrics.ObserveEvaluation(EvaluationMetric{FlagKey: "flag", Outcome: EvaluationOutcomeAssigned})
+	metrics.ObserveEvaluation(EvaluationMetric{FlagKey: "flag", Outcome: EvaluationOutcomeError, ErrorKind: EvaluationErrorTypeMismatch})
+	metrics.ObserveLoggerPanic(LoggerPanicMetric{Logger: LoggerBandit})
+
+	var vars struct {
+		Evaluations  map[string]map[string]uint64 `json:"evaluations"`
+		LoggerPanics map[string]uint64            `json:"logger_panics"`
+	}
+	assert.NoError(t, json.Unmarshal([]byte(expvar.Get("eppo_test_metrics").String()), &vars))
+	assert.Equal(t, map[string]map[string]uint64{
+		"flag": {"assigned": 1, "error:type_mismatch": 1},
+	}, vars.Evaluations)
+	assert.Equal(t, map[string]uint64{"bandit": 1}, vars.LoggerPanics)
+}
+
+func Test_histogram(t *testing.T) {
+	h := newHistogram([]float64{1, 2, 5})
+	for _, value := range []float64{0.5, 1, 1.5, 3, 10} {
+		h.observe(value)
+	}
+
+	snapshot := h.snapshot()
+	assert.Equal(t, []uint64{2, 3, 4}, snapshot.cumulativeCounts)
+	assert.Equal(t, uint64(5), snapshot.count)
+	assert.Equal(t, 16.0, snapshot.sum)
+}
+
+func Test_Metrics_banditEvaluations(t *testing.T) {
+	metrics := NewPrometheusMetrics()
+	client := newEppoClient(newConfigurationStoreWithConfig(newPrecomputedTestConfiguration()), nil, nil, nil, nil, applicationLogger)
+	client.metrics = metrics
+
+	result := client.GetBanditAction("bandit-flag", "subject", ContextAttributes{}, map[string]ContextAttributes{"action": {}}, "default")
+	assert.NotNil(t, result.Action)
+
+	assert.Equal(t, []counterSample{{labels: labels{"bandit-flag", "bandit"}, value: 1}}, metrics.banditEvaluations.snapshot())
+	assert.Equal(t, uint64(1), metrics.banditEvaluationDuration.snapshot().count)
+}
@@ -0,0 +1,215 @@
+package eppoclient
+
+import (
+	"math"
+	"sort"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Histogram buckets in seconds.
+var (
+	evaluationDurationBuckets = []float64{0.000001, 0.0000025, 0.000005, 0.00001, 0.000025, 0.00005, 0.0001, 0.00025, 0.0005, 0.001, 0.0025, 0.005, 0.01}
+	fetchDurationBuckets      = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+)
+
+// `metricsCollector` aggregates metrics in memory. It is shared by
+// built-in Metrics implementations, which only differ in how metrics
+// are exposed.
+//
+// Counters are lock-free, so observing metrics does not contend on
+// the evaluation path.
+type metricsCollector struct {
+	// flag, outcome, error kind
+	evaluations        counterVec
+	evaluationDuration *histogram
+	// flag, bandit
+	banditEvaluations        counterVec
+	banditEvaluationDuration *histogram
+	// resource, status code
+	fetches counterVec
+	// resource
+	fetchBytes    counterVec
+	fetchDuration histogramVec
+	// Unix time in nanoseconds when the active configuration was
+	// fetched. Zero if there is no configuration.
+	configurationFetchedAt atomic.Int64
+	configurationUpdates   atomic.Uint64
+	// logger
+	loggerPanics counterVec
+}
+
+func newMetricsCollector() *metricsCollector {
+	return &metricsCollector{
+		evaluationDuration:       newHistogram(evaluationDurationBuckets),
+		banditEvaluationDuration: newHistogram(evaluationDurationBuckets),
+		fetchDuration:            histogramVec{buckets: fetchDurationBuckets},
+	}
+}
+
+func (c *metricsCollector) ObserveEvaluation(metric EvaluationMetric) {
+	c.evaluations.add(labels{metric.FlagKey, string(metric.Outcome), metric.ErrorKind}, 1)
+	c.evaluationDuration.observe(metric.Duration.Seconds())
+}
+
+func (c *metricsCollector) ObserveBanditEvaluation(metric BanditEvaluationMetric) {
+	c.banditEvaluations.add(labels{metric.FlagKey, metric.BanditKey}, 1)
+	c.banditEvaluationDuration.observe(metric.Duration.Seconds())
+}
+
+func (c *metricsCollector) ObserveConfigurationFetch(metric ConfigurationFetchMetric) {
+	status := "error"
+	if metric.StatusCode != 0 {
+		status = strconv.Itoa(metric.StatusCode)
+	}
+	c.fetches.add(labels{metric.Resource, status}, 1)
+	c.fetchBytes.add(labels{metric.Resource}, uint64(metric.Bytes))
+	c.fetchDuration.observe(labels{metric.Resource}, metric.Duration.Seconds())
+}
+
+func (c *metricsCollector) ObserveConfigurationUpdate(metric ConfigurationUpdateMetric) {
+	c.configurationFetchedAt.Store(metric.FetchedAt.UnixNano())
+	c.configurationUpdates.Add(1)
+}
+
+func (c *metricsCollector) ObserveLoggerPanic(metric LoggerPanicMetric) {
+	c.loggerPanics.add(labels{metric.Logger}, 1)
+}
+
+// configurationAge returns age of the active configuration in
+// seconds, or false if there is no configuration.
+func (c *metricsCollector) configurationAge() (float64, bool) {
+	fetchedAt := c.configurationFetchedAt.Load()
+	if fetchedAt == 0 {
+		return 0, false
+	}
+	return time.Since(time.Unix(0, fetchedAt)).Seconds(), true
+}
+
+// Label values of a metric. Unused labels are empty.
+type labels [3]string
+
+type counterVec struct {
+	// labels -> *atomic.Uint64
+	values sync.Map
+}
+
+func (c *counterVec) add(l labels, n uint64) {
+	value, ok := c.values.Load(l)
+	if !ok {
+		value, _ = c.values.LoadOrStore(l, new(atomic.Uint64))
+	}
+	value.(*atomic.Uint64).Add(n)
+}
+
+type counterSample struct {
+	labels labels
+	value  uint64
+}
+
+// snapshot returns counter values sorted by labels.
+func (c *counterVec) snapshot() []counterSample {
+	var samples []counterSample
+	c.values.Range(func(key, value interface{}) bool {
+		samples = append(samples, counterSample{labels: key.(labels), value: value.(*atomic.Uint64).Load()})
+		return true
+	})
+	sort.Slice(samples, func(i, j int) bool {
+		return lessLabels(samples[i].labels, samples[j].labels)
+	})
+	return samples
+}
+
+type histogram struct {
+	// Upper bounds of buckets, sorted.
+	buckets []float64
+	// Non-cumulative counts per bucket. The last one is +Inf.
+	counts  []atomic.Uint64
+	count   atomic.Uint64
+	sumBits atomic.Uint64
+}
+
+func newHistogram(buckets []float64) *histogram {
+	return &histogram{
+		buckets: buckets,
+		counts:  make([]atomic.Uint64, len(buckets)+1),
+	}
+}
+
+func (h *histogram) observe(value float64) {
+	h.counts[sort.SearchFloat64s(h.buckets, value)].Add(1)
+	h.count.Add(1)
+	for {
+		old := h.sumBits.Load()
+		sum := math.Float64frombits(old) + value
+		if h.sumBits.CompareAndSwap(old, math.Float64bits(sum)) {
+			return
+		}
+	}
+}
+
+type histogramSnapshot struct {
+	buckets []float64
+	// Cumulative counts per bucket, excluding +Inf.
+	cumulativeCounts []uint64
+	count            uint64
+	sum              float64
+}
+
+func (h *histogram) snapshot() histogramSnapshot {
+	snapshot := histogramSnapshot{
+		buckets:          h.buckets,
+		cumulativeCounts: make([]uint64, len(h.buckets)),
+		count:            h.count.Load(),
+		sum:              math.Float64frombits(h.sumBits.Load()),
+	}
+	var cumulative uint64
+	for i := range h.buckets {
+		cumulative += h.counts[i].Load()
+		snapshot.cumulativeCounts[i] = cumulative
+	}
+	return snapshot
+}
+
+type histogramVec struct {
+	buckets []float64
+	// labels -> *histogram
+	values sync.Map
+}
+
+func (v *histogramVec) observe(l labels, value float64) {
+	h, ok := v.values.Load(l)
+	if !ok {
+		h, _ = v.values.LoadOrStore(l, newHistogram(v.buckets))
+	}
+	h.(*histogram).observe(value)
+}
+
+type histogramSample struct {
+	labels labels
+	value  histogramSnapshot
+}
+
+// snapshot returns histograms sorted by labels.
+func (v *histogramVec) snapshot() []histogramSample {
+	var samples []histogramSample
+	v.values.Range(func(key, value interface{}) bool {
+		samples = append(samples, histogramSample{labels: key.(labels), value: value.(*histogram).snapshot()})
+		return true
+	})
+	sort.Slice(samples, func(i, j int) bool {
+		return lessLabels(samples[i].labels, samples[j].labels)
+	})
+	return samples
+}
+
+func lessLabels(a, b labels) bool {
+	for i := range a {
+		if a[i] != b[i] {
+			return a[i] < b[i]
+		}
+	}
+	return false
+}
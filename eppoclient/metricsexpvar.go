@@ -0,0 +1,102 @@
+package eppoclient
+
+import (
+	"expvar"
+	"strconv"
+)
+
+// ExpvarMetrics is a Metrics implementation that publishes metrics
+// with the standard expvar package, so they are served at
+// /debug/vars along with other expvar variables.
+type ExpvarMetrics struct {
+	*metricsCollector
+}
+
+var _ Metrics = (*ExpvarMetrics)(nil)
+
+// NewExpvarMetrics publishes metrics as an expvar variable with the
+// given name (e.g., "eppo"). Like expvar.Publish, it panics if the
+// name is already in use, so it should be called once per process.
+func NewExpvarMetrics(name string) *ExpvarMetrics {
+	m := &ExpvarMetrics{metricsCollector: newMetricsCollector()}
+	expvar.Publish(name, expvar.Func(m.snapshot))
+	return m
+}
+
+// snapshot returns all metrics as a JSON-serializable value.
+func (m *ExpvarMetrics) snapshot() interface{} {
+	// flag -> outcome (with error kind) -> count
+	evaluations := make(map[string]map[string]uint64)
+	for _, sample := range m.evaluations.snapshot() {
+		flag, outcome, errorKind := sample.labels[0], sample.labels[1], sample.labels[2]
+		if errorKind != "" {
+			outcome += ":" + errorKind
+		}
+		if evaluations[flag] == nil {
+			evaluations[flag] = make(map[string]uint64)
+		}
+		evaluations[flag][outcome] = sample.value
+	}
+
+	// flag -> bandit -> count
+	banditEvaluations := make(map[string]map[string]uint64)
+	for _, sample := range m.banditEvaluations.snapshot() {
+		flag, bandit := sample.labels[0], sample.labels[1]
+		if banditEvaluations[flag] == nil {
+			banditEvaluations[flag] = make(map[string]uint64)
+		}
+		banditEvaluations[flag][bandit] = sample.value
+	}
+
+	// resource -> status -> count
+	fetches := make(map[string]map[string]uint64)
+	for _, sample := range m.fetches.snapshot() {
+		resource, status := sample.labels[0], sample.labels[1]
+		if fetches[resource] == nil {
+			fetches[resource] = make(map[string]uint64)
+		}
+		fetches[resource][status] = sample.value
+	}
+
+	fetchDuration := make(map[string]interface{})
+	for _, sample := range m.fetchDuration.snapshot() {
+		fetchDuration[sample.labels[0]] = expvarHistogram(sample.value)
+	}
+
+	result := map[string]interface{}{
+		"evaluations":                          evaluations,
+		"evaluation_duration_seconds":          expvarHistogram(m.evaluationDuration.snapshot()),
+		"bandit_evaluations":                   banditEvaluations,
+		"bandit_evaluation_duration_seconds":   expvarHistogram(m.banditEvaluationDuration.snapshot()),
+		"configuration_fetches":                fetches,
+		"configuration_fetch_bytes":            expvarCounterMap(m.fetchBytes.snapshot()),
+		"configuration_fetch_duration_seconds": fetchDuration,
+		"configuration_updates":                m.configurationUpdates.Load(),
+		"logger_panics":                        expvarCounterMap(m.loggerPanics.snapshot()),
+	}
+	if age, ok := m.configurationAge(); ok {
+		result["configuration_age_seconds"] = age
+	}
+	return result
+}
+
+// expvarCounterMap converts samples with a single label into a map.
+func expvarCounterMap(samples []counterSample) map[string]uint64 {
+	result := make(map[string]uint64, len(samples))
+	for _, sample := range samples {
+		result[sample.labels[0]] = sample.value
+	}
+	return result
+}
+
+func expvarHistogram(h histogramSnapshot) map[string]interface{} {
+	buckets := make(map[string]uint64, len(h.buckets))
+	for i, bound := range h.buckets {
+		buckets[strconv.FormatFloat(bound, 'g', -1, 64)] = h.cumulativeCounts[i]
+	}
+	return map[string]interface{}{
+		"count":   h.count,
+		"sum":     h.sum,
+		"buckets": buckets,
+	}
+}
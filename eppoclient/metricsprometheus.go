@@ -0,0 +1,158 @@
+package eppoclient
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// PrometheusMetrics is a Metrics implementation that exposes metrics
+// in the Prometheus text exposition format. It implements
+// http.Handler, so it can be mounted on a metrics endpoint:
+//
+//	metrics := eppoclient.NewPrometheusMetrics()
+//	http.Handle("/metrics", metrics)
+//	client, err := eppoclient.InitClient(eppoclient.Config{
+//		SdkKey:  sdkKey,
+//		Metrics: metrics,
+//	})
+//
+// If you already use the Prometheus client library, mount the
+// handler on a separate path or implement Metrics on top of your
+// registry instead.
+type PrometheusMetrics struct {
+	*metricsCollector
+}
+
+var _ Metrics = (*PrometheusMetrics)(nil)
+var _ http.Handler = (*PrometheusMetrics)(nil)
+
+func NewPrometheusMetrics() *PrometheusMetrics {
+	return &PrometheusMetrics{metricsCollector: newMetricsCollector()}
+}
+
+func (m *PrometheusMetrics) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	_ = m.write(w)
+}
+
+// write writes all metrics in the Prometheus text exposition format.
+func (m *PrometheusMetrics) write(w io.Writer) error {
+	pw := &prometheusWriter{w: bufio.NewWriter(w)}
+
+	pw.counterVec("eppo_evaluations_total", "Flag evaluations by flag, outcome, and error kind.",
+		[]string{"flag", "outcome", "error_kind"}, m.evaluations.snapshot())
+	pw.histogram("eppo_evaluation_duration_seconds", "Flag evaluation latency.",
+		m.evaluationDuration.snapshot())
+
+	pw.counterVec("eppo_bandit_evaluations_total", "Bandit evaluations by flag and bandit.",
+		[]string{"flag", "bandit"}, m.banditEvaluations.snapshot())
+	pw.histogram("eppo_bandit_evaluation_duration_seconds", "Bandit evaluation latency.",
+		m.banditEvaluationDuration.snapshot())
+
+	pw.counterVec("eppo_configuration_fetches_total", "Requests to Eppo by resource and HTTP status code.",
+		[]string{"resource", "status"}, m.fetches.snapshot())
+	pw.counterVec("eppo_configuration_fetch_bytes_total", "Bytes received from Eppo by resource.",
+		[]string{"resource"}, m.fetchBytes.snapshot())
+	pw.histogramVec("eppo_configuration_fetch_duration_seconds", "Request latency by resource.",
+		[]string{"resource"}, m.fetchDuration.snapshot())
+
+	pw.counter("eppo_configuration_updates_total", "Configuration updates.", m.configurationUpdates.Load())
+	if age, ok := m.configurationAge(); ok {
+		pw.gauge("eppo_configuration_age_seconds", "Time since the active configuration was fetched.", age)
+	}
+
+	pw.counterVec("eppo_logger_panics_total", "Panics recovered in assignment loggers.",
+		[]string{"logger"}, m.loggerPanics.snapshot())
+
+	return pw.flush()
+}
+
+type prometheusWriter struct {
+	w *bufio.Writer
+}
+
+func (pw *prometheusWriter) header(name, help, typ string) {
+	fmt.Fprintf(pw.w, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, typ)
+}
+
+func (pw *prometheusWriter) counter(name, help string, value uint64) {
+	pw.header(name, help, "counter")
+	fmt.Fprintf(pw.w, "%s %d\n", name, value)
+}
+
+func (pw *prometheusWriter) gauge(name, help string, value float64) {
+	pw.header(name, help, "gauge")
+	fmt.Fprintf(pw.w, "%s %s\n", name, formatPrometheusFloat(value))
+}
+
+func (pw *prometheusWriter) counterVec(name, help string, labelNames []string, samples []counterSample) {
+	pw.header(name, help, "counter")
+	for _, sample := range samples {
+		fmt.Fprintf(pw.w, "%s%s %d\n", name, formatPrometheusLabels(labelNames, sample.labels, ""), sample.value)
+	}
+}
+
+func (pw *prometheusWriter) histogram(name, help string, value histogramSnapshot) {
+	pw.header(name, help, "histogram")
+	pw.histogramSample(name, nil, labels{}, value)
+}
+
+func (pw *prometheusWriter) histogramVec(name, help string, labelNames []string, samples []histogramSample) {
+	pw.header(name, help, "histogram")
+	for _, sample := range samples {
+		pw.histogramSample(name, labelNames, sample.labels, sample.value)
+	}
+}
+
+func (pw *prometheusWriter) histogramSample(name string, labelNames []string, l labels, value histogramSnapshot) {
+	for i, bound := range value.buckets {
+		fmt.Fprintf(pw.w, "%s_bucket%s %d\n", name, formatPrometheusLabels(labelNames, l, formatPrometheusFloat(bound)), value.cumulativeCounts[i])
+	}
+	fmt.Fprintf(pw.w, "%s_bucket%s %d\n", name, formatPrometheusLabels(labelNames, l, "+Inf"), value.count)
+	fmt.Fprintf(pw.w, "%s_sum%s %s\n", name, formatPrometheusLabels(labelNames, l, ""), formatPrometheusFloat(value.sum))
+	fmt.Fprintf(pw.w, "%s_count%s %d\n", name, formatPrometheusLabels(labelNames, l, ""), value.count)
+}
+
+func (pw *prometheusWriter) flush() error {
+	return pw.w.Flush()
+}
+
+var prometheusLabelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// formatPrometheusLabels formats label set. `le` is added as the
+// last label if not empty.
+func formatPrometheusLabels(names []string, values labels, le string) string {
+	if len(names) == 0 && le == "" {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteByte('{')
+	for i, name := range names {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(name)
+		b.WriteString(`="`)
+		b.WriteString(prometheusLabelEscaper.Replace(values[i]))
+		b.WriteByte('"')
+	}
+	if le != "" {
+		if len(names) > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(`le="`)
+		b.WriteString(le)
+		b.WriteByte('"')
+	}
+	b.WriteByte('}')
+	return b.String()
+}
+
+func formatPrometheusFloat(value float64) string {
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
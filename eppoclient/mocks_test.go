@@ -39,3 +39,17 @@ type mockNonBanditLogger struct {
 func (ml *mockNonBanditLogger) LogAssignment(event AssignmentEvent) {
 	ml.MethodCalled("LogAssignment", event)
 }
+
+// `mockFlushingLogger` implements `LoggerFlusher`.
+type mockFlushingLogger struct {
+	mock.Mock
+}
+
+func (ml *mockFlushingLogger) LogAssignment(event AssignmentEvent) {
+	ml.MethodCalled("LogAssignment", event)
+}
+
+func (ml *mockFlushingLogger) Flush(ctx context.Context) error {
+	args := ml.MethodCalled("Flush", ctx)
+	return args.Error(0)
+}
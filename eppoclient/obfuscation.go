@@ -0,0 +1,144 @@
+package eppoclient
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"strconv"
+)
+
+// Format of configuration served to client-side SDKs. Flag keys are
+// MD5-hashed, while attribute names, condition values, variation
+// values, and allocation keys are base64-encoded.
+//
+// Flag keys are never decoded: lookups hash the requested key
+// instead. Other fields are decoded once when configuration is
+// loaded.
+const configFormatClient = "CLIENT"
+
+func (response *configResponse) isObfuscated() bool {
+	return response.Format == configFormatClient
+}
+
+// getObfuscatedFlagConfiguration looks up flag by the hash of `key`.
+func (c configuration) getObfuscatedFlagConfiguration(key string) (*flagConfiguration, error) {
+	flag, ok := c.flags.Flags[hashWithSalt(key, "")]
+	if !ok {
+		return nil, ErrFlagConfigurationNotFound
+	}
+
+	// Flag key is hashed in the configuration. Return a shallow
+	// copy with the plaintext key, so that it is reported in
+	// assignment events and evaluation details.
+	plaintext := *flag
+	plaintext.Key = key
+	return &plaintext, nil
+}
+
+// deobfuscate decodes base64-encoded fields in place. Fields that
+// fail to decode are left as is, so they never match during
+// evaluation.
+func (flag *flagConfiguration) deobfuscate() {
+	if flag.deobfuscated {
+		return
+	}
+	flag.deobfuscated = true
+
+	variations := make(map[string]variation, len(flag.Variations))
+	for key, v := range flag.Variations {
+		if value, ok := decodeObfuscatedVariationValue(flag.VariationType, v.Value); ok {
+			v.Value = value
+		}
+		variations[key] = v
+	}
+	flag.Variations = variations
+
+	for i := range flag.Allocations {
+		allocation := &flag.Allocations[i]
+		if key, ok := decodeBase64String(allocation.Key); ok {
+			allocation.Key = key
+		}
+		for j := range allocation.Rules {
+			for k := range allocation.Rules[j].Conditions {
+				allocation.Rules[j].Conditions[k].deobfuscate()
+			}
+		}
+	}
+}
+
+func (c *condition) deobfuscate() {
+	if attribute, ok := decodeBase64String(c.Attribute); ok {
+		c.Attribute = attribute
+	}
+
+	switch value := c.Value.(type) {
+	case string:
+		decoded, ok := decodeBase64String(value)
+		if !ok {
+			return
+		}
+		switch c.Operator {
+		case "IS_NULL":
+			if b, err := strconv.ParseBool(decoded); err == nil {
+				c.Value = b
+			}
+			return
+		case "GTE", "GT", "LTE", "LT":
+			// Numeric values are numbers in plaintext
+			// configuration, while semantic versions are
+			// strings.
+			if f, err := strconv.ParseFloat(decoded, 64); err == nil {
+				c.Value = f
+				return
+			}
+		}
+		c.Value = decoded
+	case []interface{}:
+		values := make([]interface{}, 0, len(value))
+		for _, v := range value {
+			s, ok := v.(string)
+			if !ok {
+				return
+			}
+			decoded, ok := decodeBase64String(s)
+			if !ok {
+				return
+			}
+			values = append(values, decoded)
+		}
+		c.Value = values
+	}
+}
+
+// decodeObfuscatedVariationValue converts obfuscated variation value
+// (JSON string with base64-encoded string representation of the
+// value) into plaintext UFC representation.
+func decodeObfuscatedVariationValue(ty variationType, value json.RawMessage) (json.RawMessage, bool) {
+	var encoded string
+	if err := json.Unmarshal(value, &encoded); err != nil {
+		return nil, false
+	}
+	decoded, ok := decodeBase64String(encoded)
+	if !ok {
+		return nil, false
+	}
+
+	switch ty {
+	case stringVariation, jsonVariation:
+		// Both are JSON strings in plaintext configuration.
+		result, err := json.Marshal(decoded)
+		if err != nil {
+			return nil, false
+		}
+		return result, true
+	default:
+		return json.RawMessage(decoded), true
+	}
+}
+
+func decodeBase64String(s string) (string, bool) {
+	decoded, err := base64.StdEncoding.DecodeString(s)
+	if err != nil {
+		return "", false
+	}
+	return string(decoded), true
+}
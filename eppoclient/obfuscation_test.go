@@ -0,0 +1,235 @@
+package eppoclient
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+const obfuscationTestFlags = `{
+  "flags": {
+    "string-flag": {
+      "key": "string-flag",
+      "enabled": true,
+      "variationType": "STRING",
+      "totalShards": 10000,
+      "variations": {"red": {"key": "red", "value": "red"}, "blue": {"key": "blue", "value": "blue"}},
+      "allocations": [
+        {
+          "key": "targeted",
+          "rules": [
+            {"conditions": [{"attribute": "country", "operator": "ONE_OF", "value": ["US", "CA"]}]},
+            {"conditions": [
+              {"attribute": "age", "operator": "GTE", "value": 18},
+              {"attribute": "email", "operator": "MATCHES", "value": "@example\\.com$"}
+            ]},
+            {"conditions": [{"attribute": "version", "operator": "GT", "value": "1.2.0"}]}
+          ],
+          "splits": [{"variationKey": "red", "shards": []}]
+        },
+        {
+          "key": "no-name",
+          "rules": [{"conditions": [{"attribute": "name", "operator": "IS_NULL", "value": true}]}],
+          "splits": [{"variationKey": "blue", "shards": []}]
+        }
+      ]
+    },
+    "json-flag": {
+      "key": "json-flag",
+      "enabled": true,
+      "variationType": "JSON",
+      "totalShards": 10000,
+      "variations": {"on": {"key": "on", "value": "{\"a\": 1}"}},
+      "allocations": [{"key": "everyone", "splits": [{"variationKey": "on", "shards": []}]}]
+    },
+    "integer-flag": {
+      "key": "integer-flag",
+      "enabled": true,
+      "variationType": "INTEGER",
+      "totalShards": 10000,
+      "variations": {"one": {"key": "one", "value": 1}},
+      "allocations": [{"key": "everyone", "splits": [{"variationKey": "one", "shards": []}]}]
+    }
+  }
+}`
+
+// obfuscateConfiguration converts plaintext UFC configuration into
+// the obfuscated format.
+func obfuscateConfiguration(t *testing.T, flagsJSON string) []byte {
+	var response configResponse
+	assert.NoError(t, json.Unmarshal([]byte(flagsJSON), &response))
+
+	flags := make(map[string]*flagConfiguration, len(response.Flags))
+	for key, flag := range response.Flags {
+		flag.Key = hashWithSalt(key, "")
+
+		for variationKey, v := range flag.Variations {
+			var plaintext string
+			if flag.VariationType == stringVariation || flag.VariationType == jsonVariation {
+				assert.NoError(t, json.Unmarshal(v.Value, &plaintext))
+			} else {
+				plaintext = string(v.Value)
+			}
+			v.Value, _ = json.Marshal(encodeBase64(plaintext))
+			flag.Variations[variationKey] = v
+		}
+
+		for i := range flag.Allocations {
+			allocation := &flag.Allocations[i]
+			allocation.Key = encodeBase64(allocation.Key)
+			for j := range allocation.Rules {
+				for k := range allocation.Rules[j].Conditions {
+					condition := &allocation.Rules[j].Conditions[k]
+					condition.Attribute = encodeBase64(condition.Attribute)
+					switch value := condition.Value.(type) {
+					case []interface{}:
+						for n := range value {
+							value[n] = encodeBase64(value[n].(string))
+						}
+					case bool:
+						condition.Value = encodeBase64(strconv.FormatBool(value))
+					default:
+						condition.Value = encodeBase64(formatVariationValue(value))
+					}
+				}
+			}
+		}
+
+		flags[flag.Key] = flag
+	}
+	response.Flags = flags
+	response.Format = configFormatClient
+
+	result, err := json.Marshal(response)
+	assert.NoError(t, err)
+	return result
+}
+
+func Test_obfuscatedConfiguration(t *testing.T) {
+	obfuscated := obfuscateConfiguration(t, obfuscationTestFlags)
+	assert.NotContains(t, string(obfuscated), "string-flag")
+	assert.NotContains(t, string(obfuscated), "country")
+	assert.NotContains(t, string(obfuscated), "targeted")
+
+	plaintextClient, err := InitClientFromConfiguration(Config{ApplicationLogger: applicationLogger}, []byte(obfuscationTestFlags), nil)
+	assert.NoError(t, err)
+	obfuscatedClient, err := InitClientFromConfiguration(Config{ApplicationLogger: applicationLogger}, obfuscated, nil)
+	assert.NoError(t, err)
+
+	subjects := []Attributes{
+		{"country": "US", "name": "Alice"},
+		{"country": "FR", "age": 21, "email": "bob@example.com"},
+		{"country": "FR", "age": 16, "email": "carol@example.com"},
+		{"version": "1.10.0"},
+		{"name": "Dave"},
+	}
+	for i, attributes := range subjects {
+		subjectKey := "subject-" + strconv.Itoa(i)
+
+		expected, expectedDetails, expectedErr := plaintextClient.GetStringAssignmentDetails("string-flag", subjectKey, attributes, "default")
+		actual, actualDetails, actualErr := obfuscatedClient.GetStringAssignmentDetails("string-flag", subjectKey, attributes, "default")
+		assert.Equal(t, expected, actual, "subject %v", attributes)
+		assert.Equal(t, expectedErr, actualErr)
+		assert.Equal(t, expectedDetails, actualDetails)
+	}
+
+	value, err := obfuscatedClient.GetJSONAssignment("json-flag", "subject", Attributes{}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"a": 1.0}, value)
+
+	integer, err := obfuscatedClient.GetIntegerAssignment("integer-flag", "subject", Attributes{}, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), integer)
+
+	_, err = obfuscatedClient.GetStringAssignment("missing-flag", "subject", Attributes{}, "default")
+	assert.ErrorIs(t, err, ErrFlagConfigurationNotFound)
+}
+
+func Test_obfuscatedConfiguration_logsPlaintextKeys(t *testing.T) {
+	logger := new(mockLogger)
+	logger.Mock.On("LogAssignment", mock.Anything).Return()
+
+	client, err := InitClientFromConfiguration(Config{
+		AssignmentLogger:  logger,
+		ApplicationLogger: applicationLogger,
+	}, obfuscateConfiguration(t, obfuscationTestFlags), nil)
+	assert.NoError(t, err)
+
+	value, err := client.GetStringAssignment("string-flag", "subject", Attributes{"country": "CA"}, "default")
+	assert.NoError(t, err)
+	assert.Equal(t, "red", value)
+
+	event := logger.Calls[0].Arguments[0].(AssignmentEvent)
+	assert.Equal(t, "string-flag", event.FeatureFlag)
+	assert.Equal(t, "targeted", event.Allocation)
+	assert.Equal(t, "string-flag-targeted", event.Experiment)
+}
+
+func Test_flagConfiguration_deobfuscateOnce(t *testing.T) {
+	config, err := newConfigurationFromJSON(obfuscateConfiguration(t, obfuscationTestFlags), nil)
+	assert.NoError(t, err)
+
+	config.precompute()
+	config.precompute()
+
+	flag, err := config.getFlagConfiguration("string-flag")
+	assert.NoError(t, err)
+	assert.Equal(t, "targeted", flag.Allocations[0].Key)
+	assert.Equal(t, "red", flag.ParsedVariations["red"])
+}
+
+func Test_obfuscatedConfiguration_enumerationRejected(t *testing.T) {
+	client, err := InitClientFromConfiguration(Config{ApplicationLogger: applicationLogger}, obfuscateConfiguration(t, obfuscationTestFlags), nil)
+	assert.NoError(t, err)
+
+	_, err = client.GetAllAssignments(context.Background(), "subject", Attributes{})
+	assert.ErrorIs(t, err, ErrObfuscatedConfiguration)
+
+	_, err = client.GetPrecomputedConfiguration("subject", Attributes{}, map[string]map[string]ContextAttributes{
+		"string-flag": {"action": {}},
+	})
+	assert.ErrorIs(t, err, ErrObfuscatedConfiguration)
+}
+
+func Test_obfuscatedConfiguration_changeNotReported(t *testing.T) {
+	core, logs := observer.New(zap.ErrorLevel)
+	client, err := InitClientFromConfiguration(Config{ApplicationLogger: NewZapLogger(zap.New(core))}, []byte(`{"flags": {}}`), nil)
+	assert.NoError(t, err)
+	defer client.configurationStore.unsubscribeAll()
+
+	changes := make(chan ConfigurationChange, 10)
+	client.OnConfigurationChange(func(change ConfigurationChange) {
+		changes <- change
+	})
+
+	assert.NoError(t, client.SetConfiguration(obfuscateConfiguration(t, obfuscationTestFlags), nil))
+	assert.Eventually(t, func() bool {
+		return logs.FilterMessageSnippet("obfuscated configuration").Len() == 1
+	}, time.Second, 10*time.Millisecond)
+	select {
+	case change := <-changes:
+		t.Fatalf("obfuscated configuration reported: %v", change)
+	default:
+	}
+
+	// Plaintext configuration is compared with nothing, as keys of
+	// the obfuscated one are not known.
+	assert.NoError(t, client.SetConfiguration([]byte(initClientTestFlags), nil))
+	change := receiveChange(t, changes)
+	assert.Equal(t, []FlagChange{{FlagKey: "flag", Added: true}}, change.Flags)
+}
+
+func Test_obfuscatedConfiguration_diagnosticsOmitFlagKeys(t *testing.T) {
+	diagnostics := ValidateConfiguration(obfuscatedValidationTestFlags(), nil)
+	if assert.Len(t, diagnostics, 1) {
+		assert.Equal(t, "", diagnostics[0].FlagKey)
+		assert.Equal(t, "flags."+hashWithSalt("flag", "")+".allocations[0].rules[0].conditions[0]", diagnostics[0].Path)
+	}
+}
@@ -0,0 +1,209 @@
+package eppoclient
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+const defaultOverridesReloadInterval = time.Second
+
+// Allocation key reported in assignment events of overridden
+// assignments.
+const localOverrideAllocation = "local-override"
+
+// overridesFile is the format of the local overrides file:
+//
+//	flags:
+//	  new-checkout:
+//	    - subjects: [alice, bob]
+//	      value: true
+//	    - attributes: {country: US}
+//	      value: false
+//	  banner-text:
+//	    - value: "maintenance"
+//
+// Rules of each flag are checked in order and the first matching one
+// wins. If no rule matches, the flag is evaluated as usual.
+type overridesFile struct {
+	Flags map[string][]overrideRule `yaml:"flags"`
+}
+
+type overrideRule struct {
+	// Subject keys the rule applies to. Matches any subject if
+	// empty.
+	Subjects []string `yaml:"subjects"`
+	// Attributes the subject must have. Numeric values are
+	// compared as numbers.
+	Attributes map[string]interface{} `yaml:"attributes"`
+	// Forced value of the flag.
+	Value interface{} `yaml:"value"`
+}
+
+func (r overrideRule) matches(subjectKey string, subjectAttributes Attributes) bool {
+	if len(r.Subjects) > 0 {
+		found := false
+		for _, s := range r.Subjects {
+			if s == subjectKey {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	for name, expected := range r.Attributes {
+		actual, ok := subjectAttributes[name]
+		if !ok || !overrideAttributeEquals(expected, actual) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func overrideAttributeEquals(expected, actual interface{}) bool {
+	expectedNumber, err := toFloat64(expected)
+	if _, isString := expected.(string); err == nil && !isString {
+		actualNumber, err := toFloat64(actual)
+		return err == nil && expectedNumber == actualNumber
+	}
+	return reflect.DeepEqual(expected, actual)
+}
+
+func parseOverrides(data []byte) (map[string][]overrideRule, error) {
+	var file overridesFile
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, err
+	}
+	for flagKey, rules := range file.Flags {
+		for i, rule := range rules {
+			if rule.Value == nil {
+				return nil, fmt.Errorf("flag %q: override %d has no value", flagKey, i)
+			}
+		}
+	}
+	return file.Flags, nil
+}
+
+// overrideStore holds local flag overrides loaded from a file and
+// reloads them when the file changes.
+type overrideStore struct {
+	path              string
+	logAssignments    bool
+	applicationLogger ApplicationLogger
+	poller            *poller
+
+	overrides atomic.Pointer[map[string][]overrideRule]
+
+	// `mu` serializes reloads and guards fields below.
+	mu sync.Mutex
+	// Modification time and size of the loaded file. Zero if the
+	// file does not exist.
+	modTime time.Time
+	size    int64
+}
+
+// newOverrideStore loads overrides from `path`. A missing file is
+// treated as no overrides, but a malformed one is an error.
+func newOverrideStore(path string, reloadInterval time.Duration, logAssignments bool, applicationLogger ApplicationLogger) (*overrideStore, error) {
+	s := &overrideStore{
+		path:              path,
+		logAssignments:    logAssignments,
+		applicationLogger: applicationLogger,
+	}
+	if err := s.reload(); err != nil {
+		return nil, err
+	}
+	s.poller = newPoller(pollingPolicy{interval: reloadInterval}, s.reload, applicationLogger)
+	return s, nil
+}
+
+// reload reads the file if it has changed since the last load. If
+// the file is malformed, previously loaded overrides are kept.
+func (s *overrideStore) reload() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	info, err := os.Stat(s.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		s.overrides.Store(&map[string][]overrideRule{})
+		s.modTime, s.size = time.Time{}, 0
+		return nil
+	}
+	if err != nil {
+		s.applicationLogger.Errorf("failed to read local overrides: %v", err)
+		return err
+	}
+	if info.ModTime().Equal(s.modTime) && info.Size() == s.size {
+		return nil
+	}
+
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		s.applicationLogger.Errorf("failed to read local overrides: %v", err)
+		return err
+	}
+	overrides, err := parseOverrides(data)
+	if err != nil {
+		err = fmt.Errorf("failed to parse local overrides %s: %w", s.path, err)
+		s.applicationLogger.Error(err)
+		return err
+	}
+
+	s.overrides.Store(&overrides)
+	s.modTime, s.size = info.ModTime(), info.Size()
+	s.applicationLogger.Infof("loaded local overrides for %d flags", len(overrides))
+	return nil
+}
+
+// evaluate returns the overridden evaluation of the flag, if any rule
+// matches the subject.
+func (s *overrideStore) evaluate(flagKey string, subjectKey string, subjectAttributes Attributes, variationType variationType, details *EvaluationDetails) (flagEvaluation, bool) {
+	rules := (*s.overrides.Load())[flagKey]
+	for _, rule := range rules {
+		if !rule.matches(subjectKey, subjectAttributes) {
+			continue
+		}
+
+		value, err := variationType.overrideValue(rule.Value)
+		if err != nil {
+			s.applicationLogger.Warnf("ignoring local override of flag %v: %v", flagKey, err)
+			return flagEvaluation{}, false
+		}
+
+		if details != nil {
+			details.Reason = EvaluationReasonLocalOverride
+			details.SubjectAttributes = subjectAttributes
+			details.AllocationKey = localOverrideAllocation
+		}
+
+		evaluation := flagEvaluation{value: value}
+		if s.logAssignments {
+			evaluation.event = &AssignmentEvent{
+				FeatureFlag:       flagKey,
+				Allocation:        localOverrideAllocation,
+				Experiment:        flagKey + "-" + localOverrideAllocation,
+				Variation:         formatVariationValue(value),
+				Subject:           subjectKey,
+				SubjectAttributes: subjectAttributes,
+				Timestamp:         time.Now().UTC().Format(time.RFC3339),
+				MetaData: map[string]string{
+					"sdkLanguage": "go",
+					"sdkVersion":  __version__,
+				},
+			}
+		}
+		return evaluation, true
+	}
+	return flagEvaluation{}, false
+}
@@ -0,0 +1,138 @@
+package eppoclient
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+const overridesTestFile = `
+flags:
+  flag:
+    - subjects: [alice]
+      value: "alice-value"
+    - attributes: {country: US, age: 18}
+      value: "us-value"
+  number-flag:
+    - value: 42
+  json-flag:
+    - value: {"enabled": true}
+`
+
+func writeOverridesFile(t *testing.T, path, content string) {
+	err := os.WriteFile(path, []byte(content), 0o600)
+	assert.NoError(t, err)
+}
+
+func initOverridesTestClient(t *testing.T, path string, logger IAssignmentLogger, logOverridden bool) *EppoClient {
+	client, err := InitClientFromConfiguration(Config{
+		AssignmentLogger:         logger,
+		ApplicationLogger:        applicationLogger,
+		OverridesFile:            path,
+		OverridesReloadInterval:  10 * time.Millisecond,
+		LogOverriddenAssignments: logOverridden,
+	}, []byte(initClientTestFlags), nil)
+	assert.NoError(t, err)
+	t.Cleanup(func() { _ = client.Close(context.Background()) })
+	return client
+}
+
+func Test_Overrides(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "overrides.yaml")
+	writeOverridesFile(t, path, overridesTestFile)
+	logger := new(mockLogger)
+	logger.Mock.On("LogAssignment", mock.Anything)
+	client := initOverridesTestClient(t, path, logger, false)
+
+	value, details, err := client.GetStringAssignmentDetails("flag", "alice", Attributes{}, "default")
+	assert.NoError(t, err)
+	assert.Equal(t, "alice-value", value)
+	assert.Equal(t, EvaluationReasonLocalOverride, details.Reason)
+
+	value, err = client.GetStringAssignment("flag", "bob", Attributes{"country": "US", "age": 18.0}, "default")
+	assert.NoError(t, err)
+	assert.Equal(t, "us-value", value)
+
+	// Falls back to remote configuration if no rule matches.
+	value, details, err = client.GetStringAssignmentDetails("flag", "bob", Attributes{"country": "CA"}, "default")
+	assert.NoError(t, err)
+	assert.Equal(t, "on", value)
+	assert.Equal(t, EvaluationReasonMatch, details.Reason)
+
+	// Overridden flags do not need to exist remotely.
+	number, err := client.GetIntegerAssignment("number-flag", "bob", Attributes{}, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(42), number)
+
+	jsonValue, err := client.GetJSONAssignment("json-flag", "bob", Attributes{}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"enabled": true}, jsonValue)
+
+	// Overrides of a mismatching type are ignored.
+	_, err = client.GetBoolAssignment("number-flag", "bob", Attributes{}, false)
+	assert.ErrorIs(t, err, ErrFlagConfigurationNotFound)
+
+	// Only the remote assignment is logged.
+	logger.AssertNumberOfCalls(t, "LogAssignment", 1)
+}
+
+func Test_Overrides_logAssignments(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "overrides.yaml")
+	writeOverridesFile(t, path, overridesTestFile)
+	logger := new(mockLogger)
+	logger.Mock.On("LogAssignment", mock.Anything)
+	client := initOverridesTestClient(t, path, logger, true)
+
+	_, _ = client.GetStringAssignment("flag", "alice", Attributes{}, "default")
+
+	logger.AssertNumberOfCalls(t, "LogAssignment", 1)
+	event := logger.Calls[0].Arguments.Get(0).(AssignmentEvent)
+	assert.Equal(t, "flag", event.FeatureFlag)
+	assert.Equal(t, "local-override", event.Allocation)
+	assert.Equal(t, "alice-value", event.Variation)
+	assert.Equal(t, "alice", event.Subject)
+}
+
+func Test_Overrides_reload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "overrides.json")
+	client := initOverridesTestClient(t, path, nil, false)
+
+	// Missing file means no overrides.
+	value, _ := client.GetStringAssignment("flag", "alice", Attributes{}, "default")
+	assert.Equal(t, "on", value)
+
+	writeOverridesFile(t, path, `{"flags": {"flag": [{"value": "forced"}]}}`)
+	assert.Eventually(t, func() bool {
+		value, _ := client.GetStringAssignment("flag", "alice", Attributes{}, "default")
+		return value == "forced"
+	}, time.Second, 5*time.Millisecond)
+
+	// Malformed file keeps previous overrides.
+	writeOverridesFile(t, path, `{"flags": `)
+	time.Sleep(50 * time.Millisecond)
+	value, _ = client.GetStringAssignment("flag", "alice", Attributes{}, "default")
+	assert.Equal(t, "forced", value)
+
+	assert.NoError(t, os.Remove(path))
+	assert.Eventually(t, func() bool {
+		value, _ := client.GetStringAssignment("flag", "alice", Attributes{}, "default")
+		return value == "on"
+	}, time.Second, 5*time.Millisecond)
+}
+
+func Test_Overrides_invalidFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "overrides.yaml")
+
+	writeOverridesFile(t, path, "flags: [")
+	_, err := InitClientFromConfiguration(Config{ApplicationLogger: applicationLogger, OverridesFile: path}, []byte(initClientTestFlags), nil)
+	assert.Error(t, err)
+
+	writeOverridesFile(t, path, "flags:\n  flag:\n    - subjects: [alice]\n")
+	_, err = InitClientFromConfiguration(Config{ApplicationLogger: applicationLogger, OverridesFile: path}, []byte(initClientTestFlags), nil)
+	assert.ErrorContains(t, err, "has no value")
+}
@@ -1,52 +1,178 @@
 package eppoclient
 
 import (
+	"context"
+	"math/rand"
+	"sync"
 	"time"
 )
 
+const defaultPollerMaxBackoff = 5 * time.Minute
+
+// pollingPolicy computes delays between polls.
+type pollingPolicy struct {
+	// Base interval between successful polls.
+	interval time.Duration
+	// Maximum random duration subtracted from `interval`, so that
+	// multiple clients do not poll in lockstep. Zero disables
+	// jitter.
+	jitter time.Duration
+	// Maximum delay after consecutive failures. If zero, failures
+	// do not increase delay.
+	maxBackoff time.Duration
+}
+
+// nextDelay returns delay before the next poll given the number of
+// consecutive failures so far.
+func (p pollingPolicy) nextDelay(consecutiveFailures int) time.Duration {
+	if consecutiveFailures == 0 || p.maxBackoff <= p.interval {
+		delay := p.interval
+		if p.jitter > 0 {
+			delay -= time.Duration(rand.Int63n(int64(p.jitter) + 1))
+		}
+		return delay
+	}
+
+	// Exponential backoff: interval * 2^failures, capped at
+	// maxBackoff.
+	delay := p.interval
+	for i := 0; i < consecutiveFailures && delay < p.maxBackoff; i++ {
+		delay *= 2
+	}
+	if delay > p.maxBackoff {
+		delay = p.maxBackoff
+	}
+
+	// Randomize in [delay/2, delay] to spread retries of multiple
+	// clients.
+	half := delay / 2
+	return half + time.Duration(rand.Int63n(int64(delay-half)+1))
+}
+
 type poller struct {
-	interval          time.Duration
-	callback          func()
-	isStopped         bool `default:"false"`
+	policy            pollingPolicy
+	callback          func() error
 	applicationLogger ApplicationLogger
+
+	// `mu` guards fields below.
+	mu sync.Mutex
+	// Closed to signal the polling goroutine to stop. nil if the
+	// poller is not running.
+	stopCh chan struct{}
+	// Closed when the polling goroutine exits. nil if the poller
+	// has never been started.
+	doneCh chan struct{}
 }
 
-func newPoller(interval time.Duration, callback func(), applicationLogger ApplicationLogger) *poller {
+// newPoller creates a poller invoking `callback` according to
+// `policy`. `callback` returns an error to indicate that the poll has
+// failed and the poller should back off.
+func newPoller(policy pollingPolicy, callback func() error, applicationLogger ApplicationLogger) *poller {
 	var pl = &poller{}
 
-	pl.interval = interval
+	pl.policy = policy
 	pl.callback = callback
 	pl.applicationLogger = applicationLogger
 
 	return pl
 }
 
+// Start starts polling in a background goroutine. Does nothing if the
+// poller is already running.
 func (p *poller) Start() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.stopCh != nil {
+		return
+	}
+
 	if p.applicationLogger != nil {
 		p.applicationLogger.Info("Poller start")
 	}
-	go p.poll()
+	p.stopCh = make(chan struct{})
+	p.doneCh = make(chan struct{})
+	go p.poll(p.stopCh, p.doneCh)
 }
 
-func (p *poller) poll() {
+func (p *poller) poll(stopCh <-chan struct{}, doneCh chan<- struct{}) {
+	defer close(doneCh)
 	defer func() {
 		if err := recover(); err != nil {
-			p.Stop()
+			if p.applicationLogger != nil {
+				p.applicationLogger.Errorf("poller stopped due to panic: %v", err)
+			}
+			p.mu.Lock()
+			if p.stopCh == stopCh {
+				p.stopCh = nil
+			}
+			p.mu.Unlock()
 		}
 	}()
 
+	consecutiveFailures := 0
 	for {
-		if p.isStopped {
-			break
+		select {
+		case <-stopCh:
+			return
+		default:
+		}
+
+		if err := p.callback(); err != nil {
+			consecutiveFailures++
+		} else {
+			consecutiveFailures = 0
+		}
+
+		timer := time.NewTimer(p.policy.nextDelay(consecutiveFailures))
+		select {
+		case <-stopCh:
+			timer.Stop()
+			return
+		case <-timer.C:
 		}
-		p.callback()
-		time.Sleep(p.interval)
 	}
 }
 
+// Stop signals the polling goroutine to stop without waiting for an
+// in-flight poll to complete.
 func (p *poller) Stop() {
-	if p.applicationLogger != nil {
-		p.applicationLogger.Info("Poller stopped")
+	p.stop()
+}
+
+// StopAndWait stops the poller and waits until an in-flight poll
+// completes or `ctx` is done.
+func (p *poller) StopAndWait(ctx context.Context) error {
+	doneCh := p.stop()
+	if doneCh == nil {
+		return nil
+	}
+
+	select {
+	case <-doneCh:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// stop signals the polling goroutine to stop. Returns a channel that
+// is closed when the goroutine exits, or nil if the poller has never
+// been started.
+func (p *poller) stop() <-chan struct{} {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.stopCh != nil {
+		if p.applicationLogger != nil {
+			p.applicationLogger.Info("Poller stopped")
+		}
+		close(p.stopCh)
+		p.stopCh = nil
+	}
+
+	if p.doneCh == nil {
+		return nil
 	}
-	p.isStopped = true
+	return p.doneCh
 }
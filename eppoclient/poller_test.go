@@ -1,6 +1,9 @@
 package eppoclient
 
 import (
+	"context"
+	"errors"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -12,15 +15,16 @@ type CallbackMock struct {
 	mock.Mock
 }
 
-func (m *CallbackMock) CallbackFn() {
+func (m *CallbackMock) CallbackFn() error {
 	m.Called()
+	return nil
 }
 
 func Test_PollerPoll_InvokesCallbackUntilStoped(t *testing.T) {
 	callbackMock := CallbackMock{}
 	callbackMock.On("CallbackFn").Return()
 
-	var poller = newPoller(1*time.Second, callbackMock.CallbackFn, applicationLogger)
+	var poller = newPoller(pollingPolicy{interval: 1 * time.Second}, callbackMock.CallbackFn, applicationLogger)
 	poller.Start()
 	time.Sleep(5*time.Second + 500*time.Millisecond) // half second buffer to allow polling thread to execute
 	poller.Stop()
@@ -29,19 +33,19 @@ func Test_PollerPoll_InvokesCallbackUntilStoped(t *testing.T) {
 }
 
 func Test_PollerPoll_StopsOnError(t *testing.T) {
-	callCount := 0
-	expected := 3
+	var callCount atomic.Int32
+	expected := int32(3)
 
-	var poller = newPoller(1*time.Second, func() {
-		callCount++
-		if callCount == 3 {
+	var poller = newPoller(pollingPolicy{interval: 1 * time.Second}, func() error {
+		if callCount.Add(1) == 3 {
 			panic("some_error")
 		}
+		return nil
 	}, applicationLogger)
 	poller.Start()
 
 	time.Sleep(5 * time.Second)
-	assert.Equal(t, expected, callCount)
+	assert.Equal(t, expected, callCount.Load())
 }
 
 func Test_PollerPoll_ManualStop(t *testing.T) {
@@ -50,7 +54,7 @@ func Test_PollerPoll_ManualStop(t *testing.T) {
 	callbackMock := CallbackMock{}
 	callbackMock.On("CallbackFn").Return()
 
-	var poller = newPoller(1*time.Second, callbackMock.CallbackFn, applicationLogger)
+	var poller = newPoller(pollingPolicy{interval: 1 * time.Second}, callbackMock.CallbackFn, applicationLogger)
 	poller.Start()
 
 	time.Sleep(2500 * time.Millisecond)
@@ -60,3 +64,102 @@ func Test_PollerPoll_ManualStop(t *testing.T) {
 	time.Sleep(2 * time.Second)
 	callbackMock.AssertNumberOfCalls(t, "CallbackFn", expected)
 }
+
+func Test_PollerPoll_BacksOffOnFailure(t *testing.T) {
+	var calls []time.Time
+	var poller = newPoller(pollingPolicy{interval: 100 * time.Millisecond, maxBackoff: 10 * time.Second}, func() error {
+		calls = append(calls, time.Now())
+		return errors.New("fetch failed")
+	}, applicationLogger)
+	poller.Start()
+
+	time.Sleep(1 * time.Second)
+	assert.NoError(t, poller.StopAndWait(context.Background()))
+
+	// Without backoff, the poller would have been called 10 times.
+	// With backoff, delays are at least 100ms, 200ms, 400ms.
+	assert.LessOrEqual(t, len(calls), 4)
+	assert.GreaterOrEqual(t, len(calls), 2)
+}
+
+func Test_PollerPoll_StopIsPrompt(t *testing.T) {
+	var poller = newPoller(pollingPolicy{interval: 1 * time.Hour}, func() error {
+		return nil
+	}, applicationLogger)
+	poller.Start()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+	assert.NoError(t, poller.StopAndWait(ctx))
+}
+
+func Test_PollerPoll_StopAndWaitWaitsForInFlightCallback(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	var finished atomic.Bool
+
+	var poller = newPoller(pollingPolicy{interval: 1 * time.Hour}, func() error {
+		close(started)
+		<-release
+		finished.Store(true)
+		return nil
+	}, applicationLogger)
+	poller.Start()
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, poller.StopAndWait(ctx), context.DeadlineExceeded)
+
+	close(release)
+	assert.NoError(t, poller.StopAndWait(context.Background()))
+	assert.True(t, finished.Load())
+}
+
+func Test_PollerPoll_Restart(t *testing.T) {
+	var callCount atomic.Int32
+	var poller = newPoller(pollingPolicy{interval: 1 * time.Hour}, func() error {
+		callCount.Add(1)
+		return nil
+	}, applicationLogger)
+
+	for i := 0; i < 3; i++ {
+		poller.Start()
+		// Starting a running poller is a no-op.
+		poller.Start()
+		assert.Eventually(t, func() bool { return callCount.Load() == int32(i+1) }, time.Second, 10*time.Millisecond)
+		assert.NoError(t, poller.StopAndWait(context.Background()))
+	}
+	assert.Equal(t, int32(3), callCount.Load())
+}
+
+func Test_pollingPolicy_nextDelay(t *testing.T) {
+	policy := pollingPolicy{
+		interval:   10 * time.Second,
+		jitter:     1 * time.Second,
+		maxBackoff: 60 * time.Second,
+	}
+
+	for i := 0; i < 100; i++ {
+		delay := policy.nextDelay(0)
+		assert.GreaterOrEqual(t, delay, 9*time.Second)
+		assert.LessOrEqual(t, delay, 10*time.Second)
+
+		delay = policy.nextDelay(1)
+		assert.GreaterOrEqual(t, delay, 10*time.Second)
+		assert.LessOrEqual(t, delay, 20*time.Second)
+
+		delay = policy.nextDelay(2)
+		assert.GreaterOrEqual(t, delay, 20*time.Second)
+		assert.LessOrEqual(t, delay, 40*time.Second)
+
+		// capped at maxBackoff
+		delay = policy.nextDelay(10)
+		assert.GreaterOrEqual(t, delay, 30*time.Second)
+		assert.LessOrEqual(t, delay, 60*time.Second)
+	}
+
+	noJitter := pollingPolicy{interval: 10 * time.Second}
+	assert.Equal(t, 10*time.Second, noJitter.nextDelay(0))
+	assert.Equal(t, 10*time.Second, noJitter.nextDelay(5))
+}
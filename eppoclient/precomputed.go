@@ -0,0 +1,259 @@
+package eppoclient
+
+import (
+	"crypto/md5"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// Wire format consumed by Eppo's client-side precomputed SDKs.
+type precomputedConfigurationWire struct {
+	Version     int                          `json:"version"`
+	Precomputed precomputedConfigurationData `json:"precomputed"`
+}
+
+type precomputedConfigurationData struct {
+	SubjectKey        string                       `json:"subjectKey"`
+	SubjectAttributes precomputedSubjectAttributes `json:"subjectAttributes"`
+	FetchedAt         string                       `json:"fetchedAt"`
+	// JSON-encoded precomputedConfigurationResponse.
+	Response string `json:"response"`
+}
+
+type precomputedSubjectAttributes struct {
+	Numeric     map[string]float64 `json:"numericAttributes"`
+	Categorical map[string]string  `json:"categoricalAttributes"`
+}
+
+type precomputedConfigurationResponse struct {
+	Format     string `json:"format"`
+	Obfuscated bool   `json:"obfuscated"`
+	CreatedAt  string `json:"createdAt"`
+	// Salt used to hash flag keys.
+	Salt    string                       `json:"salt"`
+	Flags   map[string]precomputedFlag   `json:"flags"`
+	Bandits map[string]precomputedBandit `json:"bandits"`
+}
+
+// All string fields except VariationType are base64-encoded.
+type precomputedFlag struct {
+	AllocationKey  string            `json:"allocationKey"`
+	VariationKey   string            `json:"variationKey"`
+	VariationType  VariationType     `json:"variationType"`
+	VariationValue string            `json:"variationValue"`
+	ExtraLogging   map[string]string `json:"extraLogging"`
+	DoLog          bool              `json:"doLog"`
+}
+
+// All string fields are base64-encoded.
+type precomputedBandit struct {
+	BanditKey                   string            `json:"banditKey"`
+	Action                      string            `json:"action"`
+	ModelVersion                string            `json:"modelVersion"`
+	ActionProbability           float64           `json:"actionProbability"`
+	OptimalityGap               float64           `json:"optimalityGap"`
+	ActionNumericAttributes     map[string]string `json:"actionNumericAttributes"`
+	ActionCategoricalAttributes map[string]string `json:"actionCategoricalAttributes"`
+}
+
+// GetPrecomputedConfiguration evaluates all flags for the subject and
+// returns a serialized precomputed configuration that can be passed to
+// Eppo's client-side precomputed SDKs (e.g., for server-side rendering).
+//
+// Flag keys are hashed with a random salt and all values are
+// base64-encoded, so the payload does not expose plaintext flag
+// configuration.
+//
+// `banditActions` maps flag keys to actions available to the subject.
+// Bandits are evaluated for flags that have actions and whose assigned
+// variation is a bandit.
+//
+// Assignments are not logged. Client SDKs are expected to log them
+// on exposure using the allocation and extra logging data included in
+// the payload.
+//
+// Returns ErrObfuscatedConfiguration if the configuration is
+// obfuscated, as flag keys must be hashed with the payload's salt.
+func (ec *EppoClient) GetPrecomputedConfiguration(
+	subjectKey string,
+	subjectAttributes Attributes,
+	banditActions map[string]map[string]ContextAttributes,
+) ([]byte, error) {
+	if ec.closed.Load() {
+		return nil, ErrClientClosed
+	}
+	if subjectKey == "" {
+		return nil, fmt.Errorf("no subject key provided")
+	}
+
+	salt, err := generateSalt()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC().Format(time.RFC3339)
+	config := ec.configurationStore.getConfiguration()
+	response, err := ec.precomputeConfiguration(config, subjectKey, subjectAttributes, banditActions, salt)
+	if err != nil {
+		return nil, err
+	}
+	response.CreatedAt = now
+
+	responseJSON, err := json.Marshal(response)
+	if err != nil {
+		return nil, err
+	}
+
+	contextAttributes := InferContextAttributes(subjectAttributes)
+	return json.Marshal(precomputedConfigurationWire{
+		Version: 1,
+		Precomputed: precomputedConfigurationData{
+			SubjectKey: subjectKey,
+			SubjectAttributes: precomputedSubjectAttributes{
+				Numeric:     contextAttributes.Numeric,
+				Categorical: contextAttributes.Categorical,
+			},
+			FetchedAt: now,
+			Response:  string(responseJSON),
+		},
+	})
+}
+
+// precomputeConfiguration evaluates all enabled flags by their
+// plaintext keys, which are hashed with `salt` and used to look up
+// actions in `banditActions`.
+func (ec *EppoClient) precomputeConfiguration(
+	config configuration,
+	subjectKey string,
+	subjectAttributes Attributes,
+	banditActions map[string]map[string]ContextAttributes,
+	salt string,
+) (precomputedConfigurationResponse, error) {
+	flagKeys, err := config.flagKeys()
+	if err != nil {
+		return precomputedConfigurationResponse{}, err
+	}
+
+	response := precomputedConfigurationResponse{
+		Format:     "PRECOMPUTED",
+		Obfuscated: true,
+		Salt:       salt,
+		Flags:      make(map[string]precomputedFlag),
+		Bandits:    make(map[string]precomputedBandit),
+	}
+
+	// Augment attributes once instead of once per flag.
+	augmentedSubjectAttributes := augmentWithSubjectKey(subjectAttributes, subjectKey)
+	for _, flagKey := range flagKeys {
+		flag, err := config.getFlagConfiguration(flagKey)
+		if err != nil || !flag.Enabled {
+			continue
+		}
+		evaluation, err := flag.evalAugmented(subjectKey, subjectAttributes, augmentedSubjectAttributes, ec.applicationLogger, nil)
+		if err != nil {
+			ec.applicationLogger.Debug("failed to evaluate flag ", flagKey, ": ", err)
+			continue
+		}
+		hashedFlagKey := hashWithSalt(flagKey, salt)
+
+		response.Flags[hashedFlagKey] = precomputedFlag{
+			AllocationKey:  encodeBase64(evaluation.allocation.Key),
+			VariationKey:   encodeBase64(evaluation.split.VariationKey),
+			VariationType:  flag.VariationType.toPublic(),
+			VariationValue: encodeBase64(formatVariationValue(evaluation.value)),
+			ExtraLogging:   encodeBase64Map(evaluation.split.ExtraLogging),
+			DoLog:          evaluation.allocation.DoLog == nil || *evaluation.allocation.DoLog,
+		}
+
+		actions := banditActions[flagKey]
+		if len(actions) == 0 {
+			continue
+		}
+		variation, ok := evaluation.value.(string)
+		if !ok {
+			continue
+		}
+		banditVariation, ok := config.getBanditVariant(flagKey, variation)
+		if !ok {
+			continue
+		}
+		bandit, err := config.getBanditConfiguration(banditVariation.Key)
+		if err != nil {
+			continue
+		}
+
+		banditEvaluation := bandit.ModelData.evaluate(banditEvaluationContext{
+			flagKey:           flagKey,
+			subjectKey:        subjectKey,
+			subjectAttributes: InferContextAttributes(subjectAttributes),
+			actions:           actions,
+		})
+
+		numericAttributes := make(map[string]string, len(banditEvaluation.actionAttributes.Numeric))
+		for key, value := range banditEvaluation.actionAttributes.Numeric {
+			numericAttributes[encodeBase64(key)] = encodeBase64(formatVariationValue(value))
+		}
+
+		response.Bandits[hashedFlagKey] = precomputedBandit{
+			BanditKey:                   encodeBase64(bandit.BanditKey),
+			Action:                      encodeBase64(banditEvaluation.actionKey),
+			ModelVersion:                encodeBase64(bandit.ModelVersion),
+			ActionProbability:           banditEvaluation.actionWeight,
+			OptimalityGap:               banditEvaluation.optimalityGap,
+			ActionNumericAttributes:     numericAttributes,
+			ActionCategoricalAttributes: encodeBase64Map(banditEvaluation.actionAttributes.Categorical),
+		}
+	}
+
+	return response, nil
+}
+
+// Formats parsed variation value the same way client SDKs stringify
+// them before encoding.
+func formatVariationValue(value interface{}) string {
+	switch value := value.(type) {
+	case string:
+		return value
+	case bool:
+		return strconv.FormatBool(value)
+	case int64:
+		return strconv.FormatInt(value, 10)
+	case float64:
+		return strconv.FormatFloat(value, 'f', -1, 64)
+	case jsonVariationValue:
+		return string(value.Raw)
+	default:
+		return fmt.Sprintf("%v", value)
+	}
+}
+
+func hashWithSalt(value, salt string) string {
+	hash := md5.Sum([]byte(salt + value))
+	return hex.EncodeToString(hash[:])
+}
+
+func encodeBase64(value string) string {
+	return base64.StdEncoding.EncodeToString([]byte(value))
+}
+
+func encodeBase64Map(m map[string]string) map[string]string {
+	result := make(map[string]string, len(m))
+	for key, value := range m {
+		result[encodeBase64(key)] = encodeBase64(value)
+	}
+	return result
+}
+
+func generateSalt() (string, error) {
+	var salt [16]byte
+	_, err := rand.Read(salt[:])
+	if err != nil {
+		return "", fmt.Errorf("failed to generate salt: %w", err)
+	}
+	return hex.EncodeToString(salt[:]), nil
+}
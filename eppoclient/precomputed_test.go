@@ -0,0 +1,134 @@
+package eppoclient
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newPrecomputedTestConfiguration() configuration {
+	config := newAllAssignmentsTestConfiguration()
+	config.flags.Flags["bandit-flag"] = &flagConfiguration{
+		Key:           "bandit-flag",
+		Enabled:       true,
+		TotalShards:   10000,
+		VariationType: stringVariation,
+		Variations:    map[string]variation{"bandit": {Key: "bandit", Value: []byte(`"bandit"`)}},
+		Allocations: []allocation{{
+			Key:   "bandit-allocation",
+			DoLog: &[]bool{false}[0],
+			Splits: []split{{
+				VariationKey: "bandit",
+				ExtraLogging: map[string]string{"holdout": "none"},
+			}},
+		}},
+	}
+	config.flags.Bandits = map[string][]banditVariation{
+		"bandit": {{Key: "bandit", FlagKey: "bandit-flag", VariationKey: "bandit", VariationValue: "bandit"}},
+	}
+	config.bandits = banditResponse{
+		Bandits: map[string]banditConfiguration{
+			"bandit": {
+				BanditKey:    "bandit",
+				ModelVersion: "v123",
+				ModelData:    banditModelData{Coefficients: map[string]banditCoefficients{}},
+			},
+		},
+	}
+	return config
+}
+
+func decodeBase64(t *testing.T, s string) string {
+	b, err := base64.StdEncoding.DecodeString(s)
+	assert.NoError(t, err)
+	return string(b)
+}
+
+func Test_precomputeConfiguration(t *testing.T) {
+	logger := new(mockLogger)
+	client := newEppoClient(newConfigurationStoreWithConfig(newPrecomputedTestConfiguration()), nil, nil, logger, nil, applicationLogger)
+
+	actions := map[string]map[string]ContextAttributes{
+		"bandit-flag": {
+			"action1": {
+				Numeric:     map[string]float64{"price": 9.5},
+				Categorical: map[string]string{"color": "red"},
+			},
+		},
+	}
+	response, err := client.precomputeConfiguration(client.configurationStore.getConfiguration(), "subject", Attributes{}, actions, "salt")
+	assert.NoError(t, err)
+
+	assert.Equal(t, "PRECOMPUTED", response.Format)
+	assert.True(t, response.Obfuscated)
+	assert.Equal(t, "salt", response.Salt)
+	assert.Len(t, response.Flags, 3)
+	assert.NotContains(t, response.Flags, "bool-flag")
+
+	boolFlag := response.Flags[hashWithSalt("bool-flag", "salt")]
+	assert.Equal(t, VariationTypeBoolean, boolFlag.VariationType)
+	assert.Equal(t, "true", decodeBase64(t, boolFlag.VariationValue))
+	assert.Equal(t, "bool-allocation", decodeBase64(t, boolFlag.AllocationKey))
+	assert.Equal(t, "on", decodeBase64(t, boolFlag.VariationKey))
+	assert.True(t, boolFlag.DoLog)
+
+	jsonFlag := response.Flags[hashWithSalt("json-flag", "salt")]
+	assert.Equal(t, `{"a": 1}`, decodeBase64(t, jsonFlag.VariationValue))
+
+	banditFlag := response.Flags[hashWithSalt("bandit-flag", "salt")]
+	assert.False(t, banditFlag.DoLog)
+	assert.Equal(t, map[string]string{
+		encodeBase64("holdout"): encodeBase64("none"),
+	}, banditFlag.ExtraLogging)
+
+	assert.Len(t, response.Bandits, 1)
+	bandit := response.Bandits[hashWithSalt("bandit-flag", "salt")]
+	assert.Equal(t, "bandit", decodeBase64(t, bandit.BanditKey))
+	assert.Equal(t, "action1", decodeBase64(t, bandit.Action))
+	assert.Equal(t, "v123", decodeBase64(t, bandit.ModelVersion))
+	assert.Equal(t, 1.0, bandit.ActionProbability)
+	assert.Equal(t, map[string]string{encodeBase64("price"): encodeBase64("9.5")}, bandit.ActionNumericAttributes)
+	assert.Equal(t, map[string]string{encodeBase64("color"): encodeBase64("red")}, bandit.ActionCategoricalAttributes)
+
+	// precomputing does not log assignments
+	logger.AssertNotCalled(t, "LogAssignment", mock.Anything)
+	logger.AssertNotCalled(t, "LogBanditAction", mock.Anything)
+}
+
+func Test_precomputeConfiguration_obfuscated(t *testing.T) {
+	config, err := newConfigurationFromJSON(obfuscateConfiguration(t, obfuscationTestFlags), nil)
+	assert.NoError(t, err)
+	config.precompute()
+	client := newEppoClient(newConfigurationStoreWithConfig(config), nil, nil, nil, nil, applicationLogger)
+
+	// Flag keys would be hashed twice and bandit actions (keyed by
+	// plaintext flag keys) would never be found.
+	_, err = client.precomputeConfiguration(config, "subject", Attributes{}, map[string]map[string]ContextAttributes{
+		"string-flag": {"action": {}},
+	}, "salt")
+	assert.ErrorIs(t, err, ErrObfuscatedConfiguration)
+}
+
+func Test_GetPrecomputedConfiguration(t *testing.T) {
+	client := newEppoClient(newConfigurationStoreWithConfig(newPrecomputedTestConfiguration()), nil, nil, nil, nil, applicationLogger)
+
+	payload, err := client.GetPrecomputedConfiguration("subject", Attributes{"age": 30, "country": "US"}, nil)
+	assert.NoError(t, err)
+
+	var wire precomputedConfigurationWire
+	assert.NoError(t, json.Unmarshal(payload, &wire))
+	assert.Equal(t, 1, wire.Version)
+	assert.Equal(t, "subject", wire.Precomputed.SubjectKey)
+	assert.Equal(t, map[string]float64{"age": 30}, wire.Precomputed.SubjectAttributes.Numeric)
+	assert.Equal(t, map[string]string{"country": "US"}, wire.Precomputed.SubjectAttributes.Categorical)
+
+	var response precomputedConfigurationResponse
+	assert.NoError(t, json.Unmarshal([]byte(wire.Precomputed.Response), &response))
+	assert.NotEmpty(t, response.Salt)
+	assert.NotEmpty(t, response.CreatedAt)
+	assert.Contains(t, response.Flags, hashWithSalt("json-flag", response.Salt))
+	assert.Empty(t, response.Bandits)
+}
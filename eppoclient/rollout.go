@@ -0,0 +1,83 @@
+package eppoclient
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// RolledOutFlag is a flag that assigns the same variation to every
+// subject. See FindRolledOutFlags.
+type RolledOutFlag struct {
+	FlagKey      string `json:"flagKey"`
+	VariationKey string `json:"variationKey"`
+	// Variation value as found in configuration.
+	Value interface{} `json:"value"`
+}
+
+// FindRolledOutFlags returns enabled flags (as found in a
+// configuration served at CONFIG_ENDPOINT) that assign the same
+// variation to every subject, sorted by key. Code using such flags
+// can usually be simplified to the variation's value.
+//
+// A flag is rolled out if an allocation without rules or end time
+// assigns every subject, and every allocation that may be evaluated
+// before it serves the same variation. Allocations that have already
+// ended are ignored. Keys of obfuscated configurations are hashed.
+func FindRolledOutFlags(flagsJSON []byte) ([]RolledOutFlag, error) {
+	config, err := newConfigurationFromJSON(flagsJSON, nil)
+	if err != nil {
+		return nil, err
+	}
+	config.precompute()
+
+	now := time.Now()
+	var result []RolledOutFlag
+	for _, key := range sortedKeys(config.flags.Flags) {
+		flag := config.flags.Flags[key]
+		variationKey, ok := flag.rolledOutVariation(now)
+		if !ok {
+			continue
+		}
+		var value interface{}
+		if err := json.Unmarshal(flag.Variations[variationKey].Value, &value); err != nil {
+			value = string(flag.Variations[variationKey].Value)
+		}
+		result = append(result, RolledOutFlag{FlagKey: key, VariationKey: variationKey, Value: value})
+	}
+	return result, nil
+}
+
+// rolledOutVariation returns the key of the variation the flag
+// assigns to every subject from `now` on.
+func (flag *flagConfiguration) rolledOutVariation(now time.Time) (string, bool) {
+	if !flag.Enabled {
+		return "", false
+	}
+
+	variationKey := ""
+	for _, a := range flag.Allocations {
+		if !a.EndAt.IsZero() && now.After(a.EndAt) {
+			continue
+		}
+		coverage, ok := a.splitCoverage(flag.TotalShards)
+		for i, s := range a.Splits {
+			if ok && coverage.assigned[i] == 0 {
+				// The split never assigns anyone.
+				continue
+			}
+			if variationKey != "" && s.VariationKey != variationKey {
+				return "", false
+			}
+			variationKey = s.VariationKey
+		}
+
+		if ok && coverage.gap == 0 && a.alwaysMatchesTargeting(now) {
+			if _, exists := flag.Variations[variationKey]; !exists {
+				return "", false
+			}
+			return variationKey, true
+		}
+	}
+	// Some subjects get the default value.
+	return "", false
+}
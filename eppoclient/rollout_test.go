@@ -0,0 +1,83 @@
+package eppoclient
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_FindRolledOutFlags(t *testing.T) {
+	flags := diffTestFlags(10000, diffTestStaticFlag+strings.Replace(diffTestStaticFlag, `"static"`, `"disabled"`, 2))
+	flags = []byte(strings.Replace(string(flags), `"key": "disabled",
+      "enabled": true`, `"key": "disabled",
+      "enabled": false`, 1))
+
+	rolledOut, err := FindRolledOutFlags(flags)
+	assert.NoError(t, err)
+	assert.Equal(t, []RolledOutFlag{
+		{FlagKey: "rollout", VariationKey: "on", Value: true},
+		{FlagKey: "static", VariationKey: "a", Value: "a"},
+	}, rolledOut)
+
+	rolledOut, err = FindRolledOutFlags(diffTestFlags(9999, ""))
+	assert.NoError(t, err)
+	assert.Empty(t, rolledOut)
+}
+
+func Test_flagConfiguration_rolledOutVariation(t *testing.T) {
+	now := time.Now()
+	serve := func(key, variationKey string) allocation {
+		return allocation{Key: key, Splits: []split{{VariationKey: variationKey}}}
+	}
+	variations := map[string]variation{"on": {Key: "on"}, "off": {Key: "off"}}
+
+	tests := map[string]struct {
+		allocations []allocation
+		variation   string
+	}{
+		"single allocation": {
+			allocations: []allocation{serve("all", "on")},
+			variation:   "on",
+		},
+		"targeted allocation of another variation": {
+			allocations: []allocation{
+				{Key: "internal", Rules: []rule{{Conditions: []condition{{Attribute: "a", Operator: "IS_NULL", Value: false}}}}, Splits: []split{{VariationKey: "off"}}},
+				serve("all", "on"),
+			},
+		},
+		"ended allocation": {
+			allocations: []allocation{
+				{Key: "past", EndAt: now.Add(-time.Hour), Splits: []split{{VariationKey: "off"}}},
+				serve("all", "on"),
+			},
+			variation: "on",
+		},
+		"allocation ending later": {
+			allocations: []allocation{
+				{Key: "temporary", EndAt: now.Add(time.Hour), Splits: []split{{VariationKey: "on"}}},
+			},
+		},
+		"partial exposure": {
+			allocations: []allocation{
+				{Key: "half", Splits: []split{{VariationKey: "on", Shards: []shard{{Salt: "s", Ranges: []shardRange{{Start: 0, End: 50}}}}}}},
+			},
+		},
+		"shadowed allocations": {
+			allocations: []allocation{serve("all", "on"), serve("never", "off")},
+			variation:   "on",
+		},
+		"unknown variation": {
+			allocations: []allocation{serve("all", "unknown")},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			flag := flagConfiguration{Enabled: true, TotalShards: 100, Variations: variations, Allocations: tt.allocations}
+			variation, ok := flag.rolledOutVariation(now)
+			assert.Equal(t, tt.variation != "", ok)
+			assert.Equal(t, tt.variation, variation)
+		})
+	}
+}
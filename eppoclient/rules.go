@@ -3,7 +3,6 @@ package eppoclient
 import (
 	"errors"
 	"fmt"
-	"reflect"
 	"regexp"
 	"strconv"
 
@@ -37,14 +36,21 @@ func (condition condition) matches(subjectAttributes Attributes, applicationLogg
 	}
 
 	switch condition.Operator {
+	// Conditions with malformed values never match. Such
+	// configurations are rejected by validation, so this only
+	// guards against panics.
 	case "MATCHES":
-		return matches(subjectValue, condition.Value.(string))
+		pattern, ok := condition.Value.(string)
+		return ok && matches(subjectValue, pattern)
 	case "NOT_MATCHES":
-		return !matches(subjectValue, condition.Value.(string))
+		pattern, ok := condition.Value.(string)
+		return ok && !matches(subjectValue, pattern)
 	case "ONE_OF":
-		return isOneOf(subjectValue, convertToStringArray(condition.Value))
+		values, ok := convertToStringArray(condition.Value)
+		return ok && isOneOf(subjectValue, values)
 	case "NOT_ONE_OF":
-		return !isOneOf(subjectValue, convertToStringArray(condition.Value))
+		values, ok := convertToStringArray(condition.Value)
+		return ok && !isOneOf(subjectValue, values)
 	case "GTE", "GT", "LTE", "LT":
 		// Attempt to coerce the subject value to float64 and compare it
 		// against the condition value.
@@ -84,15 +90,25 @@ func (condition condition) matches(subjectAttributes Attributes, applicationLogg
 	}
 }
 
-func convertToStringArray(conditionValue interface{}) []string {
-	if reflect.TypeOf(conditionValue).Elem().Kind() == reflect.String {
-		return conditionValue.([]string)
-	}
-	conditionValueStrings := make([]string, len(conditionValue.([]interface{})))
-	for i, v := range conditionValue.([]interface{}) {
-		conditionValueStrings[i] = v.(string)
+// convertToStringArray converts ONE_OF and NOT_ONE_OF condition
+// values. Returns false if `conditionValue` is not a list of strings.
+func convertToStringArray(conditionValue interface{}) ([]string, bool) {
+	switch values := conditionValue.(type) {
+	case []string:
+		return values, true
+	case []interface{}:
+		conditionValueStrings := make([]string, len(values))
+		for i, v := range values {
+			s, ok := v.(string)
+			if !ok {
+				return nil, false
+			}
+			conditionValueStrings[i] = s
+		}
+		return conditionValueStrings, true
+	default:
+		return nil, false
 	}
-	return conditionValueStrings
 }
 
 func matches(subjectValue interface{}, conditionValue string) bool {
@@ -0,0 +1,111 @@
+package eppoclient
+
+import (
+	"context"
+	"errors"
+)
+
+// ClientState is the state of EppoClient.
+type ClientState string
+
+const (
+	// Client has no configuration yet.
+	ClientStateInitializing ClientState = "INITIALIZING"
+	// Client has configuration and serves assignments.
+	ClientStateReady ClientState = "READY"
+	// Eppo rejected the SDK key. Polling has been stopped, and
+	// the client keeps serving the last known configuration, if
+	// any.
+	ClientStateUnauthorized ClientState = "UNAUTHORIZED"
+	// Close has been called.
+	ClientStateClosed ClientState = "CLOSED"
+)
+
+// ClientStatus describes the state of EppoClient.
+type ClientStatus struct {
+	State ClientState
+	// Error of the last configuration fetch. nil if the last fetch
+	// has succeeded or no fetch has been made yet.
+	LastError error
+}
+
+// Status returns the current state of the client.
+func (ec *EppoClient) Status() ClientStatus {
+	var status ClientStatus
+	if ec.configRequestor != nil {
+		status.LastError = ec.configRequestor.lastError()
+	}
+
+	switch {
+	case ec.closed.Load():
+		status.State = ClientStateClosed
+	case errors.Is(status.LastError, ErrUnauthorized):
+		status.State = ClientStateUnauthorized
+	case ec.isInitialized():
+		status.State = ClientStateReady
+	default:
+		status.State = ClientStateInitializing
+	}
+
+	return status
+}
+
+// WaitForInitialization blocks until the client receives
+// configuration, the SDK key is rejected, or `ctx` is done.
+//
+// Returns nil if the client has been initialized, ErrUnauthorized if
+// the SDK key has been rejected, ErrClientClosed if the client has
+// been closed, or ctx.Err().
+func (ec *EppoClient) WaitForInitialization(ctx context.Context) error {
+	if ec.closed.Load() {
+		return ErrClientClosed
+	}
+
+	select {
+	case <-ec.unauthorizedCh:
+		return ErrUnauthorized
+	case <-ec.Initialized():
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (ec *EppoClient) isInitialized() bool {
+	select {
+	case <-ec.Initialized():
+		return true
+	default:
+		return false
+	}
+}
+
+// handleUnauthorized stops polling and streaming, as all further
+// requests would be rejected as well, and notifies the user.
+//
+// Called from polling and streaming goroutines, so it must not wait
+// for them.
+func (ec *EppoClient) handleUnauthorized() {
+	ec.applicationLogger.Error("SDK key has been rejected by Eppo, stopping configuration polling")
+
+	if ec.streamer != nil {
+		ec.streamer.Stop()
+	}
+	if ec.poller != nil {
+		ec.poller.Stop()
+	}
+
+	ec.unauthorizedOnce.Do(func() {
+		close(ec.unauthorizedCh)
+	})
+
+	if ec.onUnauthorized != nil {
+		// need to catch panics from hook and continue
+		defer func() {
+			if r := recover(); r != nil {
+				ec.applicationLogger.Errorf("panic occurred in OnUnauthorized hook: %v", r)
+			}
+		}()
+		ec.onUnauthorized(ErrUnauthorized)
+	}
+}
@@ -0,0 +1,122 @@
+package eppoclient
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Status_unauthorized(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	hookCalls := make(chan error, 10)
+	client, err := InitClient(Config{
+		BaseUrl:           server.URL,
+		SdkKey:            "revoked-sdk-key",
+		ApplicationLogger: applicationLogger,
+		PollerInterval:    50 * time.Millisecond,
+		OnUnauthorized: func(err error) {
+			hookCalls <- err
+			panic("hook panic is recovered")
+		},
+	})
+	assert.NoError(t, err)
+	defer client.Close(context.Background())
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	assert.ErrorIs(t, client.WaitForInitialization(ctx), ErrUnauthorized)
+	assert.ErrorIs(t, <-hookCalls, ErrUnauthorized)
+
+	status := client.Status()
+	assert.Equal(t, ClientStateUnauthorized, status.State)
+	assert.ErrorIs(t, status.LastError, ErrUnauthorized)
+
+	// Polling has stopped.
+	time.Sleep(300 * time.Millisecond)
+	assert.Equal(t, int32(1), requests.Load())
+	assert.False(t, isPolling(client.poller))
+}
+
+func Test_Status_streamingUnauthorized(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	hookCalls := make(chan error, 10)
+	client, err := InitClient(Config{
+		BaseUrl:           server.URL,
+		SdkKey:            "revoked-sdk-key",
+		ApplicationLogger: applicationLogger,
+		StreamingUrl:      server.URL + "/stream",
+		OnUnauthorized:    func(err error) { hookCalls <- err },
+	})
+	assert.NoError(t, err)
+	defer client.Close(context.Background())
+
+	assert.ErrorIs(t, <-hookCalls, ErrUnauthorized)
+	assert.Equal(t, ClientStateUnauthorized, client.Status().State)
+
+	// Client does not fall back to polling.
+	time.Sleep(100 * time.Millisecond)
+	assert.Equal(t, int32(1), requests.Load())
+	assert.False(t, isPolling(client.poller))
+}
+
+func Test_Status(t *testing.T) {
+	var fail atomic.Bool
+	fail.Store(true)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if fail.Load() {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		fmt.Fprint(w, initClientTestFlags)
+	}))
+	defer server.Close()
+
+	client, err := InitClient(Config{
+		BaseUrl:           server.URL,
+		SdkKey:            "sdk-key",
+		ApplicationLogger: applicationLogger,
+		PollerInterval:    50 * time.Millisecond,
+		PollerMaxBackoff:  -1,
+	})
+	assert.NoError(t, err)
+
+	assert.Eventually(t, func() bool { return client.Status().LastError != nil }, time.Second, 10*time.Millisecond)
+	assert.Equal(t, ClientStateInitializing, client.Status().State)
+
+	fail.Store(false)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	assert.NoError(t, client.WaitForInitialization(ctx))
+	assert.Eventually(t, func() bool { return client.Status() == ClientStatus{State: ClientStateReady} }, time.Second, 10*time.Millisecond)
+
+	assert.NoError(t, client.Close(context.Background()))
+	assert.Equal(t, ClientStateClosed, client.Status().State)
+	assert.ErrorIs(t, client.WaitForInitialization(ctx), ErrClientClosed)
+}
+
+func Test_WaitForInitialization_timeout(t *testing.T) {
+	client := newEppoClient(newConfigurationStore(), nil, nil, nil, nil, applicationLogger)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, client.WaitForInitialization(ctx), context.DeadlineExceeded)
+	assert.Equal(t, ClientStateInitializing, client.Status().State)
+}
@@ -0,0 +1,347 @@
+package eppoclient
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// Delay before the first reconnection attempt. Doubles with
+	// every consecutive failure up to streamingMaxReconnectDelay.
+	streamingReconnectDelay    = 1 * time.Second
+	streamingMaxReconnectDelay = 1 * time.Minute
+	// The stream is considered dead and reconnected if nothing
+	// (including heartbeat comments) is received for this long.
+	streamingReadTimeout = 2 * time.Minute
+)
+
+// Server-Sent Events types understood by the streamer.
+const (
+	// Data is a JSON object with "flags" (UFC configuration) and
+	// optional "bandits" (bandit models) fields. The configuration
+	// is applied immediately.
+	streamingEventConfiguration = "configuration"
+	// Configuration has changed on the server. Data is ignored and
+	// configuration is fetched immediately.
+	streamingEventChanged = "changed"
+)
+
+// `streamer` keeps a Server-Sent Events connection to receive
+// configuration updates as soon as they are published.
+//
+// While the stream is unavailable, `streamer` falls back to the
+// poller. Polling is stopped again once the stream is reconnected.
+type streamer struct {
+	url               string
+	httpClient        httpClient
+	requestor         *configurationRequestor
+	poller            *poller
+	reconnectPolicy   pollingPolicy
+	readTimeout       time.Duration
+	applicationLogger ApplicationLogger
+	// Called by fallBackToPolling before acquiring `mu`. nil
+	// outside of tests.
+	beforeFallback func()
+
+	// `mu` guards fields below.
+	mu sync.Mutex
+	// Cancels the streaming goroutine. nil if the streamer is not
+	// running.
+	cancel context.CancelFunc
+	// Closed when the streaming goroutine exits. nil if the
+	// streamer has never been started.
+	doneCh chan struct{}
+}
+
+func newStreamer(url string, httpClient httpClient, requestor *configurationRequestor, poller *poller, applicationLogger ApplicationLogger) *streamer {
+	// Streaming responses never complete, so the request timeout
+	// of the configured client must not apply. Dead connections are
+	// detected with `readTimeout` instead.
+	streamingClient := *httpClient.client
+	streamingClient.Timeout = 0
+	httpClient.client = &streamingClient
+
+	return &streamer{
+		url:        url,
+		httpClient: httpClient,
+		requestor:  requestor,
+		poller:     poller,
+		reconnectPolicy: pollingPolicy{
+			interval:   streamingReconnectDelay,
+			maxBackoff: streamingMaxReconnectDelay,
+		},
+		readTimeout:       streamingReadTimeout,
+		applicationLogger: applicationLogger,
+	}
+}
+
+// Start connects to the stream in a background goroutine. Does
+// nothing if the streamer is already running.
+func (s *streamer) Start() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.cancel != nil {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+	s.doneCh = make(chan struct{})
+	go s.run(ctx, s.doneCh)
+}
+
+// Stop closes the stream without waiting for the streaming goroutine
+// to exit. Fallback polling is not stopped.
+func (s *streamer) Stop() {
+	s.stop()
+}
+
+// StopAndWait closes the stream and waits until the streaming
+// goroutine exits or `ctx` is done. Fallback polling is not stopped.
+func (s *streamer) StopAndWait(ctx context.Context) error {
+	doneCh := s.stop()
+	if doneCh == nil {
+		return nil
+	}
+
+	select {
+	case <-doneCh:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// stop cancels the streaming goroutine. Returns a channel that is
+// closed when the goroutine exits, or nil if the streamer has never
+// been started.
+func (s *streamer) stop() <-chan struct{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.cancel != nil {
+		s.cancel()
+		s.cancel = nil
+	}
+	if s.doneCh == nil {
+		return nil
+	}
+	return s.doneCh
+}
+
+func (s *streamer) run(ctx context.Context, doneCh chan<- struct{}) {
+	defer close(doneCh)
+	defer func() {
+		if err := recover(); err != nil {
+			s.applicationLogger.Errorf("streamer stopped due to panic, falling back to polling: %v", err)
+			s.fallBackToPolling(ctx)
+		}
+	}()
+
+	consecutiveFailures := 0
+	for {
+		err := s.connect(ctx, func() {
+			consecutiveFailures = 0
+			s.applicationLogger.Info("Configuration stream connected")
+			s.poller.Stop()
+			// Catch up on updates missed while disconnected.
+			if err := s.requestor.FetchAndStoreConfigurations(); err != nil {
+				s.applicationLogger.Warnf("failed to fetch configuration after connecting to stream: %v", err)
+			}
+		})
+		if ctx.Err() != nil {
+			return
+		}
+		if errors.Is(err, ErrUnauthorized) {
+			// Polling would be rejected as well.
+			s.applicationLogger.Error("configuration stream rejected SDK key")
+			s.requestor.reportError(err)
+			return
+		}
+
+		consecutiveFailures++
+		s.applicationLogger.Warnf("configuration stream unavailable, falling back to polling: %v", err)
+		s.fallBackToPolling(ctx)
+
+		timer := time.NewTimer(s.reconnectPolicy.nextDelay(consecutiveFailures))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+	}
+}
+
+// fallBackToPolling starts the poller unless the streamer has been
+// stopped. The check is made under `mu`, so a concurrent stop
+// followed by stopping the poller (as in handleUnauthorized) cannot
+// be overtaken by a restart of polling.
+func (s *streamer) fallBackToPolling(ctx context.Context) {
+	if s.beforeFallback != nil {
+		s.beforeFallback()
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if ctx.Err() != nil {
+		return
+	}
+	s.poller.Start()
+}
+
+// connect opens the stream and handles events until the stream is
+// closed. `onConnected` is called once the stream is open.
+func (s *streamer) connect(ctx context.Context, onConnected func()) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	resp, err := s.httpClient.openStream(ctx, s.url)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	idleTimer := time.AfterFunc(s.readTimeout, cancel)
+	defer idleTimer.Stop()
+
+	onConnected()
+
+	events := newSSEReader(&idleTimeoutReader{
+		r:       resp.Body,
+		timer:   idleTimer,
+		timeout: s.readTimeout,
+	})
+	for {
+		event, err := events.next()
+		if errors.Is(err, io.EOF) {
+			return errors.New("stream closed by server")
+		}
+		if err != nil {
+			return err
+		}
+
+		s.handleEvent(event)
+	}
+}
+
+func (s *streamer) handleEvent(event sseEvent) {
+	switch event.event {
+	case streamingEventConfiguration:
+		var payload struct {
+			Flags   json.RawMessage `json:"flags"`
+			Bandits json.RawMessage `json:"bandits"`
+		}
+		err := json.Unmarshal([]byte(event.data), &payload)
+		if err == nil && len(payload.Flags) == 0 {
+			err = fmt.Errorf(`missing "flags" field`)
+		}
+		if err != nil {
+			s.applicationLogger.Errorf("invalid configuration event: %v", err)
+			return
+		}
+
+		var bandits []byte
+		if len(payload.Bandits) > 0 && string(payload.Bandits) != "null" {
+			bandits = payload.Bandits
+		}
+		err = s.requestor.StorePushedConfiguration(payload.Flags, bandits)
+		if err != nil {
+			s.applicationLogger.Errorf("failed to store pushed configuration: %v", err)
+		}
+	case streamingEventChanged:
+		// Errors are logged by the requestor.
+		_ = s.requestor.FetchAndStoreConfigurations()
+	default:
+		s.applicationLogger.Debug("ignoring unknown stream event: ", event.event)
+	}
+}
+
+// `idleTimeoutReader` resets `timer` every time data is read.
+type idleTimeoutReader struct {
+	r       io.Reader
+	timer   *time.Timer
+	timeout time.Duration
+}
+
+func (r *idleTimeoutReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	if n > 0 {
+		r.timer.Reset(r.timeout)
+	}
+	return n, err
+}
+
+type sseEvent struct {
+	// Event type. "message" if not specified by the server.
+	event string
+	data  string
+	id    string
+}
+
+// `sseReader` parses a stream in the Server-Sent Events format.
+//
+// See https://html.spec.whatwg.org/multipage/server-sent-events.html#event-stream-interpretation
+type sseReader struct {
+	r *bufio.Reader
+}
+
+func newSSEReader(r io.Reader) *sseReader {
+	return &sseReader{r: bufio.NewReader(r)}
+}
+
+// next returns the next event. Returns io.EOF when the stream ends.
+// An incomplete event at the end of the stream is discarded.
+func (r *sseReader) next() (sseEvent, error) {
+	var event sseEvent
+	var data []string
+
+	for {
+		line, err := r.r.ReadString('\n')
+		if err != nil {
+			return sseEvent{}, err
+		}
+		line = strings.TrimSuffix(strings.TrimSuffix(line, "\n"), "\r")
+
+		if line == "" {
+			// Blank line dispatches the event. Events without
+			// data are ignored.
+			if len(data) == 0 {
+				event = sseEvent{}
+				continue
+			}
+			if event.event == "" {
+				event.event = "message"
+			}
+			event.data = strings.Join(data, "\n")
+			return event, nil
+		}
+
+		if strings.HasPrefix(line, ":") {
+			// comment (usually heartbeat)
+			continue
+		}
+
+		field, value, _ := strings.Cut(line, ":")
+		value = strings.TrimPrefix(value, " ")
+		switch field {
+		case "event":
+			event.event = value
+		case "data":
+			data = append(data, value)
+		case "id":
+			event.id = value
+		default:
+			// ignore unknown fields (including "retry")
+		}
+	}
+}
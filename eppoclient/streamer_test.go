@@ -0,0 +1,240 @@
+package eppoclient
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_sseReader(t *testing.T) {
+	stream := strings.Join([]string{
+		": heartbeat",
+		"event: configuration",
+		"id: 1",
+		"data: line1",
+		"data:line2",
+		"",
+		"event: no-data",
+		"",
+		"data: default type\r",
+		"\r",
+		"event: incomplete",
+		"data: at the end",
+	}, "\n")
+	reader := newSSEReader(strings.NewReader(stream))
+
+	event, err := reader.next()
+	assert.NoError(t, err)
+	assert.Equal(t, sseEvent{event: "configuration", data: "line1\nline2", id: "1"}, event)
+
+	event, err = reader.next()
+	assert.NoError(t, err)
+	assert.Equal(t, sseEvent{event: "message", data: "default type"}, event)
+
+	_, err = reader.next()
+	assert.ErrorIs(t, err, io.EOF)
+}
+
+// `streamingTestServer` serves configuration at CONFIG_ENDPOINT and a
+// Server-Sent Events stream at "/stream".
+type streamingTestServer struct {
+	*httptest.Server
+
+	mu          sync.Mutex
+	flags       string
+	streamFails bool
+
+	configRequests atomic.Int32
+	streamRequests atomic.Int32
+	events         chan string
+}
+
+// The server is closed at the end of the test.
+func newStreamingTestServer(t *testing.T, flags string) *streamingTestServer {
+	server := &streamingTestServer{
+		flags:  flags,
+		events: make(chan string, 10),
+	}
+	server.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		server.mu.Lock()
+		flags, streamFails := server.flags, server.streamFails
+		server.mu.Unlock()
+
+		switch r.URL.Path {
+		case CONFIG_ENDPOINT:
+			server.configRequests.Add(1)
+			fmt.Fprint(w, flags)
+		case "/stream":
+			server.streamRequests.Add(1)
+			if streamFails {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			w.Header().Set("Content-Type", "text/event-stream")
+			w.WriteHeader(http.StatusOK)
+			w.(http.Flusher).Flush()
+			for {
+				select {
+				case <-r.Context().Done():
+					return
+				case event := <-server.events:
+					fmt.Fprint(w, event)
+					w.(http.Flusher).Flush()
+				}
+			}
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	// Cleanups run in reverse order, so the server is closed after
+	// clients are.
+	t.Cleanup(server.Close)
+	return server
+}
+
+func (s *streamingTestServer) setFlags(flags string) {
+	s.mu.Lock()
+	s.flags = flags
+	s.mu.Unlock()
+}
+
+func (s *streamingTestServer) setStreamFails(fails bool) {
+	s.mu.Lock()
+	s.streamFails = fails
+	s.mu.Unlock()
+}
+
+func initStreamingTestClient(t *testing.T, server *streamingTestServer) *EppoClient {
+	client, err := InitClient(Config{
+		BaseUrl:           server.URL,
+		SdkKey:            "sdk-key",
+		ApplicationLogger: applicationLogger,
+		PollerInterval:    1 * time.Hour,
+		StreamingUrl:      server.URL + "/stream",
+	})
+	assert.NoError(t, err)
+	t.Cleanup(func() {
+		assert.NoError(t, client.Close(context.Background()))
+	})
+	return client
+}
+
+func Test_streamer_pushedConfiguration(t *testing.T) {
+	server := newStreamingTestServer(t, `{"flags": {}}`)
+	client := initStreamingTestClient(t, server)
+
+	<-client.Initialized()
+	server.events <- "event: configuration\ndata: {\"flags\": " + strings.ReplaceAll(initClientTestFlags, "\n", "") + "}\n\n"
+
+	assert.Eventually(t, func() bool {
+		value, _ := client.GetStringAssignment("flag", "subject", Attributes{}, "default")
+		return value == "on"
+	}, time.Second, 10*time.Millisecond)
+	assert.Equal(t, int32(1), server.configRequests.Load())
+}
+
+func Test_streamer_changedNotification(t *testing.T) {
+	server := newStreamingTestServer(t, `{"flags": {}}`)
+	client := initStreamingTestClient(t, server)
+
+	<-client.Initialized()
+	server.setFlags(initClientTestFlags)
+	server.events <- "event: changed\ndata: {}\n\n"
+
+	assert.Eventually(t, func() bool {
+		value, _ := client.GetStringAssignment("flag", "subject", Attributes{}, "default")
+		return value == "on"
+	}, time.Second, 10*time.Millisecond)
+	assert.Equal(t, int32(2), server.configRequests.Load())
+}
+
+func Test_streamer_fallsBackToPolling(t *testing.T) {
+	server := newStreamingTestServer(t, initClientTestFlags)
+	server.setStreamFails(true)
+	client := initStreamingTestClient(t, server)
+
+	// Configuration is fetched by the poller while the stream is
+	// unavailable.
+	select {
+	case <-client.Initialized():
+	case <-time.After(time.Second):
+		t.Fatal("client is not initialized by fallback poller")
+	}
+	assert.True(t, isPolling(client.poller))
+
+	// Polling stops once the stream is reconnected.
+	server.setStreamFails(false)
+	assert.Eventually(t, func() bool {
+		return server.streamRequests.Load() >= 2 && !isPolling(client.poller)
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func Test_streamer_reconnectsOnIdleTimeout(t *testing.T) {
+	server := newStreamingTestServer(t, initClientTestFlags)
+
+	sdkParams := SDKParams{sdkKey: "sdk-key", sdkName: "go", sdkVersion: __version__}
+	httpClient := newHttpClient(server.URL, &http.Client{Timeout: REQUEST_TIMEOUT_SECONDS}, sdkParams)
+	requestor := newConfigurationRequestor(*httpClient, newConfigurationStore(), applicationLogger, nil)
+	poller := newPoller(pollingPolicy{interval: 1 * time.Hour}, requestor.FetchAndStoreConfigurations, applicationLogger)
+
+	streamer := newStreamer(server.URL+"/stream", *httpClient, requestor, poller, applicationLogger)
+	streamer.readTimeout = 50 * time.Millisecond
+	streamer.reconnectPolicy = pollingPolicy{interval: 10 * time.Millisecond}
+	streamer.Start()
+
+	assert.Eventually(t, func() bool {
+		return server.streamRequests.Load() >= 3
+	}, 2*time.Second, 10*time.Millisecond)
+
+	assert.NoError(t, streamer.StopAndWait(context.Background()))
+	assert.NoError(t, poller.StopAndWait(context.Background()))
+}
+
+func Test_streamer_stopRacesFallbackPolling(t *testing.T) {
+	server := newStreamingTestServer(t, initClientTestFlags)
+	server.setStreamFails(true)
+
+	sdkParams := SDKParams{sdkKey: "sdk-key", sdkName: "go", sdkVersion: __version__}
+	httpClient := newHttpClient(server.URL, &http.Client{Timeout: REQUEST_TIMEOUT_SECONDS}, sdkParams)
+	requestor := newConfigurationRequestor(*httpClient, newConfigurationStore(), applicationLogger, nil)
+	poller := newPoller(pollingPolicy{interval: 1 * time.Hour}, requestor.FetchAndStoreConfigurations, applicationLogger)
+	streamer := newStreamer(server.URL+"/stream", *httpClient, requestor, poller, applicationLogger)
+	// Pause the streaming goroutine right before it falls back to
+	// polling, and stop the streamer and the poller meanwhile.
+	fallback := make(chan struct{})
+	proceed := make(chan struct{})
+	var once sync.Once
+	streamer.beforeFallback = func() {
+		once.Do(func() {
+			close(fallback)
+			<-proceed
+		})
+	}
+
+	streamer.Start()
+	<-fallback
+
+	// Same order as handleUnauthorized.
+	streamer.Stop()
+	poller.Stop()
+	close(proceed)
+
+	assert.NoError(t, streamer.StopAndWait(context.Background()))
+	assert.False(t, isPolling(poller), "polling restarted after stop")
+	assert.NoError(t, poller.StopAndWait(context.Background()))
+}
+
+func isPolling(p *poller) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.stopCh != nil
+}
@@ -0,0 +1,79 @@
+package eppoclient
+
+import "context"
+
+// Tracer creates spans around SDK operations, so that flag
+// evaluations and configuration fetches show up in distributed
+// traces. The SDK does not depend on any tracing library; implement
+// Tracer as an adapter to the tracer of your choice (e.g.,
+// OpenTelemetry).
+//
+// Methods are called synchronously on the evaluation path, so
+// implementations must be fast and safe for concurrent use.
+type Tracer interface {
+	// StartSpan starts a span named `name` as a child of the span
+	// carried by `ctx` (if any) and returns a context carrying the
+	// new span.
+	//
+	// For evaluations, `ctx` is the context passed to the
+	// `*Context` getters. Configuration fetches are not tied to a
+	// caller and receive context.Background().
+	StartSpan(ctx context.Context, name string) (context.Context, Span)
+}
+
+// Span is a single traced operation started by Tracer.
+type Span interface {
+	// SetAttribute records an attribute on the span. See
+	// Attribute* constants for keys used by the SDK. Value is one
+	// of string, bool, int64, or float64.
+	SetAttribute(key string, value interface{})
+	// RecordError records an error that caused the operation to
+	// fail.
+	RecordError(err error)
+	// End completes the span. No methods are called on the span
+	// afterwards.
+	End()
+}
+
+// Names of spans started by the SDK.
+const (
+	SpanGetAssignment      = "eppo.get_assignment"
+	SpanGetBanditAction    = "eppo.get_bandit_action"
+	SpanFetchConfiguration = "eppo.fetch_configuration"
+)
+
+// Keys of span attributes recorded by the SDK.
+const (
+	AttributeFlagKey       = "eppo.flag_key"
+	AttributeVariationType = "eppo.variation_type"
+	AttributeVariationKey  = "eppo.variation_key"
+	AttributeAllocationKey = "eppo.allocation_key"
+	// One of EvaluationOutcome values.
+	AttributeOutcome = "eppo.outcome"
+	// One of EvaluationError* constants. Only set if evaluation
+	// has failed.
+	AttributeErrorKind    = "eppo.error_kind"
+	AttributeBanditKey    = "eppo.bandit_key"
+	AttributeBanditAction = "eppo.bandit_action"
+	// Whether fetched configuration differs from the active one.
+	AttributeConfigurationModified = "eppo.configuration_modified"
+)
+
+func (ec *EppoClient) traceEvaluation(span Span, flagKey string, variationType variationType, evaluation flagEvaluation, err error) {
+	span.SetAttribute(AttributeFlagKey, flagKey)
+	span.SetAttribute(AttributeVariationType, string(variationType.toPublic()))
+
+	outcome, errorKind := classifyEvaluationError(err)
+	span.SetAttribute(AttributeOutcome, string(outcome))
+	if outcome == EvaluationOutcomeError {
+		span.SetAttribute(AttributeErrorKind, errorKind)
+		span.RecordError(err)
+	}
+
+	if evaluation.allocation != nil {
+		span.SetAttribute(AttributeAllocationKey, evaluation.allocation.Key)
+	}
+	if evaluation.split != nil {
+		span.SetAttribute(AttributeVariationKey, evaluation.split.VariationKey)
+	}
+}
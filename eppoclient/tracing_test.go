@@ -0,0 +1,190 @@
+package eppoclient
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type testSpanKey struct{}
+
+type testSpan struct {
+	name       string
+	parent     *testSpan
+	attributes map[string]interface{}
+	errors     []error
+	ended      bool
+}
+
+func (s *testSpan) SetAttribute(key string, value interface{}) {
+	s.attributes[key] = value
+}
+
+func (s *testSpan) RecordError(err error) {
+	s.errors = append(s.errors, err)
+}
+
+func (s *testSpan) End() {
+	s.ended = true
+}
+
+type testTracer struct {
+	mu    sync.Mutex
+	spans []*testSpan
+}
+
+func (t *testTracer) StartSpan(ctx context.Context, name string) (context.Context, Span) {
+	parent, _ := ctx.Value(testSpanKey{}).(*testSpan)
+	span := &testSpan{name: name, parent: parent, attributes: map[string]interface{}{}}
+
+	t.mu.Lock()
+	t.spans = append(t.spans, span)
+	t.mu.Unlock()
+
+	return context.WithValue(ctx, testSpanKey{}, span), span
+}
+
+func (t *testTracer) getSpans() []*testSpan {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return append([]*testSpan(nil), t.spans...)
+}
+
+func Test_Tracing_evaluations(t *testing.T) {
+	tracer := &testTracer{}
+	logger := new(mockLoggerContext)
+	logger.Mock.On("LogAssignment", mock.Anything, mock.Anything)
+
+	client, err := InitClientFromConfiguration(Config{
+		AssignmentLoggerContext: logger,
+		ApplicationLogger:       applicationLogger,
+		Tracer:                  tracer,
+	}, []byte(initClientTestFlags), nil)
+	assert.NoError(t, err)
+
+	parent := &testSpan{name: "parent"}
+	ctx := context.WithValue(context.Background(), testSpanKey{}, parent)
+
+	value, err := client.GetStringAssignmentContext(ctx, "flag", "subject", Attributes{}, "default")
+	assert.NoError(t, err)
+	assert.Equal(t, "on", value)
+	_, _ = client.GetStringAssignmentContext(ctx, "missing-flag", "subject", Attributes{}, "default")
+
+	spans := tracer.getSpans()
+	assert.Len(t, spans, 2)
+
+	assert.Equal(t, SpanGetAssignment, spans[0].name)
+	assert.Same(t, parent, spans[0].parent)
+	assert.True(t, spans[0].ended)
+	assert.Equal(t, map[string]interface{}{
+		AttributeFlagKey:       "flag",
+		AttributeVariationType: "STRING",
+		AttributeOutcome:       "assigned",
+		AttributeAllocationKey: "allocation",
+		AttributeVariationKey:  "on",
+	}, spans[0].attributes)
+	assert.Empty(t, spans[0].errors)
+
+	assert.Equal(t, map[string]interface{}{
+		AttributeFlagKey:       "missing-flag",
+		AttributeVariationType: "STRING",
+		AttributeOutcome:       "error",
+		AttributeErrorKind:     "flag_not_found",
+	}, spans[1].attributes)
+	assert.Len(t, spans[1].errors, 1)
+	assert.True(t, spans[1].ended)
+
+	// Assignment logger receives the context carrying the span.
+	loggedCtx := logger.Calls[0].Arguments.Get(0).(context.Context)
+	assert.Same(t, spans[0], loggedCtx.Value(testSpanKey{}))
+}
+
+func Test_Tracing_defaultOutcome(t *testing.T) {
+	tracer := &testTracer{}
+	client := newEppoClient(newConfigurationStoreWithConfig(newAllAssignmentsTestConfiguration()), nil, nil, nil, nil, applicationLogger)
+	client.tracer = tracer
+
+	_, _ = client.GetStringAssignment("disabled-flag", "subject", Attributes{}, "default")
+
+	spans := tracer.getSpans()
+	assert.Len(t, spans, 1)
+	assert.Equal(t, "default", spans[0].attributes[AttributeOutcome])
+	assert.NotContains(t, spans[0].attributes, AttributeErrorKind)
+	assert.Empty(t, spans[0].errors)
+}
+
+func Test_Tracing_banditAction(t *testing.T) {
+	tracer := &testTracer{}
+	client := newEppoClient(newConfigurationStoreWithConfig(newPrecomputedTestConfiguration()), nil, nil, nil, nil, applicationLogger)
+	client.tracer = tracer
+
+	result := client.GetBanditAction("bandit-flag", "subject", ContextAttributes{}, map[string]ContextAttributes{"action": {}}, "default")
+	assert.NotNil(t, result.Action)
+
+	spans := tracer.getSpans()
+	assert.Len(t, spans, 2)
+
+	banditSpan, assignmentSpan := spans[0], spans[1]
+	assert.Equal(t, SpanGetBanditAction, banditSpan.name)
+	assert.Equal(t, map[string]interface{}{
+		AttributeFlagKey:      "bandit-flag",
+		AttributeBanditKey:    "bandit",
+		AttributeBanditAction: "action",
+	}, banditSpan.attributes)
+	assert.True(t, banditSpan.ended)
+
+	assert.Equal(t, SpanGetAssignment, assignmentSpan.name)
+	assert.Same(t, banditSpan, assignmentSpan.parent)
+}
+
+func Test_Tracing_fetchConfiguration(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("ETag", `"v1"`)
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		fmt.Fprint(w, initClientTestFlags)
+	}))
+	defer server.Close()
+
+	tracer := &testTracer{}
+	httpClient := newHttpClient(server.URL, &http.Client{}, SDKParams{sdkKey: "sdk-key"})
+	requestor := newConfigurationRequestor(*httpClient, newConfigurationStore(), applicationLogger, nil)
+	requestor.tracer = tracer
+
+	assert.NoError(t, requestor.FetchAndStoreConfigurations())
+	assert.NoError(t, requestor.FetchAndStoreConfigurations())
+
+	spans := tracer.getSpans()
+	assert.Len(t, spans, 2)
+	assert.Equal(t, SpanFetchConfiguration, spans[0].name)
+	assert.Equal(t, true, spans[0].attributes[AttributeConfigurationModified])
+	assert.Equal(t, false, spans[1].attributes[AttributeConfigurationModified])
+	assert.True(t, spans[1].ended)
+}
+
+func Test_Tracing_fetchConfigurationError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	tracer := &testTracer{}
+	httpClient := newHttpClient(server.URL, &http.Client{}, SDKParams{sdkKey: "sdk-key"})
+	requestor := newConfigurationRequestor(*httpClient, newConfigurationStore(), applicationLogger, nil)
+	requestor.tracer = tracer
+
+	assert.Error(t, requestor.FetchAndStoreConfigurations())
+
+	spans := tracer.getSpans()
+	assert.Len(t, spans, 1)
+	assert.Len(t, spans[0].errors, 1)
+	assert.NotContains(t, spans[0].attributes, AttributeConfigurationModified)
+}
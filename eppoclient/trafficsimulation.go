@@ -0,0 +1,426 @@
+package eppoclient
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// AttributeDistribution describes attributes of synthetic subjects.
+// Each attribute takes one of its values with probability
+// proportional to the value's weight. A nil value leaves the
+// attribute unset.
+type AttributeDistribution map[string][]WeightedValue
+
+// WeightedValue is a possible value of a synthetic attribute.
+type WeightedValue struct {
+	Value  interface{} `json:"value"`
+	Weight float64     `json:"weight"`
+}
+
+// SyntheticSubjects generates `n` subjects with attributes drawn from
+// `distribution`. The same seed always generates the same subjects.
+func SyntheticSubjects(distribution AttributeDistribution, n int, seed int64) ([]Subject, error) {
+	attributes := sortedKeys(distribution)
+	totals := make(map[string]float64, len(distribution))
+	for _, attribute := range attributes {
+		for _, v := range distribution[attribute] {
+			if v.Weight < 0 {
+				return nil, fmt.Errorf("attribute %q: negative weight %v", attribute, v.Weight)
+			}
+			totals[attribute] += v.Weight
+		}
+		if totals[attribute] <= 0 {
+			return nil, fmt.Errorf("attribute %q: weights must add up to a positive number", attribute)
+		}
+	}
+
+	rng := rand.New(rand.NewSource(seed))
+	subjects := make([]Subject, n)
+	for i := range subjects {
+		subject := Subject{Key: fmt.Sprintf("subject-%d-%d", seed, i), Attributes: Attributes{}}
+		for _, attribute := range attributes {
+			values := distribution[attribute]
+			r := rng.Float64() * totals[attribute]
+			choice := values[len(values)-1]
+			for _, v := range values {
+				if r < v.Weight {
+					choice = v
+					break
+				}
+				r -= v.Weight
+			}
+			if choice.Value != nil {
+				subject.Attributes[attribute] = choice.Value
+			}
+		}
+		subjects[i] = subject
+	}
+	return subjects, nil
+}
+
+// TrafficReport is the result of SimulateTraffic. Shares are
+// fractions of all simulated subjects.
+type TrafficReport struct {
+	FlagKey  string `json:"flagKey"`
+	Enabled  bool   `json:"enabled"`
+	Subjects int    `json:"subjects"`
+	// Allocations in evaluation order.
+	Allocations []AllocationTraffic `json:"allocations"`
+	// Variations assigned by any allocation, sorted by key.
+	Variations []VariationTraffic `json:"variations"`
+	// Subjects not assigned a variation, who get the default value.
+	Default TrafficShare `json:"default"`
+	// Problems found in the flag's allocations, independent of the
+	// simulated subjects.
+	Issues []CoverageIssue `json:"issues,omitempty"`
+}
+
+// TrafficShare is a number of subjects and their share of all
+// simulated subjects.
+type TrafficShare struct {
+	Subjects int     `json:"subjects"`
+	Share    float64 `json:"share"`
+}
+
+// AllocationTraffic describes simulated subjects reaching an
+// allocation.
+type AllocationTraffic struct {
+	Key string `json:"key"`
+	// Subjects matching the allocation's rules and schedule.
+	Matched TrafficShare `json:"matched"`
+	// Subjects assigned a variation by the allocation.
+	Assigned TrafficShare `json:"assigned"`
+	// Subjects matching rules and schedule but no split, who fall
+	// through to later allocations.
+	FellThrough TrafficShare `json:"fellThrough"`
+	// Assigned variations sorted by key.
+	Variations []VariationTraffic `json:"variations,omitempty"`
+}
+
+// VariationTraffic is the share of subjects assigned a variation.
+type VariationTraffic struct {
+	VariationKey string `json:"variationKey"`
+	TrafficShare
+}
+
+// CoverageIssueKind identifies a kind of CoverageIssue.
+type CoverageIssueKind string
+
+const (
+	// Some subjects matching an allocation's rules are not assigned
+	// by any split and fall through to later allocations or the
+	// default value. This is expected for allocations with partial
+	// traffic exposure.
+	CoverageShardGap CoverageIssueKind = "SHARD_GAP"
+	// Subjects match several splits of an allocation and the first
+	// of them wins.
+	CoverageShardOverlap CoverageIssueKind = "SHARD_OVERLAP"
+	// An earlier allocation assigns every subject, so the
+	// allocation is never evaluated.
+	CoverageUnreachableAllocation CoverageIssueKind = "UNREACHABLE_ALLOCATION"
+)
+
+// CoverageIssue is a problem in a flag's allocations found by
+// analyzing shard ranges.
+type CoverageIssue struct {
+	Kind          CoverageIssueKind `json:"kind"`
+	AllocationKey string            `json:"allocationKey"`
+	// Expected share of subjects matching the allocation's rules
+	// that are affected.
+	Share   float64 `json:"share"`
+	Message string  `json:"message"`
+}
+
+// maxCoverageCells limits the number of shard cells inspected per
+// allocation by flagCoverageIssues.
+const maxCoverageCells = 1 << 16
+
+// SimulateTraffic evaluates a flag (as found in a configuration
+// served at CONFIG_ENDPOINT) for `subjects` and reports how they are
+// distributed over allocations and variations. Use ReadSubjects for
+// a sample of real subjects or SyntheticSubjects to generate them.
+//
+// The report also lists shard gaps, overlapping splits, and
+// unreachable allocations, computed from shard ranges rather than
+// from the subjects.
+func SimulateTraffic(flagsJSON []byte, flagKey string, subjects []Subject) (TrafficReport, error) {
+	config, err := newConfigurationFromJSON(flagsJSON, nil)
+	if err != nil {
+		return TrafficReport{}, err
+	}
+	config.precompute()
+	flag, err := config.getFlagConfiguration(flagKey)
+	if err != nil {
+		return TrafficReport{}, fmt.Errorf("%s: %w", flagKey, err)
+	}
+
+	report := TrafficReport{
+		FlagKey:     flagKey,
+		Enabled:     flag.Enabled,
+		Subjects:    len(subjects),
+		Allocations: make([]AllocationTraffic, len(flag.Allocations)),
+		Issues:      flagCoverageIssues(flag, time.Now()),
+	}
+	allocationVariations := make([]map[string]int, len(flag.Allocations))
+	for i, a := range flag.Allocations {
+		report.Allocations[i].Key = a.Key
+		allocationVariations[i] = make(map[string]int)
+	}
+	variations := make(map[string]int)
+
+	logger := NewZapLogger(zap.NewNop())
+	for _, subject := range subjects {
+		// Subjects that are not assigned a variation, whatever the
+		// reason, get the default value.
+		var details EvaluationDetails
+		_, _ = flag.eval(subject.Key, subject.Attributes, logger, &details)
+		for i, a := range details.Allocations {
+			switch a.Reason {
+			case EvaluationReasonMatch:
+				report.Allocations[i].Matched.Subjects++
+				if details.VariationKey != "" {
+					report.Allocations[i].Assigned.Subjects++
+					allocationVariations[i][details.VariationKey]++
+				}
+			case EvaluationReasonShardMiss:
+				report.Allocations[i].Matched.Subjects++
+				report.Allocations[i].FellThrough.Subjects++
+			}
+		}
+		if details.VariationKey == "" {
+			report.Default.Subjects++
+		} else {
+			variations[details.VariationKey]++
+		}
+	}
+
+	total := len(subjects)
+	for i := range report.Allocations {
+		a := &report.Allocations[i]
+		a.Matched.setShare(total)
+		a.Assigned.setShare(total)
+		a.FellThrough.setShare(total)
+		a.Variations = variationTraffic(allocationVariations[i], total)
+	}
+	report.Variations = variationTraffic(variations, total)
+	report.Default.setShare(total)
+	return report, nil
+}
+
+func (s *TrafficShare) setShare(total int) {
+	if total > 0 {
+		s.Share = float64(s.Subjects) / float64(total)
+	}
+}
+
+func variationTraffic(counts map[string]int, total int) []VariationTraffic {
+	var result []VariationTraffic
+	for _, key := range sortedKeys(counts) {
+		v := VariationTraffic{VariationKey: key, TrafficShare: TrafficShare{Subjects: counts[key]}}
+		v.setShare(total)
+		result = append(result, v)
+	}
+	return result
+}
+
+// flagCoverageIssues finds shard gaps and overlaps in every
+// allocation and allocations shadowed by an earlier allocation that
+// always assigns a variation at time `now`.
+func flagCoverageIssues(flag *flagConfiguration, now time.Time) []CoverageIssue {
+	var issues []CoverageIssue
+	alwaysMatching := ""
+	for _, a := range flag.Allocations {
+		if alwaysMatching != "" {
+			issues = append(issues, CoverageIssue{
+				Kind:          CoverageUnreachableAllocation,
+				AllocationKey: a.Key,
+				Share:         1,
+				Message:       fmt.Sprintf("allocation is never evaluated: earlier allocation %q assigns every subject", alwaysMatching),
+			})
+			continue
+		}
+
+		coverage, ok := a.splitCoverage(flag.TotalShards)
+		if !ok {
+			continue
+		}
+		if coverage.gap > 0 {
+			issues = append(issues, CoverageIssue{
+				Kind:          CoverageShardGap,
+				AllocationKey: a.Key,
+				Share:         coverage.gap,
+				Message:       fmt.Sprintf("%.2f%% of matching subjects are not in any split and fall through", 100*coverage.gap),
+			})
+		}
+		for _, o := range coverage.overlaps {
+			issues = append(issues, CoverageIssue{
+				Kind:          CoverageShardOverlap,
+				AllocationKey: a.Key,
+				Share:         o.share,
+				Message: fmt.Sprintf("splits %d (%s) and %d (%s) overlap on %.2f%% of matching subjects, who are assigned %q",
+					o.first, a.Splits[o.first].VariationKey, o.second, a.Splits[o.second].VariationKey, 100*o.share, a.Splits[o.first].VariationKey),
+			})
+		}
+
+		if coverage.gap == 0 && a.alwaysMatchesTargeting(now) {
+			alwaysMatching = a.Key
+		}
+	}
+	return issues
+}
+
+// alwaysMatchesTargeting returns true if every subject passes the
+// allocation's rules and schedule from `now` on.
+func (a allocation) alwaysMatchesTargeting(now time.Time) bool {
+	if !a.EndAt.IsZero() || (!a.StartAt.IsZero() && now.Before(a.StartAt)) {
+		return false
+	}
+	if len(a.Rules) == 0 {
+		return true
+	}
+	for _, r := range a.Rules {
+		if len(r.Conditions) == 0 {
+			return true
+		}
+	}
+	return false
+}
+
+type splitCoverage struct {
+	// Share of subjects not matching any split.
+	gap float64
+	// Share of subjects assigned by each split, i.e., matching it
+	// and no earlier split.
+	assigned []float64
+	overlaps []splitOverlap
+}
+
+// splitOverlap is the share of subjects matching both splits with
+// indices `first` and `second`.
+type splitOverlap struct {
+	first, second int
+	share         float64
+}
+
+// splitCoverage computes which shares of subjects match no split or
+// several splits of the allocation, assuming that shards of different
+// salts are independent and uniformly distributed.
+//
+// Shard values of every salt are partitioned into cells at range
+// boundaries, so that each split either contains or excludes a whole
+// cell, and every combination of cells is inspected. Returns false if
+// there are more than maxCoverageCells combinations.
+func (a allocation) splitCoverage(totalShards int64) (splitCoverage, bool) {
+	if totalShards <= 0 {
+		return splitCoverage{}, false
+	}
+
+	// Boundaries of cells per salt, in order of first appearance.
+	var salts []string
+	boundaries := make(map[string][]int64)
+	for _, s := range a.Splits {
+		for _, sh := range s.Shards {
+			if _, ok := boundaries[sh.Salt]; !ok {
+				salts = append(salts, sh.Salt)
+				boundaries[sh.Salt] = []int64{0, totalShards}
+			}
+			for _, r := range sh.Ranges {
+				for _, b := range []int64{r.Start, r.End} {
+					if b > 0 && b < totalShards {
+						boundaries[sh.Salt] = append(boundaries[sh.Salt], b)
+					}
+				}
+			}
+		}
+	}
+	cells := 1
+	for _, salt := range salts {
+		boundaries[salt] = sortedUniqueInt64(boundaries[salt])
+		cells *= len(boundaries[salt]) - 1
+		if cells > maxCoverageCells {
+			return splitCoverage{}, false
+		}
+	}
+
+	coverage := splitCoverage{assigned: make([]float64, len(a.Splits))}
+	overlaps := make(map[[2]int]float64)
+	// Index of the current cell per salt.
+	cell := make([]int, len(salts))
+	shardValues := make(map[string]int64, len(salts))
+	for {
+		share := 1.0
+		for i, salt := range salts {
+			start, end := boundaries[salt][cell[i]], boundaries[salt][cell[i]+1]
+			shardValues[salt] = start
+			share *= float64(end-start) / float64(totalShards)
+		}
+
+		var matching []int
+		for i, s := range a.Splits {
+			if s.containsShards(shardValues) {
+				matching = append(matching, i)
+			}
+		}
+		if len(matching) == 0 {
+			coverage.gap += share
+		} else {
+			coverage.assigned[matching[0]] += share
+		}
+		if len(matching) > 1 {
+			for _, other := range matching[1:] {
+				overlaps[[2]int{matching[0], other}] += share
+			}
+		}
+
+		// Advance to the next combination of cells.
+		i := 0
+		for ; i < len(salts); i++ {
+			cell[i]++
+			if cell[i] < len(boundaries[salts[i]])-1 {
+				break
+			}
+			cell[i] = 0
+		}
+		if i == len(salts) {
+			break
+		}
+	}
+
+	for pair, share := range overlaps {
+		coverage.overlaps = append(coverage.overlaps, splitOverlap{first: pair[0], second: pair[1], share: share})
+	}
+	sort.Slice(coverage.overlaps, func(i, j int) bool {
+		a, b := coverage.overlaps[i], coverage.overlaps[j]
+		if a.first != b.first {
+			return a.first < b.first
+		}
+		return a.second < b.second
+	})
+	return coverage, true
+}
+
+// containsShards returns true if the split matches subjects with the
+// given shard values per salt.
+func (s split) containsShards(shardValues map[string]int64) bool {
+	for _, sh := range s.Shards {
+		if !sh.containsShard(shardValues[sh.Salt]) {
+			return false
+		}
+	}
+	return true
+}
+
+func sortedUniqueInt64(values []int64) []int64 {
+	sort.Slice(values, func(i, j int) bool { return values[i] < values[j] })
+	var unique []int64
+	for _, v := range values {
+		if len(unique) == 0 || v != unique[len(unique)-1] {
+			unique = append(unique, v)
+		}
+	}
+	return unique
+}
@@ -0,0 +1,203 @@
+package eppoclient
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// trafficTestFlags has a "traffic" flag serving "on" to US subjects,
+// an experiment to half of the rest with overlapping splits, and
+// "off" to everyone else. The last allocation is unreachable.
+const trafficTestFlags = `{
+  "flags": {
+    "traffic": {
+      "key": "traffic",
+      "enabled": true,
+      "variationType": "STRING",
+      "totalShards": 10000,
+      "variations": {"on": {"key": "on", "value": "on"}, "off": {"key": "off", "value": "off"}},
+      "allocations": [
+        {
+          "key": "internal",
+          "rules": [{"conditions": [{"attribute": "country", "operator": "ONE_OF", "value": ["US"]}]}],
+          "splits": [{"variationKey": "on", "shards": []}]
+        },
+        {
+          "key": "experiment",
+          "splits": [
+            {"variationKey": "on", "shards": [
+              {"salt": "exposure", "ranges": [{"start": 0, "end": 5000}]},
+              {"salt": "split", "ranges": [{"start": 0, "end": 5000}]}
+            ]},
+            {"variationKey": "off", "shards": [
+              {"salt": "exposure", "ranges": [{"start": 0, "end": 5000}]},
+              {"salt": "split", "ranges": [{"start": 4000, "end": 10000}]}
+            ]}
+          ]
+        },
+        {"key": "everyone", "splits": [{"variationKey": "off", "shards": []}]},
+        {"key": "shadowed", "splits": [{"variationKey": "on", "shards": []}]}
+      ]
+    }
+  }
+}`
+
+func Test_SimulateTraffic(t *testing.T) {
+	subjects, err := SyntheticSubjects(AttributeDistribution{
+		"country": {{Value: "US", Weight: 20}, {Value: "FR", Weight: 80}},
+	}, 10000, 1)
+	assert.NoError(t, err)
+
+	report, err := SimulateTraffic([]byte(trafficTestFlags), "traffic", subjects)
+	assert.NoError(t, err)
+	assert.Equal(t, "traffic", report.FlagKey)
+	assert.True(t, report.Enabled)
+	assert.Equal(t, 10000, report.Subjects)
+	if !assert.Len(t, report.Allocations, 4) {
+		return
+	}
+
+	internal, experiment, everyone, shadowed := report.Allocations[0], report.Allocations[1], report.Allocations[2], report.Allocations[3]
+	assert.InDelta(t, 0.2, internal.Assigned.Share, 0.02)
+	assert.Equal(t, internal.Matched, internal.Assigned)
+	assert.InDelta(t, 0.8, experiment.Matched.Share, 0.02)
+	assert.InDelta(t, 0.4, experiment.FellThrough.Share, 0.02)
+	assert.Equal(t, experiment.Matched.Subjects, experiment.Assigned.Subjects+experiment.FellThrough.Subjects)
+	if assert.Len(t, experiment.Variations, 2) {
+		// Overlapping shards go to the first split.
+		assert.InDelta(t, 0.4*0.55, experiment.Variations[1].Share, 0.02)
+		assert.Equal(t, "on", experiment.Variations[1].VariationKey)
+	}
+	assert.Equal(t, experiment.FellThrough, everyone.Assigned)
+	assert.Zero(t, shadowed.Matched.Subjects)
+
+	assert.Zero(t, report.Default.Subjects)
+	if assert.Len(t, report.Variations, 2) {
+		assert.Equal(t, 10000, report.Variations[0].Subjects+report.Variations[1].Subjects)
+	}
+
+	if assert.Len(t, report.Issues, 3) {
+		assert.Equal(t, CoverageShardGap, report.Issues[0].Kind)
+		assert.Equal(t, "experiment", report.Issues[0].AllocationKey)
+		assert.InDelta(t, 0.5, report.Issues[0].Share, 1e-9)
+
+		assert.Equal(t, CoverageShardOverlap, report.Issues[1].Kind)
+		assert.Equal(t, "experiment", report.Issues[1].AllocationKey)
+		assert.InDelta(t, 0.05, report.Issues[1].Share, 1e-9)
+		assert.Contains(t, report.Issues[1].Message, `assigned "on"`)
+
+		assert.Equal(t, CoverageIssue{
+			Kind:          CoverageUnreachableAllocation,
+			AllocationKey: "shadowed",
+			Share:         1,
+			Message:       `allocation is never evaluated: earlier allocation "everyone" assigns every subject`,
+		}, report.Issues[2])
+	}
+}
+
+func Test_SimulateTraffic_disabledFlag(t *testing.T) {
+	flags := strings.Replace(trafficTestFlags, `"enabled": true`, `"enabled": false`, 1)
+
+	report, err := SimulateTraffic([]byte(flags), "traffic", []Subject{{Key: "alice"}, {Key: "bob"}})
+	assert.NoError(t, err)
+	assert.False(t, report.Enabled)
+	assert.Equal(t, TrafficShare{Subjects: 2, Share: 1}, report.Default)
+	assert.Empty(t, report.Variations)
+	for _, a := range report.Allocations {
+		assert.Zero(t, a.Matched.Subjects)
+	}
+}
+
+func Test_SimulateTraffic_unknownFlag(t *testing.T) {
+	_, err := SimulateTraffic([]byte(trafficTestFlags), "missing", nil)
+	assert.ErrorIs(t, err, ErrFlagConfigurationNotFound)
+}
+
+func Test_allocation_splitCoverage(t *testing.T) {
+	tests := map[string]struct {
+		splits   []split
+		gap      float64
+		overlaps []splitOverlap
+	}{
+		"no splits": {
+			gap: 1,
+		},
+		"full coverage": {
+			splits: []split{
+				{Shards: []shard{{Salt: "a", Ranges: []shardRange{{Start: 0, End: 30}}}}},
+				{Shards: []shard{{Salt: "a", Ranges: []shardRange{{Start: 30, End: 100}}}}},
+			},
+		},
+		"gap between splits": {
+			splits: []split{
+				{Shards: []shard{{Salt: "a", Ranges: []shardRange{{Start: 0, End: 30}}}}},
+				{Shards: []shard{{Salt: "a", Ranges: []shardRange{{Start: 40, End: 100}}}}},
+			},
+			gap: 0.1,
+		},
+		"overlapping splits": {
+			splits: []split{
+				{Shards: []shard{{Salt: "a", Ranges: []shardRange{{Start: 0, End: 60}}}}},
+				{Shards: []shard{{Salt: "a", Ranges: []shardRange{{Start: 50, End: 100}}}}},
+				{},
+			},
+			overlaps: []splitOverlap{{first: 0, second: 1, share: 0.1}, {first: 0, second: 2, share: 0.6}, {first: 1, second: 2, share: 0.4}},
+		},
+		"independent salts": {
+			splits: []split{
+				{Shards: []shard{
+					{Salt: "a", Ranges: []shardRange{{Start: 0, End: 50}}},
+					{Salt: "b", Ranges: []shardRange{{Start: 0, End: 50}}},
+				}},
+			},
+			gap: 0.75,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			coverage, ok := allocation{Splits: tt.splits}.splitCoverage(100)
+			assert.True(t, ok)
+			assert.InDelta(t, tt.gap, coverage.gap, 1e-9)
+			assert.Equal(t, len(tt.overlaps), len(coverage.overlaps))
+			for i := range tt.overlaps {
+				if i < len(coverage.overlaps) {
+					assert.Equal(t, tt.overlaps[i].first, coverage.overlaps[i].first)
+					assert.Equal(t, tt.overlaps[i].second, coverage.overlaps[i].second)
+					assert.InDelta(t, tt.overlaps[i].share, coverage.overlaps[i].share, 1e-9)
+				}
+			}
+		})
+	}
+}
+
+func Test_SyntheticSubjects(t *testing.T) {
+	distribution := AttributeDistribution{
+		"plan":    {{Value: "free", Weight: 3}, {Value: "pro", Weight: 1}},
+		"company": {{Value: nil, Weight: 1}, {Value: "acme", Weight: 1}},
+	}
+	subjects, err := SyntheticSubjects(distribution, 1000, 42)
+	assert.NoError(t, err)
+	assert.Len(t, subjects, 1000)
+
+	again, _ := SyntheticSubjects(distribution, 1000, 42)
+	assert.Equal(t, subjects, again)
+
+	free, withCompany := 0, 0
+	for _, s := range subjects {
+		if s.Attributes["plan"] == "free" {
+			free++
+		}
+		if _, ok := s.Attributes["company"]; ok {
+			withCompany++
+		}
+	}
+	assert.InDelta(t, 750, free, 50)
+	assert.InDelta(t, 500, withCompany, 50)
+
+	_, err = SyntheticSubjects(AttributeDistribution{"plan": {{Value: "free", Weight: -1}}}, 1, 1)
+	assert.Error(t, err)
+	_, err = SyntheticSubjects(AttributeDistribution{"plan": {}}, 1, 1)
+	assert.Error(t, err)
+}
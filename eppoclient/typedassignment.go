@@ -0,0 +1,168 @@
+package eppoclient
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+// GetAssignment returns the assignment for the given flag as a value
+// of type T.
+//
+// bool, int64, float64, and string map to BOOLEAN, INTEGER, NUMERIC,
+// and STRING flags respectively. Any other T requires a JSON flag and
+// the variation value is decoded into T with encoding/json.
+//
+// Decoded values are cached per variation and shared between calls,
+// so the result must be treated as read-only if T contains maps,
+// slices, or pointers.
+//
+// If the variation cannot be decoded into T, defaultValue is returned
+// along with an error wrapping ErrVariationDecode and the assignment
+// is not logged.
+//
+// Implementations of Client other than EppoClient (e.g., the fake in
+// package eppotest) are served by the typed getter matching T, or by
+// GetJSONBytesAssignmentContext, and their JSON results are not
+// cached.
+func GetAssignment[T any](
+	client Client,
+	flagKey, subjectKey string,
+	subjectAttributes Attributes,
+	defaultValue T,
+) (T, error) {
+	return getTypedAssignment(context.Background(), client, flagKey, subjectKey, subjectAttributes, defaultValue)
+}
+
+func GetAssignmentContext[T any](
+	ctx context.Context,
+	client Client,
+	flagKey, subjectKey string,
+	subjectAttributes Attributes,
+	defaultValue T,
+) (T, error) {
+	return getTypedAssignment(ctx, client, flagKey, subjectKey, subjectAttributes, defaultValue)
+}
+
+func getTypedAssignment[T any](
+	ctx context.Context,
+	client Client,
+	flagKey, subjectKey string,
+	subjectAttributes Attributes,
+	defaultValue T,
+) (T, error) {
+	ec, ok := client.(*EppoClient)
+	if !ok {
+		return getClientTypedAssignment(ctx, client, flagKey, subjectKey, subjectAttributes, defaultValue)
+	}
+
+	// The value is decoded before the assignment is logged, so that
+	// nothing is logged if the caller receives the default value.
+	result := defaultValue
+	decode := func(variation interface{}) error {
+		if variation == nil {
+			return nil
+		}
+		value, err := convertVariation[T](variation)
+		if err != nil {
+			ec.applicationLogger.Warnf("failed to decode variation of flag %s: %v", flagKey, err)
+			return err
+		}
+		result = value
+		return nil
+	}
+
+	_, err := ec.assign(ctx, ec.configurationStore.getConfiguration(), flagKey, subjectKey, subjectAttributes, variationTypeOf[T](), nil, assignOptions{logging: true, decode: decode})
+	if err != nil {
+		return defaultValue, err
+	}
+	return result, nil
+}
+
+// convertVariation converts the internal representation of a
+// variation value to T.
+func convertVariation[T any](variation interface{}) (T, error) {
+	if value, ok := variation.(jsonVariationValue); ok {
+		return decodeJSONVariation[T](value)
+	}
+	result, ok := variation.(T)
+	if !ok {
+		return result, fmt.Errorf("%w: failed to cast %v to %T", ErrVariationDecode, variation, result)
+	}
+	return result, nil
+}
+
+// Returns the assignment through the typed getters of a Client that
+// is not an EppoClient.
+func getClientTypedAssignment[T any](
+	ctx context.Context,
+	client Client,
+	flagKey, subjectKey string,
+	subjectAttributes Attributes,
+	defaultValue T,
+) (T, error) {
+	var variation interface{}
+	var err error
+	switch value := any(defaultValue).(type) {
+	case bool:
+		variation, err = client.GetBoolAssignmentContext(ctx, flagKey, subjectKey, subjectAttributes, value)
+	case int64:
+		variation, err = client.GetIntegerAssignmentContext(ctx, flagKey, subjectKey, subjectAttributes, value)
+	case float64:
+		variation, err = client.GetNumericAssignmentContext(ctx, flagKey, subjectKey, subjectAttributes, value)
+	case string:
+		variation, err = client.GetStringAssignmentContext(ctx, flagKey, subjectKey, subjectAttributes, value)
+	default:
+		raw, err := client.GetJSONBytesAssignmentContext(ctx, flagKey, subjectKey, subjectAttributes, nil)
+		if err != nil || raw == nil {
+			return defaultValue, err
+		}
+		var result T
+		if err := json.Unmarshal(raw, &result); err != nil {
+			return defaultValue, fmt.Errorf("%w: %v", ErrVariationDecode, err)
+		}
+		return result, nil
+	}
+	if err != nil {
+		return defaultValue, err
+	}
+	return variation.(T), nil
+}
+
+// Returns the flag variation type expected for T.
+func variationTypeOf[T any]() variationType {
+	var zero T
+	switch any(zero).(type) {
+	case bool:
+		return booleanVariation
+	case int64:
+		return integerVariation
+	case float64:
+		return numericVariation
+	case string:
+		return stringVariation
+	default:
+		return jsonVariation
+	}
+}
+
+func decodeJSONVariation[T any](value jsonVariationValue) (T, error) {
+	ty := reflect.TypeOf((*T)(nil)).Elem()
+	if value.decoded != nil {
+		if cached, ok := value.decoded.Load(ty); ok {
+			return cached.(T), nil
+		}
+	}
+
+	var result T
+	err := json.Unmarshal(value.Raw, &result)
+	if err != nil {
+		return result, fmt.Errorf("%w: %v", ErrVariationDecode, err)
+	}
+
+	if value.decoded != nil {
+		value.decoded.Store(ty, result)
+	}
+	return result, nil
+}
@@ -0,0 +1,102 @@
+package eppoclient
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type typedAssignmentTestValue struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+func newTypedAssignmentTestClient(variationType variationType, value string) *EppoClient {
+	config := configResponse{
+		Flags: map[string]*flagConfiguration{
+			"flag": {
+				Key:           "flag",
+				Enabled:       true,
+				TotalShards:   10000,
+				VariationType: variationType,
+				Variations: map[string]variation{
+					"on": {Key: "on", Value: []byte(value)},
+				},
+				Allocations: []allocation{
+					{Key: "allocation", Splits: []split{{VariationKey: "on"}}},
+				},
+			},
+		},
+	}
+	return newEppoClient(newConfigurationStoreWithConfig(configuration{flags: config}), nil, nil, nil, nil, applicationLogger)
+}
+
+func Test_GetAssignment_scalars(t *testing.T) {
+	b, err := GetAssignment(newTypedAssignmentTestClient(booleanVariation, `true`), "flag", "subject", Attributes{}, false)
+	assert.NoError(t, err)
+	assert.True(t, b)
+
+	i, err := GetAssignment(newTypedAssignmentTestClient(integerVariation, `42`), "flag", "subject", Attributes{}, int64(0))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(42), i)
+
+	f, err := GetAssignment(newTypedAssignmentTestClient(numericVariation, `4.2`), "flag", "subject", Attributes{}, 0.0)
+	assert.NoError(t, err)
+	assert.Equal(t, 4.2, f)
+
+	s, err := GetAssignment(newTypedAssignmentTestClient(stringVariation, `"on"`), "flag", "subject", Attributes{}, "off")
+	assert.NoError(t, err)
+	assert.Equal(t, "on", s)
+}
+
+func Test_GetAssignment_typeMismatch(t *testing.T) {
+	client := newTypedAssignmentTestClient(stringVariation, `"on"`)
+
+	value, err := GetAssignment(client, "flag", "subject", Attributes{}, true)
+
+	assert.Error(t, err)
+	assert.True(t, value)
+}
+
+func Test_GetAssignment_struct(t *testing.T) {
+	client := newTypedAssignmentTestClient(jsonVariation, `"{\"name\": \"eppo\", \"count\": 3}"`)
+
+	value, err := GetAssignment(client, "flag", "subject", Attributes{}, typedAssignmentTestValue{})
+
+	assert.NoError(t, err)
+	assert.Equal(t, typedAssignmentTestValue{Name: "eppo", Count: 3}, value)
+}
+
+func Test_GetAssignment_cachesDecodedValue(t *testing.T) {
+	client := newTypedAssignmentTestClient(jsonVariation, `"{\"name\": \"eppo\", \"count\": 3}"`)
+
+	first, err := GetAssignment(client, "flag", "subject", Attributes{}, &typedAssignmentTestValue{})
+	assert.NoError(t, err)
+	second, err := GetAssignment(client, "flag", "subject", Attributes{}, &typedAssignmentTestValue{})
+	assert.NoError(t, err)
+
+	// The same decoded pointer is returned from cache.
+	assert.Same(t, first, second)
+}
+
+func Test_GetAssignment_decodeError(t *testing.T) {
+	logger := new(mockLogger)
+	logger.Mock.On("LogAssignment", mock.Anything).Return()
+	client := newTypedAssignmentTestClient(jsonVariation, `"{\"name\": 42}"`)
+	client.logger = logger
+	metrics := NewPrometheusMetrics()
+	client.metrics = metrics
+	defaultValue := typedAssignmentTestValue{Name: "default"}
+
+	value, err := GetAssignment(client, "flag", "subject", Attributes{}, defaultValue)
+
+	assert.ErrorIs(t, err, ErrVariationDecode)
+	assert.Equal(t, defaultValue, value)
+	// The caller receives the default value, so the assignment is
+	// neither logged nor counted as assigned.
+	logger.AssertNotCalled(t, "LogAssignment", mock.Anything)
+	assert.Equal(t, []counterSample{
+		{labels: labels{"flag", "error", "other"}, value: 1},
+	}, metrics.evaluations.snapshot())
+}
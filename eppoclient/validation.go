@@ -0,0 +1,379 @@
+package eppoclient
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// DiagnosticSeverity is the severity of a configuration problem.
+type DiagnosticSeverity string
+
+const (
+	// The configuration is malformed: it cannot be parsed, would
+	// make evaluation panic, or references missing variations.
+	// Configurations with errors are rejected.
+	SeverityError DiagnosticSeverity = "error"
+	// Part of the configuration is ignored or never matches, e.g.,
+	// invalid variation values, regular expressions that Go cannot
+	// compile, or operators unknown to this SDK version. Such
+	// problems are limited to the affected flags and may be caused
+	// by server features newer than the SDK, so they do not reject
+	// the configuration.
+	SeverityWarning DiagnosticSeverity = "warning"
+)
+
+// Diagnostic is a single problem found by ValidateConfiguration.
+type Diagnostic struct {
+	Severity DiagnosticSeverity `json:"severity"`
+	// Location of the problem within the flags configuration, e.g.,
+	// "flags.my-flag.allocations[0].rules[1].conditions[2]", or
+	// within the bandits configuration for problems of bandit
+	// models, e.g., "bandits.my-bandit.modelData.gamma". Flag keys
+	// are hashed in obfuscated configurations.
+	Path string `json:"path"`
+	// Key of the flag the problem was found in. Empty for problems
+	// outside of flags and for flags of obfuscated configurations,
+	// whose plaintext keys are not known.
+	FlagKey string `json:"flagKey,omitempty"`
+	Message string `json:"message"`
+}
+
+func (d Diagnostic) String() string {
+	if d.Path == "" {
+		return fmt.Sprintf("%s: %s", d.Severity, d.Message)
+	}
+	return fmt.Sprintf("%s: %s: %s", d.Severity, d.Path, d.Message)
+}
+
+// Diagnostics is a list of configuration problems.
+type Diagnostics []Diagnostic
+
+// HasErrors reports whether any diagnostic is an error.
+func (ds Diagnostics) HasErrors() bool {
+	return len(ds.Errors()) > 0
+}
+
+// Errors returns diagnostics with SeverityError.
+func (ds Diagnostics) Errors() Diagnostics {
+	return ds.filter(SeverityError)
+}
+
+// Warnings returns diagnostics with SeverityWarning.
+func (ds Diagnostics) Warnings() Diagnostics {
+	return ds.filter(SeverityWarning)
+}
+
+func (ds Diagnostics) filter(severity DiagnosticSeverity) Diagnostics {
+	var result Diagnostics
+	for _, d := range ds {
+		if d.Severity == severity {
+			result = append(result, d)
+		}
+	}
+	return result
+}
+
+// ValidationError is returned when a configuration is rejected
+// because ValidateConfiguration has found errors. It matches
+// ErrInvalidConfiguration with errors.Is.
+type ValidationError struct {
+	// All diagnostics, including warnings.
+	Diagnostics Diagnostics
+}
+
+func (e *ValidationError) Error() string {
+	errs := e.Diagnostics.Errors()
+	if len(errs) == 0 {
+		return ErrInvalidConfiguration.Error()
+	}
+	msg := fmt.Sprintf("%v: %s", ErrInvalidConfiguration, errs[0])
+	if len(errs) > 1 {
+		msg += fmt.Sprintf(" (and %d more errors)", len(errs)-1)
+	}
+	return msg
+}
+
+func (e *ValidationError) Is(target error) bool {
+	return target == ErrInvalidConfiguration
+}
+
+// ValidateConfiguration checks a flags configuration (as served at
+// CONFIG_ENDPOINT) and optional bandit models (as served at
+// BANDIT_ENDPOINT) for problems that would break evaluation.
+// `banditsJSON` may be nil.
+//
+// The client runs the same validation before activating any
+// configuration and rejects configurations with errors, keeping the
+// previous one.
+func ValidateConfiguration(flagsJSON, banditsJSON []byte) Diagnostics {
+	v := &validator{}
+	v.validateJSON(flagsJSON, banditsJSON)
+	return v.diagnostics
+}
+
+// validateConfiguration validates already decoded configuration
+// before it is activated and returns a *ValidationError if there are
+// errors. `bandits` is nil if there is no bandits configuration.
+// Warnings are logged.
+//
+// Obfuscated flags are decoded in place.
+func validateConfiguration(flags *configResponse, bandits *banditResponse, applicationLogger ApplicationLogger) error {
+	v := &validator{}
+	v.validate(flags, bandits)
+	diagnostics := v.diagnostics
+	if applicationLogger != nil {
+		for _, d := range diagnostics.Warnings() {
+			applicationLogger.Warnf("configuration %s", d)
+		}
+	}
+	if diagnostics.HasErrors() {
+		return &ValidationError{Diagnostics: diagnostics}
+	}
+	return nil
+}
+
+type validator struct {
+	diagnostics Diagnostics
+}
+
+func (v *validator) report(severity DiagnosticSeverity, path, flagKey, format string, args ...interface{}) {
+	v.diagnostics = append(v.diagnostics, Diagnostic{
+		Severity: severity,
+		Path:     path,
+		FlagKey:  flagKey,
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+// validateJSON parses flags one by one, so that a malformed flag is
+// reported without hiding problems of the others, and validates the
+// result.
+func (v *validator) validateJSON(flagsJSON, banditsJSON []byte) {
+	var response struct {
+		Format  string                       `json:"format"`
+		Flags   map[string]json.RawMessage   `json:"flags"`
+		Bandits map[string][]banditVariation `json:"bandits"`
+	}
+	if err := json.Unmarshal(flagsJSON, &response); err != nil {
+		v.report(SeverityError, "", "", "failed to parse flags configuration: %v", err)
+		return
+	}
+
+	flags := configResponse{Format: response.Format, Bandits: response.Bandits}
+	if response.Flags != nil {
+		flags.Flags = make(map[string]*flagConfiguration, len(response.Flags))
+	}
+	for _, key := range sortedKeys(response.Flags) {
+		var flag *flagConfiguration
+		if err := json.Unmarshal(response.Flags[key], &flag); err != nil {
+			flagKey := key
+			if flags.isObfuscated() {
+				flagKey = ""
+			}
+			v.report(SeverityError, "flags."+key, flagKey, "failed to parse flag: %v", err)
+			continue
+		}
+		flags.Flags[key] = flag
+	}
+
+	var bandits *banditResponse
+	if len(banditsJSON) > 0 {
+		bandits = &banditResponse{}
+		if err := json.Unmarshal(banditsJSON, bandits); err != nil {
+			v.report(SeverityError, "bandits", "", "failed to parse bandits configuration: %v", err)
+			bandits = nil
+		}
+	}
+
+	v.validate(&flags, bandits)
+}
+
+func (v *validator) validate(response *configResponse, bandits *banditResponse) {
+	if response.Flags == nil {
+		v.report(SeverityError, "", "", "missing \"flags\" field")
+		return
+	}
+	obfuscated := response.isObfuscated()
+
+	for _, key := range sortedKeys(response.Flags) {
+		path := "flags." + key
+		flagKey := key
+		if obfuscated {
+			flagKey = ""
+		}
+		flag := response.Flags[key]
+		if flag == nil {
+			v.report(SeverityError, path, flagKey, "flag is null")
+			continue
+		}
+		if obfuscated {
+			flag.deobfuscate()
+		} else if flag.Key != key {
+			v.report(SeverityWarning, path+".key", key, "flag key %q does not match its key in configuration", flag.Key)
+		}
+		v.validateFlag(path, flagKey, flag)
+	}
+
+	if bandits != nil {
+		v.validateBandits(bandits)
+	}
+
+	for _, banditKey := range sortedKeys(response.Bandits) {
+		for i, bv := range response.Bandits[banditKey] {
+			path := fmt.Sprintf("bandits.%s[%d]", banditKey, i)
+			flag, ok := response.Flags[bv.FlagKey]
+			if !ok && obfuscated {
+				flag, ok = response.Flags[hashWithSalt(bv.FlagKey, "")]
+			}
+			if !ok || flag == nil {
+				v.report(SeverityWarning, path, bv.FlagKey, "bandit references unknown flag %q", bv.FlagKey)
+			} else if _, ok := flag.Variations[bv.VariationKey]; !ok {
+				v.report(SeverityWarning, path, bv.FlagKey, "bandit references unknown variation %q of flag %q", bv.VariationKey, bv.FlagKey)
+			}
+			if bandits != nil {
+				if _, ok := bandits.Bandits[banditKey]; !ok {
+					v.report(SeverityWarning, path, bv.FlagKey, "bandit %q is missing from bandits configuration", banditKey)
+				}
+			}
+		}
+	}
+}
+
+func (v *validator) validateFlag(path, flagKey string, flag *flagConfiguration) {
+	for _, key := range sortedKeys(flag.Variations) {
+		variation := flag.Variations[key]
+		variationPath := path + ".variations." + key
+		if variation.Key != key {
+			v.report(SeverityWarning, variationPath, flagKey, "variation key %q does not match its key in configuration", variation.Key)
+		}
+		if _, err := flag.VariationType.parseVariationValue(variation.Value); err != nil {
+			v.report(SeverityWarning, variationPath, flagKey, "value %s is not a valid %s value and is ignored: %v", variation.Value, flag.VariationType.toPublic(), err)
+		}
+	}
+
+	hasShards := false
+	allocationKeys := make(map[string]bool, len(flag.Allocations))
+	for i, allocation := range flag.Allocations {
+		allocationPath := fmt.Sprintf("%s.allocations[%d]", path, i)
+		if allocationKeys[allocation.Key] {
+			v.report(SeverityWarning, allocationPath, flagKey, "duplicate allocation key %q", allocation.Key)
+		}
+		allocationKeys[allocation.Key] = true
+
+		if !allocation.StartAt.IsZero() && !allocation.EndAt.IsZero() && !allocation.StartAt.Before(allocation.EndAt) {
+			v.report(SeverityWarning, allocationPath, flagKey, "allocation ends (%v) before it starts (%v) and is never active", allocation.EndAt, allocation.StartAt)
+		}
+
+		for j, rule := range allocation.Rules {
+			for k, condition := range rule.Conditions {
+				v.validateCondition(fmt.Sprintf("%s.rules[%d].conditions[%d]", allocationPath, j, k), flagKey, condition)
+			}
+		}
+
+		if len(allocation.Splits) == 0 {
+			v.report(SeverityWarning, allocationPath, flagKey, "allocation has no splits and never matches")
+		}
+		for j, split := range allocation.Splits {
+			splitPath := fmt.Sprintf("%s.splits[%d]", allocationPath, j)
+			if _, ok := flag.Variations[split.VariationKey]; !ok {
+				v.report(SeverityError, splitPath, flagKey, "split references unknown variation %q", split.VariationKey)
+			}
+			for k, shard := range split.Shards {
+				hasShards = true
+				v.validateShard(fmt.Sprintf("%s.shards[%d]", splitPath, k), flagKey, shard, flag.TotalShards)
+			}
+		}
+	}
+
+	if hasShards && flag.TotalShards <= 0 {
+		v.report(SeverityError, path+".totalShards", flagKey, "totalShards must be positive, got %d", flag.TotalShards)
+	}
+}
+
+func (v *validator) validateShard(path, flagKey string, shard shard, totalShards int64) {
+	if len(shard.Ranges) == 0 {
+		v.report(SeverityWarning, path, flagKey, "shard has no ranges and never matches")
+	}
+	for i, r := range shard.Ranges {
+		rangePath := fmt.Sprintf("%s.ranges[%d]", path, i)
+		switch {
+		case r.Start >= r.End:
+			v.report(SeverityWarning, rangePath, flagKey, "shard range [%d, %d) is empty", r.Start, r.End)
+		case r.Start < 0 || (totalShards > 0 && r.End > totalShards):
+			v.report(SeverityWarning, rangePath, flagKey, "shard range [%d, %d) exceeds [0, %d)", r.Start, r.End, totalShards)
+		}
+	}
+}
+
+func (v *validator) validateCondition(path, flagKey string, c condition) {
+	if c.Attribute == "" {
+		v.report(SeverityWarning, path, flagKey, "condition has no attribute")
+	}
+
+	switch c.Operator {
+	case "MATCHES", "NOT_MATCHES":
+		pattern, ok := c.Value.(string)
+		if !ok {
+			v.report(SeverityError, path, flagKey, "%s condition value must be a string, got %s", c.Operator, describeValue(c.Value))
+			return
+		}
+		if _, err := regexp.Compile(pattern); err != nil {
+			v.report(SeverityWarning, path, flagKey, "invalid regular expression %q never matches: %v", pattern, err)
+		}
+	case "ONE_OF", "NOT_ONE_OF":
+		if _, ok := convertToStringArray(c.Value); !ok {
+			v.report(SeverityError, path, flagKey, "%s condition value must be a list of strings, got %s", c.Operator, describeValue(c.Value))
+		}
+	case "GT", "GTE", "LT", "LTE":
+		c.precompute()
+		if !c.NumericValueValid && !c.SemVerValueValid {
+			v.report(SeverityWarning, path, flagKey, "%s condition value %s is neither a number nor a semantic version and never matches", c.Operator, describeValue(c.Value))
+		}
+	case "IS_NULL":
+		if _, ok := c.Value.(bool); !ok {
+			v.report(SeverityError, path, flagKey, "IS_NULL condition value must be a boolean, got %s", describeValue(c.Value))
+		}
+	default:
+		v.report(SeverityWarning, path, flagKey, "unknown condition operator %q never matches", c.Operator)
+	}
+}
+
+func (v *validator) validateBandits(bandits *banditResponse) {
+	for _, key := range sortedKeys(bandits.Bandits) {
+		bandit := bandits.Bandits[key]
+		path := "bandits." + key
+		if bandit.BanditKey != key {
+			v.report(SeverityWarning, path+".banditKey", "", "bandit key %q does not match its key in configuration", bandit.BanditKey)
+		}
+		floor := bandit.ModelData.ActionProbabilityFloor
+		if floor < 0 || floor > 1 {
+			v.report(SeverityWarning, path+".modelData.actionProbabilityFloor", "", "action probability floor %v is outside of [0, 1]", floor)
+		}
+		if bandit.ModelData.Gamma < 0 {
+			v.report(SeverityWarning, path+".modelData.gamma", "", "gamma %v is negative", bandit.ModelData.Gamma)
+		}
+	}
+}
+
+func describeValue(value interface{}) string {
+	if value == nil {
+		return "null"
+	}
+	b, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprintf("%v", value)
+	}
+	return strings.TrimSpace(string(b))
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
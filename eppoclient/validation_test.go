@@ -0,0 +1,263 @@
+package eppoclient
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// validationTestFlags returns initClientTestFlags with replacements
+// applied. `replacements` are pairs of old and new strings.
+func validationTestFlags(t *testing.T, replacements ...string) []byte {
+	flags := initClientTestFlags
+	for i := 0; i < len(replacements); i += 2 {
+		if !strings.Contains(flags, replacements[i]) {
+			t.Fatalf("%q not found in test flags", replacements[i])
+		}
+		flags = strings.Replace(flags, replacements[i], replacements[i+1], 1)
+	}
+	return []byte(flags)
+}
+
+const validationTestShards = `"shards": [{"salt": "salt", "ranges": [{"start": 0, "end": 10}]}]`
+
+func validationTestCondition(t *testing.T, condition string) []byte {
+	return validationTestFlags(t, `{"key": "allocation", `, `{"key": "allocation", "rules": [{"conditions": [`+condition+`]}], `)
+}
+
+func Test_ValidateConfiguration_valid(t *testing.T) {
+	assert.Empty(t, ValidateConfiguration([]byte(initClientTestFlags), nil))
+}
+
+func Test_ValidateConfiguration(t *testing.T) {
+	tests := map[string]struct {
+		flags    []byte
+		bandits  []byte
+		severity DiagnosticSeverity
+		path     string
+	}{
+		"invalid JSON": {
+			flags:    []byte(`{"flags": `),
+			severity: SeverityError,
+		},
+		"missing flags": {
+			flags:    []byte(`{}`),
+			severity: SeverityError,
+		},
+		"unknown variation type": {
+			flags:    validationTestFlags(t, `"STRING"`, `"DATE"`),
+			severity: SeverityError,
+			path:     "flags.flag",
+		},
+		"zero totalShards": {
+			flags:    validationTestFlags(t, `"shards": []`, validationTestShards, `"totalShards": 10000`, `"totalShards": 0`),
+			severity: SeverityError,
+			path:     "flags.flag.totalShards",
+		},
+		"unknown split variation": {
+			flags:    validationTestFlags(t, `{"variationKey": "on"`, `{"variationKey": "unknown"`),
+			severity: SeverityError,
+			path:     "flags.flag.allocations[0].splits[0]",
+		},
+		"non-string MATCHES": {
+			flags:    validationTestCondition(t, `{"attribute": "email", "operator": "MATCHES", "value": 1}`),
+			severity: SeverityError,
+			path:     "flags.flag.allocations[0].rules[0].conditions[0]",
+		},
+		"non-string ONE_OF": {
+			flags:    validationTestCondition(t, `{"attribute": "age", "operator": "ONE_OF", "value": ["1", 2]}`),
+			severity: SeverityError,
+			path:     "flags.flag.allocations[0].rules[0].conditions[0]",
+		},
+		"null NOT_ONE_OF": {
+			flags:    validationTestCondition(t, `{"attribute": "age", "operator": "NOT_ONE_OF", "value": null}`),
+			severity: SeverityError,
+			path:     "flags.flag.allocations[0].rules[0].conditions[0]",
+		},
+		"non-boolean IS_NULL": {
+			flags:    validationTestCondition(t, `{"attribute": "age", "operator": "IS_NULL", "value": "yes"}`),
+			severity: SeverityError,
+			path:     "flags.flag.allocations[0].rules[0].conditions[0]",
+		},
+		"invalid regex": {
+			flags:    validationTestCondition(t, `{"attribute": "email", "operator": "MATCHES", "value": "(?=lookahead)"}`),
+			severity: SeverityWarning,
+			path:     "flags.flag.allocations[0].rules[0].conditions[0]",
+		},
+		"unknown operator": {
+			flags:    validationTestCondition(t, `{"attribute": "email", "operator": "STARTS_WITH", "value": "a"}`),
+			severity: SeverityWarning,
+			path:     "flags.flag.allocations[0].rules[0].conditions[0]",
+		},
+		"non-numeric comparison": {
+			flags:    validationTestCondition(t, `{"attribute": "age", "operator": "GT", "value": "old"}`),
+			severity: SeverityWarning,
+			path:     "flags.flag.allocations[0].rules[0].conditions[0]",
+		},
+		"invalid variation value": {
+			flags:    validationTestFlags(t, `"value": "off"`, `"value": 3`),
+			severity: SeverityWarning,
+			path:     "flags.flag.variations.off",
+		},
+		"mismatching flag key": {
+			flags:    validationTestFlags(t, `"key": "flag"`, `"key": "other"`),
+			severity: SeverityWarning,
+			path:     "flags.flag.key",
+		},
+		"empty shard range": {
+			flags:    validationTestFlags(t, `"shards": []`, `"shards": [{"salt": "salt", "ranges": [{"start": 10, "end": 10}]}]`),
+			severity: SeverityWarning,
+			path:     "flags.flag.allocations[0].splits[0].shards[0].ranges[0]",
+		},
+		"unknown bandit flag": {
+			flags:    validationTestFlags(t, `"flags": {`, `"bandits": {"bandit": [{"key": "bandit", "flagKey": "missing", "variationKey": "bandit", "variationValue": "bandit"}]}, "flags": {`),
+			severity: SeverityWarning,
+			path:     "bandits.bandit[0]",
+		},
+		"invalid bandits": {
+			flags:    []byte(initClientTestFlags),
+			bandits:  []byte(`[]`),
+			severity: SeverityError,
+			path:     "bandits",
+		},
+		"invalid probability floor": {
+			flags:    []byte(initClientTestFlags),
+			bandits:  []byte(`{"bandits": {"bandit": {"banditKey": "bandit", "modelData": {"actionProbabilityFloor": 2}}}}`),
+			severity: SeverityWarning,
+			path:     "bandits.bandit.modelData.actionProbabilityFloor",
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			diagnostics := ValidateConfiguration(tt.flags, tt.bandits)
+			if assert.Len(t, diagnostics, 1, "%v", diagnostics) {
+				assert.Equal(t, tt.severity, diagnostics[0].Severity)
+				assert.Equal(t, tt.path, diagnostics[0].Path)
+				assert.NotEmpty(t, diagnostics[0].Message)
+			}
+			assert.Equal(t, tt.severity == SeverityError, diagnostics.HasErrors())
+		})
+	}
+}
+
+// obfuscatedValidationTestFlags is a configuration in the CLIENT
+// format with an invalid regular expression.
+func obfuscatedValidationTestFlags() []byte {
+	return []byte(`{
+  "format": "CLIENT",
+  "flags": {
+    "` + hashWithSalt("flag", "") + `": {
+      "key": "` + hashWithSalt("flag", "") + `",
+      "enabled": true,
+      "variationType": "STRING",
+      "totalShards": 10000,
+      "variations": {"on": {"key": "on", "value": "` + encodeBase64("on") + `"}},
+      "allocations": [{
+        "key": "` + encodeBase64("allocation") + `",
+        "rules": [{"conditions": [{"attribute": "` + encodeBase64("email") + `", "operator": "MATCHES", "value": "` + encodeBase64("(") + `"}]}],
+        "splits": [{"variationKey": "on", "shards": []}]
+      }]
+    }
+  }
+}`)
+}
+
+func Test_ValidateConfiguration_obfuscated(t *testing.T) {
+	diagnostics := ValidateConfiguration(obfuscatedValidationTestFlags(), nil)
+	if assert.Len(t, diagnostics, 1) {
+		assert.Equal(t, SeverityWarning, diagnostics[0].Severity)
+		assert.Contains(t, diagnostics[0].Message, `"("`)
+	}
+}
+
+func Test_configuration_validate(t *testing.T) {
+	config, err := newConfigurationFromJSON([]byte(`{"flags": {"flag": null}}`), nil)
+	assert.NoError(t, err)
+	err = config.validate(false, nil)
+	var validationErr *ValidationError
+	if assert.True(t, errors.As(err, &validationErr)) {
+		// Same diagnostics as for the raw configuration.
+		assert.Equal(t, ValidateConfiguration([]byte(`{"flags": {"flag": null}}`), nil), validationErr.Diagnostics)
+	}
+}
+
+func Test_configuration_validateObfuscated(t *testing.T) {
+	config, err := newConfigurationFromJSON(obfuscatedValidationTestFlags(), nil)
+	assert.NoError(t, err)
+	assert.NoError(t, config.validate(false, nil))
+
+	// Flags decoded by validation are not decoded again.
+	config.precompute()
+	flag, err := config.getFlagConfiguration("flag")
+	if assert.NoError(t, err) {
+		assert.Equal(t, "allocation", flag.Allocations[0].Key)
+		assert.Equal(t, "email", flag.Allocations[0].Rules[0].Conditions[0].Attribute)
+	}
+}
+
+func Test_SetConfiguration_rejectsInvalidConfiguration(t *testing.T) {
+	client, err := InitClientFromConfiguration(Config{ApplicationLogger: applicationLogger}, []byte(initClientTestFlags), nil)
+	assert.NoError(t, err)
+
+	err = client.SetConfiguration(validationTestFlags(t, `{"variationKey": "on"`, `{"variationKey": "unknown"`), nil)
+	assert.ErrorIs(t, err, ErrInvalidConfiguration)
+	var validationErr *ValidationError
+	if assert.True(t, errors.As(err, &validationErr)) {
+		assert.Equal(t, "flags.flag.allocations[0].splits[0]", validationErr.Diagnostics[0].Path)
+	}
+
+	// The previous configuration stays active.
+	value, err := client.GetStringAssignment("flag", "subject", Attributes{}, "default")
+	assert.NoError(t, err)
+	assert.Equal(t, "on", value)
+
+	// Warnings do not reject configuration.
+	assert.NoError(t, client.SetConfiguration(validationTestFlags(t, `"key": "flag"`, `"key": "renamed"`, `"value": "on"`, `"value": "other"`), nil))
+	value, _ = client.GetStringAssignment("flag", "subject", Attributes{}, "default")
+	assert.Equal(t, "other", value)
+}
+
+func Test_InitClientFromConfiguration_rejectsInvalidConfiguration(t *testing.T) {
+	_, err := InitClientFromConfiguration(Config{ApplicationLogger: applicationLogger},
+		validationTestCondition(t, `{"attribute": "email", "operator": "MATCHES", "value": ["a"]}`), nil)
+	assert.ErrorIs(t, err, ErrInvalidConfiguration)
+}
+
+func Test_configurationRequestor_keepsLastValidConfiguration(t *testing.T) {
+	server := newConditionalTestServer(initClientTestFlags, "")
+	defer server.Close()
+	requestor, store := newConditionalTestRequestor(server)
+
+	assert.NoError(t, requestor.FetchAndStoreConfigurations())
+	first := store.configuration.Load()
+
+	server.flags = string(validationTestFlags(t, `"shards": []`, validationTestShards, `"totalShards": 10000`, `"totalShards": 0`))
+	server.flagsETag = `"flags-2"`
+	err := requestor.FetchAndStoreConfigurations()
+	assert.ErrorIs(t, err, ErrInvalidConfiguration)
+	assert.Same(t, first, store.configuration.Load())
+	assert.ErrorIs(t, requestor.lastError(), ErrInvalidConfiguration)
+
+	// The rejected configuration is fetched and validated again
+	// instead of being treated as not modified.
+	err = requestor.FetchAndStoreConfigurations()
+	assert.ErrorIs(t, err, ErrInvalidConfiguration)
+	assert.Equal(t, []string{"", `"flags-1"`, `"flags-1"`}, server.ifNoneMatch[CONFIG_ENDPOINT])
+	assert.Same(t, first, store.configuration.Load())
+}
+
+func Test_condition_malformedValuesNeverMatch(t *testing.T) {
+	attributes := Attributes{"attr": "a"}
+	for _, c := range []condition{
+		{Operator: "MATCHES", Attribute: "attr", Value: 1.0},
+		{Operator: "NOT_MATCHES", Attribute: "attr", Value: nil},
+		{Operator: "ONE_OF", Attribute: "attr", Value: "a"},
+		{Operator: "NOT_ONE_OF", Attribute: "attr", Value: []interface{}{1.0}},
+	} {
+		assert.NotPanics(t, func() {
+			assert.False(t, c.matches(attributes), "%v", c)
+		})
+	}
+}
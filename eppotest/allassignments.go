@@ -0,0 +1,112 @@
+package eppotest
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Eppo-exp/golang-sdk/v6/eppoclient"
+)
+
+// GetAllAssignments returns values of all flags for the subject, with
+// variation types inferred from value types (see SetValue). Each
+// assignment is logged unless eppoclient.WithoutAssignmentLogging is
+// passed.
+func (c *Client) GetAllAssignments(
+	ctx context.Context,
+	subjectKey string,
+	subjectAttributes eppoclient.Attributes,
+	opts ...eppoclient.GetAllAssignmentsOption,
+) (map[string]eppoclient.AssignmentResult, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	logging := !eppoclient.AssignmentLoggingDisabled(opts...)
+	results := make(map[string]eppoclient.AssignmentResult)
+	assign := func(flagKey string, value interface{}) {
+		if _, ok := results[flagKey]; ok {
+			return
+		}
+		result, ok := assignmentResult(value)
+		if !ok {
+			return
+		}
+		results[flagKey] = result
+		if logging {
+			c.logAssignment(flagKey, subjectKey, subjectAttributes, value)
+		}
+	}
+	for flagKey, values := range c.subjectValues {
+		if value, ok := values[subjectKey]; ok {
+			assign(flagKey, value)
+		}
+	}
+	for flagKey, value := range c.values {
+		assign(flagKey, value)
+	}
+
+	c.calls = append(c.calls, Call{
+		Method:            "GetAllAssignments",
+		Context:           ctx,
+		SubjectKey:        subjectKey,
+		SubjectAttributes: subjectAttributes,
+		Result:            results,
+	})
+
+	return results, nil
+}
+
+// GetPrecomputedConfiguration returns the payload set with
+// SetPrecomputedConfiguration, or an error if none is set.
+func (c *Client) GetPrecomputedConfiguration(
+	subjectKey string,
+	subjectAttributes eppoclient.Attributes,
+	banditActions map[string]map[string]eppoclient.ContextAttributes,
+) ([]byte, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var err error
+	payload := c.precomputedConfiguration
+	if payload == nil {
+		err = errors.New("eppotest: no precomputed configuration set")
+	}
+
+	c.calls = append(c.calls, Call{
+		Method:            "GetPrecomputedConfiguration",
+		Context:           context.Background(),
+		SubjectKey:        subjectKey,
+		SubjectAttributes: subjectAttributes,
+		Result:            payload,
+		Error:             err,
+	})
+
+	return payload, err
+}
+
+// assignmentResult converts a flag value into the result the real
+// client would return for a flag of the inferred type.
+func assignmentResult(value interface{}) (eppoclient.AssignmentResult, bool) {
+	result := eppoclient.AssignmentResult{
+		VariationKey:  formatValue(value),
+		AllocationKey: Allocation,
+	}
+	switch value.(type) {
+	case bool:
+		result.Value, result.VariationType = value, eppoclient.VariationTypeBoolean
+	case int, int32, int64:
+		result.Value, _ = toInteger(value)
+		result.VariationType = eppoclient.VariationTypeInteger
+	case float32, float64:
+		result.Value, _ = toNumeric(value)
+		result.VariationType = eppoclient.VariationTypeNumeric
+	case string:
+		result.Value, result.VariationType = value, eppoclient.VariationTypeString
+	default:
+		parsed, ok := toJSON(value)
+		if !ok {
+			return result, false
+		}
+		result.Value, result.VariationType = parsed, eppoclient.VariationTypeJSON
+	}
+	return result, true
+}
@@ -0,0 +1,62 @@
+package eppotest
+
+import "testing"
+
+// AssertEvaluated asserts that the flag has been evaluated at least
+// once.
+func (c *Client) AssertEvaluated(t testing.TB, flagKey string) bool {
+	t.Helper()
+	if len(c.CallsForFlag(flagKey)) == 0 {
+		t.Errorf("eppotest: expected flag %q to be evaluated, but it was not", flagKey)
+		return false
+	}
+	return true
+}
+
+// AssertEvaluatedForSubject asserts that the flag has been evaluated
+// for the given subject at least once.
+func (c *Client) AssertEvaluatedForSubject(t testing.TB, flagKey, subjectKey string) bool {
+	t.Helper()
+	for _, call := range c.CallsForFlag(flagKey) {
+		if call.SubjectKey == subjectKey {
+			return true
+		}
+	}
+	t.Errorf("eppotest: expected flag %q to be evaluated for subject %q, but it was not", flagKey, subjectKey)
+	return false
+}
+
+// AssertNotEvaluated asserts that the flag has not been evaluated.
+func (c *Client) AssertNotEvaluated(t testing.TB, flagKey string) bool {
+	t.Helper()
+	if n := len(c.CallsForFlag(flagKey)); n > 0 {
+		t.Errorf("eppotest: expected flag %q not to be evaluated, but it was evaluated %d time(s)", flagKey, n)
+		return false
+	}
+	return true
+}
+
+// AssertNumberOfEvaluations asserts that the flag has been evaluated
+// exactly `expected` times.
+func (c *Client) AssertNumberOfEvaluations(t testing.TB, flagKey string, expected int) bool {
+	t.Helper()
+	if n := len(c.CallsForFlag(flagKey)); n != expected {
+		t.Errorf("eppotest: expected flag %q to be evaluated %d time(s), but it was evaluated %d time(s)", flagKey, expected, n)
+		return false
+	}
+	return true
+}
+
+// AssertNoErrors asserts that no evaluation has returned an error,
+// e.g., because of a flag without a value or of a mismatching type.
+func (c *Client) AssertNoErrors(t testing.TB) bool {
+	t.Helper()
+	ok := true
+	for _, call := range c.Calls() {
+		if call.Error != nil {
+			t.Errorf("eppotest: %s(%q, %q) returned error: %v", call.Method, call.FlagKey, call.SubjectKey, call.Error)
+			ok = false
+		}
+	}
+	return ok
+}
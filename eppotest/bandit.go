@@ -0,0 +1,106 @@
+package eppotest
+
+import (
+	"context"
+	"time"
+
+	"github.com/Eppo-exp/golang-sdk/v6/eppoclient"
+)
+
+// Model version reported in bandit events logged by the fake.
+const ModelVersion = "eppotest"
+
+func (c *Client) GetBanditAction(
+	flagKey, subjectKey string,
+	subjectAttributes eppoclient.ContextAttributes,
+	actions map[string]eppoclient.ContextAttributes,
+	defaultVariation string,
+) eppoclient.BanditResult {
+	return c.getBanditAction(context.Background(), "GetBanditAction", flagKey, subjectKey, subjectAttributes, actions, defaultVariation)
+}
+
+func (c *Client) GetBanditActionContext(
+	ctx context.Context,
+	flagKey, subjectKey string,
+	subjectAttributes eppoclient.ContextAttributes,
+	actions map[string]eppoclient.ContextAttributes,
+	defaultVariation string,
+) eppoclient.BanditResult {
+	return c.getBanditAction(ctx, "GetBanditActionContext", flagKey, subjectKey, subjectAttributes, actions, defaultVariation)
+}
+
+func (c *Client) getBanditAction(
+	ctx context.Context,
+	method string,
+	flagKey, subjectKey string,
+	subjectAttributes eppoclient.ContextAttributes,
+	actions map[string]eppoclient.ContextAttributes,
+	defaultVariation string,
+) eppoclient.BanditResult {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	result := eppoclient.BanditResult{Variation: defaultVariation}
+	// Whether the variation comes from configuration (and is logged)
+	// rather than being the default.
+	assigned := false
+	bandit, ok := c.subjectBanditActions[flagKey][subjectKey]
+	if !ok {
+		bandit, ok = c.banditActions[flagKey]
+	}
+	if ok {
+		result.Variation = bandit.variation
+		assigned = true
+		if _, ok := actions[bandit.action]; ok && bandit.action != "" {
+			action := bandit.action
+			result.Action = &action
+		}
+	} else if value, ok := c.lookup(flagKey, subjectKey); ok {
+		if variation, ok := value.(string); ok {
+			result.Variation = variation
+			assigned = true
+		}
+	}
+
+	if assigned {
+		c.logAssignment(flagKey, subjectKey, genericAttributes(subjectAttributes), result.Variation)
+	}
+	if result.Action != nil {
+		c.banditEvents = append(c.banditEvents, eppoclient.BanditEvent{
+			FlagKey:                      flagKey,
+			BanditKey:                    result.Variation,
+			Subject:                      subjectKey,
+			Action:                       *result.Action,
+			ActionProbability:            1,
+			ModelVersion:                 ModelVersion,
+			Timestamp:                    time.Now().UTC().Format(time.RFC3339),
+			SubjectNumericAttributes:     subjectAttributes.Numeric,
+			SubjectCategoricalAttributes: subjectAttributes.Categorical,
+			ActionNumericAttributes:      actions[*result.Action].Numeric,
+			ActionCategoricalAttributes:  actions[*result.Action].Categorical,
+		})
+	}
+
+	c.calls = append(c.calls, Call{
+		Method:                  method,
+		Context:                 ctx,
+		FlagKey:                 flagKey,
+		SubjectKey:              subjectKey,
+		BanditSubjectAttributes: subjectAttributes,
+		BanditActions:           actions,
+		Result:                  result,
+	})
+
+	return result
+}
+
+func genericAttributes(attributes eppoclient.ContextAttributes) eppoclient.Attributes {
+	result := make(eppoclient.Attributes, len(attributes.Numeric)+len(attributes.Categorical))
+	for k, v := range attributes.Numeric {
+		result[k] = v
+	}
+	for k, v := range attributes.Categorical {
+		result[k] = v
+	}
+	return result
+}
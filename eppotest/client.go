@@ -0,0 +1,287 @@
+// Package eppotest provides an in-memory fake of eppoclient.Client for
+// unit testing code that depends on feature flags.
+//
+//	client := eppotest.NewClient()
+//	client.SetValue("new-checkout", true)
+//	client.SetSubjectValue("new-checkout", "alice", false)
+//
+//	runCodeUnderTest(client)
+//
+//	client.AssertEvaluatedForSubject(t, "new-checkout", "alice")
+//
+// The fake evaluates synchronously and does not make any network
+// requests, so tests do not need to wait for configuration to load.
+package eppotest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/Eppo-exp/golang-sdk/v6/eppoclient"
+)
+
+// Allocation key reported in assignment events logged by the fake.
+const Allocation = "eppotest"
+
+// Call is a recorded call of one of the Client methods.
+type Call struct {
+	// Name of the called method, e.g. "GetBoolAssignmentContext".
+	Method string
+	// Context passed to the method. context.Background() for
+	// methods not accepting a context.
+	Context    context.Context
+	FlagKey    string
+	SubjectKey string
+	// Subject attributes of assignment calls.
+	SubjectAttributes eppoclient.Attributes
+	// Subject attributes and actions of bandit calls.
+	BanditSubjectAttributes eppoclient.ContextAttributes
+	BanditActions           map[string]eppoclient.ContextAttributes
+	// Returned value (eppoclient.BanditResult for bandit calls).
+	// FlagKey is empty for GetAllAssignments and
+	// GetPrecomputedConfiguration calls, whose results are the
+	// returned map and payload respectively.
+	Result interface{}
+	// Returned error, if any.
+	Error error
+}
+
+type banditAction struct {
+	variation string
+	action    string
+}
+
+// Client is an in-memory fake implementing eppoclient.Client.
+//
+// Flag values are set with SetValue and SetSubjectValue, and bandit
+// results with SetBanditAction and SetSubjectBanditAction. Evaluating
+// a flag without a value returns the default value along with
+// eppoclient.ErrFlagConfigurationNotFound, like a flag missing from
+// configuration.
+//
+// All methods are safe for concurrent use.
+type Client struct {
+	mu sync.Mutex
+	// flag key -> value for all subjects
+	values map[string]interface{}
+	// flag key -> subject key -> value
+	subjectValues map[string]map[string]interface{}
+	// flag key -> bandit result for all subjects
+	banditActions map[string]banditAction
+	// flag key -> subject key -> bandit result
+	subjectBanditActions map[string]map[string]banditAction
+	// returned by GetPrecomputedConfiguration
+	precomputedConfiguration []byte
+
+	calls            []Call
+	assignmentEvents []eppoclient.AssignmentEvent
+	banditEvents     []eppoclient.BanditEvent
+}
+
+var _ eppoclient.Client = (*Client)(nil)
+
+func NewClient() *Client {
+	return &Client{
+		values:               map[string]interface{}{},
+		subjectValues:        map[string]map[string]interface{}{},
+		banditActions:        map[string]banditAction{},
+		subjectBanditActions: map[string]map[string]banditAction{},
+	}
+}
+
+// SetValue sets the value of the flag for all subjects. The value
+// must be of the type requested by the getter: bool, float64 (or any
+// integer type) for numeric flags, int64 (or int) for integer flags,
+// and string. JSON getters accept any value, including raw JSON as
+// []byte or json.RawMessage, and return it as if parsed from JSON
+// (e.g., numbers become float64).
+func (c *Client) SetValue(flagKey string, value interface{}) *Client {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.values[flagKey] = value
+	return c
+}
+
+// SetSubjectValue sets the value of the flag for the given subject,
+// taking precedence over SetValue. See SetValue for supported types.
+func (c *Client) SetSubjectValue(flagKey, subjectKey string, value interface{}) *Client {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.subjectValues[flagKey] == nil {
+		c.subjectValues[flagKey] = map[string]interface{}{}
+	}
+	c.subjectValues[flagKey][subjectKey] = value
+	return c
+}
+
+// SetBanditAction sets the variation and action returned by
+// GetBanditAction for all subjects. The action is only returned if
+// the caller passes it among the actions, as the real client only
+// selects from the given actions. Empty `action` means no action.
+//
+// If no bandit result is set for a flag, GetBanditAction returns the
+// flag value set with SetValue (or the default variation) without an
+// action.
+func (c *Client) SetBanditAction(flagKey, variation, action string) *Client {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.banditActions[flagKey] = banditAction{variation: variation, action: action}
+	return c
+}
+
+// SetSubjectBanditAction is the same as SetBanditAction but only
+// applies to the given subject.
+func (c *Client) SetSubjectBanditAction(flagKey, subjectKey, variation, action string) *Client {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.subjectBanditActions[flagKey] == nil {
+		c.subjectBanditActions[flagKey] = map[string]banditAction{}
+	}
+	c.subjectBanditActions[flagKey][subjectKey] = banditAction{variation: variation, action: action}
+	return c
+}
+
+// SetPrecomputedConfiguration sets the payload returned by
+// GetPrecomputedConfiguration for all subjects. The fake does not
+// build payloads itself.
+func (c *Client) SetPrecomputedConfiguration(payload []byte) *Client {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.precomputedConfiguration = payload
+	return c
+}
+
+// Calls returns all recorded calls in order.
+func (c *Client) Calls() []Call {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]Call(nil), c.calls...)
+}
+
+// CallsForFlag returns recorded calls evaluating the given flag.
+func (c *Client) CallsForFlag(flagKey string) []Call {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var calls []Call
+	for _, call := range c.calls {
+		if call.FlagKey == flagKey {
+			calls = append(calls, call)
+		}
+	}
+	return calls
+}
+
+// AssignmentEvents returns assignment events the real client would
+// have logged, i.e., one per successful assignment.
+func (c *Client) AssignmentEvents() []eppoclient.AssignmentEvent {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]eppoclient.AssignmentEvent(nil), c.assignmentEvents...)
+}
+
+// BanditEvents returns bandit events the real client would have
+// logged, i.e., one per returned bandit action.
+func (c *Client) BanditEvents() []eppoclient.BanditEvent {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]eppoclient.BanditEvent(nil), c.banditEvents...)
+}
+
+// Reset forgets recorded calls and events. Flag values are kept.
+func (c *Client) Reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.calls = nil
+	c.assignmentEvents = nil
+	c.banditEvents = nil
+}
+
+// lookup returns the value of the flag for the subject.
+func (c *Client) lookup(flagKey, subjectKey string) (interface{}, bool) {
+	if value, ok := c.subjectValues[flagKey][subjectKey]; ok {
+		return value, true
+	}
+	value, ok := c.values[flagKey]
+	return value, ok
+}
+
+// assign evaluates the flag, records the call, and returns the value
+// converted by `convert`. `typeName` is used in error messages.
+func (c *Client) assign(
+	ctx context.Context,
+	method string,
+	flagKey, subjectKey string,
+	subjectAttributes eppoclient.Attributes,
+	typeName string,
+	convert func(interface{}) (interface{}, bool),
+) (interface{}, eppoclient.EvaluationDetails, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	details := eppoclient.EvaluationDetails{
+		FlagKey:           flagKey,
+		SubjectKey:        subjectKey,
+		SubjectAttributes: subjectAttributes,
+	}
+
+	var result interface{}
+	var err error
+	if value, ok := c.lookup(flagKey, subjectKey); !ok {
+		err = eppoclient.ErrFlagConfigurationNotFound
+		details.Reason = eppoclient.EvaluationReasonFlagNotFound
+	} else if result, ok = convert(value); !ok {
+		err = fmt.Errorf("eppotest: value of flag %q is %T, not %s", flagKey, value, typeName)
+		details.Reason = eppoclient.EvaluationReasonTypeMismatch
+	} else {
+		details.Reason = eppoclient.EvaluationReasonMatch
+		details.AllocationKey = Allocation
+		c.logAssignment(flagKey, subjectKey, subjectAttributes, value)
+	}
+	details.Error = err
+
+	c.calls = append(c.calls, Call{
+		Method:            method,
+		Context:           ctx,
+		FlagKey:           flagKey,
+		SubjectKey:        subjectKey,
+		SubjectAttributes: subjectAttributes,
+		Result:            result,
+		Error:             err,
+	})
+
+	return result, details, err
+}
+
+func (c *Client) logAssignment(flagKey, subjectKey string, subjectAttributes eppoclient.Attributes, value interface{}) {
+	c.assignmentEvents = append(c.assignmentEvents, eppoclient.AssignmentEvent{
+		FeatureFlag:       flagKey,
+		Allocation:        Allocation,
+		Experiment:        flagKey + "-" + Allocation,
+		Variation:         formatValue(value),
+		Subject:           subjectKey,
+		SubjectAttributes: subjectAttributes,
+		Timestamp:         time.Now().UTC().Format(time.RFC3339),
+	})
+}
+
+func formatValue(value interface{}) string {
+	switch value := value.(type) {
+	case string:
+		return value
+	case []byte:
+		return string(value)
+	case json.RawMessage:
+		return string(value)
+	case bool, int, int64, float64:
+		return fmt.Sprint(value)
+	default:
+		b, err := json.Marshal(value)
+		if err != nil {
+			return fmt.Sprint(value)
+		}
+		return string(b)
+	}
+}
@@ -0,0 +1,260 @@
+package eppotest
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/Eppo-exp/golang-sdk/v6/eppoclient"
+	"github.com/stretchr/testify/assert"
+)
+
+// recordingT records assertion failures instead of failing the test.
+type recordingT struct {
+	testing.TB
+	errors []string
+}
+
+func (t *recordingT) Helper() {}
+
+func (t *recordingT) Errorf(format string, args ...interface{}) {
+	t.errors = append(t.errors, fmt.Sprintf(format, args...))
+}
+
+type ctxKey struct{}
+
+// checkout is an example of code depending on eppoclient.Client.
+func checkout(client eppoclient.Client, user string) string {
+	if enabled, _ := client.GetBoolAssignment("new-checkout", user, eppoclient.Attributes{}, false); enabled {
+		return "new"
+	}
+	return "old"
+}
+
+func Test_Client_values(t *testing.T) {
+	client := NewClient().
+		SetValue("new-checkout", true).
+		SetSubjectValue("new-checkout", "alice", false)
+
+	assert.Equal(t, "new", checkout(client, "bob"))
+	assert.Equal(t, "old", checkout(client, "alice"))
+
+	client.AssertEvaluatedForSubject(t, "new-checkout", "alice")
+	client.AssertNumberOfEvaluations(t, "new-checkout", 2)
+	client.AssertNoErrors(t)
+
+	events := client.AssignmentEvents()
+	assert.Len(t, events, 2)
+	assert.Equal(t, "new-checkout", events[0].FeatureFlag)
+	assert.Equal(t, "bob", events[0].Subject)
+	assert.Equal(t, "true", events[0].Variation)
+	assert.Equal(t, Allocation, events[0].Allocation)
+}
+
+func Test_Client_types(t *testing.T) {
+	client := NewClient().
+		SetValue("numeric", 1).
+		SetValue("integer", 2).
+		SetValue("string", "value").
+		SetValue("json", map[string]int{"a": 1}).
+		SetValue("raw-json", []byte(`{"b": 2}`))
+
+	numeric, err := client.GetNumericAssignment("numeric", "subject", nil, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, 1.0, numeric)
+
+	integer, err := client.GetIntegerAssignmentContext(context.Background(), "integer", "subject", nil, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), integer)
+
+	str, details, err := client.GetStringAssignmentDetails("string", "subject", nil, "default")
+	assert.NoError(t, err)
+	assert.Equal(t, "value", str)
+	assert.Equal(t, eppoclient.EvaluationReasonMatch, details.Reason)
+
+	jsonValue, err := client.GetJSONAssignment("json", "subject", nil, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"a": 1.0}, jsonValue)
+
+	jsonBytes, err := client.GetJSONBytesAssignment("raw-json", "subject", nil, nil)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"b": 2}`, string(jsonBytes))
+}
+
+func Test_Client_errors(t *testing.T) {
+	client := NewClient().SetValue("string", "value")
+
+	value, details, err := client.GetBoolAssignmentDetails("missing", "subject", nil, true)
+	assert.ErrorIs(t, err, eppoclient.ErrFlagConfigurationNotFound)
+	assert.True(t, value)
+	assert.Equal(t, eppoclient.EvaluationReasonFlagNotFound, details.Reason)
+
+	value, details, err = client.GetBoolAssignmentDetails("string", "subject", nil, true)
+	assert.Error(t, err)
+	assert.True(t, value)
+	assert.Equal(t, eppoclient.EvaluationReasonTypeMismatch, details.Reason)
+
+	assert.Empty(t, client.AssignmentEvents())
+
+	rt := &recordingT{TB: t}
+	assert.False(t, client.AssertNoErrors(rt))
+	assert.Len(t, rt.errors, 2)
+}
+
+func Test_Client_calls(t *testing.T) {
+	client := NewClient().SetValue("flag", "on")
+	ctx := context.WithValue(context.Background(), ctxKey{}, "value")
+
+	_, _ = client.GetStringAssignmentContext(ctx, "flag", "subject", eppoclient.Attributes{"country": "US"}, "off")
+
+	calls := client.Calls()
+	assert.Len(t, calls, 1)
+	assert.Equal(t, "GetStringAssignmentContext", calls[0].Method)
+	assert.Equal(t, "value", calls[0].Context.Value(ctxKey{}))
+	assert.Equal(t, eppoclient.Attributes{"country": "US"}, calls[0].SubjectAttributes)
+	assert.Equal(t, "on", calls[0].Result)
+
+	rt := &recordingT{TB: t}
+	assert.False(t, client.AssertNotEvaluated(rt, "flag"))
+	assert.False(t, client.AssertEvaluated(rt, "other-flag"))
+	assert.False(t, client.AssertEvaluatedForSubject(rt, "flag", "other-subject"))
+	assert.False(t, client.AssertNumberOfEvaluations(rt, "flag", 2))
+	assert.Len(t, rt.errors, 4)
+
+	client.Reset()
+	assert.Empty(t, client.Calls())
+	assert.Empty(t, client.AssignmentEvents())
+	client.AssertNotEvaluated(t, "flag")
+}
+
+func Test_Client_bandits(t *testing.T) {
+	client := NewClient().
+		SetBanditAction("bandit-flag", "bandit", "action-a").
+		SetSubjectBanditAction("bandit-flag", "alice", "control", "").
+		SetValue("plain-flag", "variation")
+	actions := map[string]eppoclient.ContextAttributes{
+		"action-a": {Numeric: map[string]float64{"price": 10}},
+	}
+
+	result := client.GetBanditAction("bandit-flag", "bob", eppoclient.ContextAttributes{}, actions, "default")
+	assert.Equal(t, "bandit", result.Variation)
+	assert.Equal(t, "action-a", *result.Action)
+
+	result = client.GetBanditAction("bandit-flag", "alice", eppoclient.ContextAttributes{}, actions, "default")
+	assert.Equal(t, "control", result.Variation)
+	assert.Nil(t, result.Action)
+
+	// No actions passed, no action returned.
+	result = client.GetBanditActionContext(context.Background(), "bandit-flag", "bob", eppoclient.ContextAttributes{}, nil, "default")
+	assert.Equal(t, "bandit", result.Variation)
+	assert.Nil(t, result.Action)
+
+	// The configured action is not among the given actions.
+	otherActions := map[string]eppoclient.ContextAttributes{"action-b": {}}
+	result = client.GetBanditAction("bandit-flag", "bob", eppoclient.ContextAttributes{}, otherActions, "default")
+	assert.Equal(t, "bandit", result.Variation)
+	assert.Nil(t, result.Action)
+
+	result = client.GetBanditAction("plain-flag", "bob", eppoclient.ContextAttributes{}, actions, "default")
+	assert.Equal(t, eppoclient.BanditResult{Variation: "variation"}, result)
+
+	result = client.GetBanditAction("missing-flag", "bob", eppoclient.ContextAttributes{}, actions, "default")
+	assert.Equal(t, eppoclient.BanditResult{Variation: "default"}, result)
+
+	banditEvents := client.BanditEvents()
+	assert.Len(t, banditEvents, 1)
+	assert.Equal(t, "action-a", banditEvents[0].Action)
+	assert.Equal(t, map[string]float64{"price": 10}, banditEvents[0].ActionNumericAttributes)
+	client.AssertNumberOfEvaluations(t, "bandit-flag", 4)
+	// Every configured flag is logged; the missing one is not.
+	assert.Len(t, client.AssignmentEvents(), 5)
+}
+
+func Test_Client_banditsLogDefaultVariation(t *testing.T) {
+	// The configured variation equals the default but still comes
+	// from configuration, so it's logged.
+	client := NewClient().
+		SetBanditAction("bandit-flag", "default", "").
+		SetValue("plain-flag", "default")
+
+	client.GetBanditAction("bandit-flag", "bob", eppoclient.ContextAttributes{}, nil, "default")
+	client.GetBanditAction("plain-flag", "bob", eppoclient.ContextAttributes{}, nil, "default")
+
+	events := client.AssignmentEvents()
+	if assert.Len(t, events, 2) {
+		assert.Equal(t, "bandit-flag", events[0].FeatureFlag)
+		assert.Equal(t, "default", events[0].Variation)
+		assert.Equal(t, "plain-flag", events[1].FeatureFlag)
+	}
+}
+
+func Test_Client_GetAllAssignments(t *testing.T) {
+	client := NewClient().
+		SetValue("bool-flag", true).
+		SetValue("int-flag", 3).
+		SetValue("json-flag", map[string]int{"a": 1}).
+		SetValue("string-flag", "all").
+		SetSubjectValue("string-flag", "alice", "alice-only")
+
+	results, err := client.GetAllAssignments(context.Background(), "alice", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]eppoclient.AssignmentResult{
+		"bool-flag":   {Value: true, VariationKey: "true", AllocationKey: Allocation, VariationType: eppoclient.VariationTypeBoolean},
+		"int-flag":    {Value: int64(3), VariationKey: "3", AllocationKey: Allocation, VariationType: eppoclient.VariationTypeInteger},
+		"json-flag":   {Value: map[string]interface{}{"a": float64(1)}, VariationKey: `{"a":1}`, AllocationKey: Allocation, VariationType: eppoclient.VariationTypeJSON},
+		"string-flag": {Value: "alice-only", VariationKey: "alice-only", AllocationKey: Allocation, VariationType: eppoclient.VariationTypeString},
+	}, results)
+	assert.Len(t, client.AssignmentEvents(), 4)
+
+	results, err = client.GetAllAssignments(context.Background(), "bob", nil, eppoclient.WithoutAssignmentLogging())
+	assert.NoError(t, err)
+	assert.Equal(t, "all", results["string-flag"].Value)
+	assert.Len(t, client.AssignmentEvents(), 4)
+
+	calls := client.Calls()
+	if assert.Len(t, calls, 2) {
+		assert.Equal(t, "GetAllAssignments", calls[1].Method)
+		assert.Equal(t, "bob", calls[1].SubjectKey)
+	}
+}
+
+func Test_Client_GetPrecomputedConfiguration(t *testing.T) {
+	client := NewClient()
+	_, err := client.GetPrecomputedConfiguration("alice", nil, nil)
+	assert.Error(t, err)
+
+	client.SetPrecomputedConfiguration([]byte(`{"version":1}`))
+	payload, err := client.GetPrecomputedConfiguration("alice", nil, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"version":1}`, string(payload))
+	assert.Len(t, client.Calls(), 2)
+}
+
+func Test_GetAssignment_fake(t *testing.T) {
+	type settings struct {
+		Limit int `json:"limit"`
+	}
+	client := NewClient().
+		SetValue("int-flag", 3).
+		SetValue("string-flag", "value").
+		SetValue("json-flag", map[string]int{"limit": 5})
+
+	i, err := eppoclient.GetAssignment(client, "int-flag", "alice", nil, int64(0))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), i)
+
+	s, err := eppoclient.GetAssignmentContext(context.Background(), client, "string-flag", "alice", nil, "default")
+	assert.NoError(t, err)
+	assert.Equal(t, "value", s)
+
+	v, err := eppoclient.GetAssignment(client, "json-flag", "alice", nil, settings{})
+	assert.NoError(t, err)
+	assert.Equal(t, settings{Limit: 5}, v)
+
+	_, err = eppoclient.GetAssignment(client, "string-flag", "alice", nil, settings{Limit: 1})
+	assert.ErrorIs(t, err, eppoclient.ErrVariationDecode)
+
+	v, err = eppoclient.GetAssignment(client, "missing-flag", "alice", nil, settings{Limit: 1})
+	assert.ErrorIs(t, err, eppoclient.ErrFlagConfigurationNotFound)
+	assert.Equal(t, settings{Limit: 1}, v)
+}
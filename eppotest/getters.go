@@ -0,0 +1,235 @@
+package eppotest
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/Eppo-exp/golang-sdk/v6/eppoclient"
+)
+
+func (c *Client) GetBoolAssignment(flagKey, subjectKey string, subjectAttributes eppoclient.Attributes, defaultValue bool) (bool, error) {
+	value, _, err := c.getBoolAssignment(context.Background(), "GetBoolAssignment", flagKey, subjectKey, subjectAttributes, defaultValue)
+	return value, err
+}
+
+func (c *Client) GetBoolAssignmentContext(ctx context.Context, flagKey, subjectKey string, subjectAttributes eppoclient.Attributes, defaultValue bool) (bool, error) {
+	value, _, err := c.getBoolAssignment(ctx, "GetBoolAssignmentContext", flagKey, subjectKey, subjectAttributes, defaultValue)
+	return value, err
+}
+
+func (c *Client) GetBoolAssignmentDetails(flagKey, subjectKey string, subjectAttributes eppoclient.Attributes, defaultValue bool) (bool, eppoclient.EvaluationDetails, error) {
+	return c.getBoolAssignment(context.Background(), "GetBoolAssignmentDetails", flagKey, subjectKey, subjectAttributes, defaultValue)
+}
+
+func (c *Client) GetBoolAssignmentDetailsContext(ctx context.Context, flagKey, subjectKey string, subjectAttributes eppoclient.Attributes, defaultValue bool) (bool, eppoclient.EvaluationDetails, error) {
+	return c.getBoolAssignment(ctx, "GetBoolAssignmentDetailsContext", flagKey, subjectKey, subjectAttributes, defaultValue)
+}
+
+func (c *Client) getBoolAssignment(ctx context.Context, method string, flagKey, subjectKey string, subjectAttributes eppoclient.Attributes, defaultValue bool) (bool, eppoclient.EvaluationDetails, error) {
+	value, details, err := c.assign(ctx, method, flagKey, subjectKey, subjectAttributes, "BOOLEAN", toBool)
+	if err != nil {
+		return defaultValue, details, err
+	}
+	result, _ := value.(bool)
+	return result, details, nil
+}
+
+func (c *Client) GetNumericAssignment(flagKey, subjectKey string, subjectAttributes eppoclient.Attributes, defaultValue float64) (float64, error) {
+	value, _, err := c.getNumericAssignment(context.Background(), "GetNumericAssignment", flagKey, subjectKey, subjectAttributes, defaultValue)
+	return value, err
+}
+
+func (c *Client) GetNumericAssignmentContext(ctx context.Context, flagKey, subjectKey string, subjectAttributes eppoclient.Attributes, defaultValue float64) (float64, error) {
+	value, _, err := c.getNumericAssignment(ctx, "GetNumericAssignmentContext", flagKey, subjectKey, subjectAttributes, defaultValue)
+	return value, err
+}
+
+func (c *Client) GetNumericAssignmentDetails(flagKey, subjectKey string, subjectAttributes eppoclient.Attributes, defaultValue float64) (float64, eppoclient.EvaluationDetails, error) {
+	return c.getNumericAssignment(context.Background(), "GetNumericAssignmentDetails", flagKey, subjectKey, subjectAttributes, defaultValue)
+}
+
+func (c *Client) GetNumericAssignmentDetailsContext(ctx context.Context, flagKey, subjectKey string, subjectAttributes eppoclient.Attributes, defaultValue float64) (float64, eppoclient.EvaluationDetails, error) {
+	return c.getNumericAssignment(ctx, "GetNumericAssignmentDetailsContext", flagKey, subjectKey, subjectAttributes, defaultValue)
+}
+
+func (c *Client) getNumericAssignment(ctx context.Context, method string, flagKey, subjectKey string, subjectAttributes eppoclient.Attributes, defaultValue float64) (float64, eppoclient.EvaluationDetails, error) {
+	value, details, err := c.assign(ctx, method, flagKey, subjectKey, subjectAttributes, "NUMERIC", toNumeric)
+	if err != nil {
+		return defaultValue, details, err
+	}
+	result, _ := value.(float64)
+	return result, details, nil
+}
+
+func (c *Client) GetIntegerAssignment(flagKey, subjectKey string, subjectAttributes eppoclient.Attributes, defaultValue int64) (int64, error) {
+	value, _, err := c.getIntegerAssignment(context.Background(), "GetIntegerAssignment", flagKey, subjectKey, subjectAttributes, defaultValue)
+	return value, err
+}
+
+func (c *Client) GetIntegerAssignmentContext(ctx context.Context, flagKey, subjectKey string, subjectAttributes eppoclient.Attributes, defaultValue int64) (int64, error) {
+	value, _, err := c.getIntegerAssignment(ctx, "GetIntegerAssignmentContext", flagKey, subjectKey, subjectAttributes, defaultValue)
+	return value, err
+}
+
+func (c *Client) GetIntegerAssignmentDetails(flagKey, subjectKey string, subjectAttributes eppoclient.Attributes, defaultValue int64) (int64, eppoclient.EvaluationDetails, error) {
+	return c.getIntegerAssignment(context.Background(), "GetIntegerAssignmentDetails", flagKey, subjectKey, subjectAttributes, defaultValue)
+}
+
+func (c *Client) GetIntegerAssignmentDetailsContext(ctx context.Context, flagKey, subjectKey string, subjectAttributes eppoclient.Attributes, defaultValue int64) (int64, eppoclient.EvaluationDetails, error) {
+	return c.getIntegerAssignment(ctx, "GetIntegerAssignmentDetailsContext", flagKey, subjectKey, subjectAttributes, defaultValue)
+}
+
+func (c *Client) getIntegerAssignment(ctx context.Context, method string, flagKey, subjectKey string, subjectAttributes eppoclient.Attributes, defaultValue int64) (int64, eppoclient.EvaluationDetails, error) {
+	value, details, err := c.assign(ctx, method, flagKey, subjectKey, subjectAttributes, "INTEGER", toInteger)
+	if err != nil {
+		return defaultValue, details, err
+	}
+	result, _ := value.(int64)
+	return result, details, nil
+}
+
+func (c *Client) GetStringAssignment(flagKey, subjectKey string, subjectAttributes eppoclient.Attributes, defaultValue string) (string, error) {
+	value, _, err := c.getStringAssignment(context.Background(), "GetStringAssignment", flagKey, subjectKey, subjectAttributes, defaultValue)
+	return value, err
+}
+
+func (c *Client) GetStringAssignmentContext(ctx context.Context, flagKey, subjectKey string, subjectAttributes eppoclient.Attributes, defaultValue string) (string, error) {
+	value, _, err := c.getStringAssignment(ctx, "GetStringAssignmentContext", flagKey, subjectKey, subjectAttributes, defaultValue)
+	return value, err
+}
+
+func (c *Client) GetStringAssignmentDetails(flagKey, subjectKey string, subjectAttributes eppoclient.Attributes, defaultValue string) (string, eppoclient.EvaluationDetails, error) {
+	return c.getStringAssignment(context.Background(), "GetStringAssignmentDetails", flagKey, subjectKey, subjectAttributes, defaultValue)
+}
+
+func (c *Client) GetStringAssignmentDetailsContext(ctx context.Context, flagKey, subjectKey string, subjectAttributes eppoclient.Attributes, defaultValue string) (string, eppoclient.EvaluationDetails, error) {
+	return c.getStringAssignment(ctx, "GetStringAssignmentDetailsContext", flagKey, subjectKey, subjectAttributes, defaultValue)
+}
+
+func (c *Client) getStringAssignment(ctx context.Context, method string, flagKey, subjectKey string, subjectAttributes eppoclient.Attributes, defaultValue string) (string, eppoclient.EvaluationDetails, error) {
+	value, details, err := c.assign(ctx, method, flagKey, subjectKey, subjectAttributes, "STRING", toString)
+	if err != nil {
+		return defaultValue, details, err
+	}
+	result, _ := value.(string)
+	return result, details, nil
+}
+
+func (c *Client) GetJSONAssignment(flagKey, subjectKey string, subjectAttributes eppoclient.Attributes, defaultValue any) (any, error) {
+	value, _, err := c.getJSONAssignment(context.Background(), "GetJSONAssignment", flagKey, subjectKey, subjectAttributes, defaultValue)
+	return value, err
+}
+
+func (c *Client) GetJSONAssignmentContext(ctx context.Context, flagKey, subjectKey string, subjectAttributes eppoclient.Attributes, defaultValue any) (any, error) {
+	value, _, err := c.getJSONAssignment(ctx, "GetJSONAssignmentContext", flagKey, subjectKey, subjectAttributes, defaultValue)
+	return value, err
+}
+
+func (c *Client) GetJSONAssignmentDetails(flagKey, subjectKey string, subjectAttributes eppoclient.Attributes, defaultValue any) (any, eppoclient.EvaluationDetails, error) {
+	return c.getJSONAssignment(context.Background(), "GetJSONAssignmentDetails", flagKey, subjectKey, subjectAttributes, defaultValue)
+}
+
+func (c *Client) GetJSONAssignmentDetailsContext(ctx context.Context, flagKey, subjectKey string, subjectAttributes eppoclient.Attributes, defaultValue any) (any, eppoclient.EvaluationDetails, error) {
+	return c.getJSONAssignment(ctx, "GetJSONAssignmentDetailsContext", flagKey, subjectKey, subjectAttributes, defaultValue)
+}
+
+func (c *Client) getJSONAssignment(ctx context.Context, method string, flagKey, subjectKey string, subjectAttributes eppoclient.Attributes, defaultValue any) (any, eppoclient.EvaluationDetails, error) {
+	value, details, err := c.assign(ctx, method, flagKey, subjectKey, subjectAttributes, "JSON", toJSON)
+	if err != nil {
+		return defaultValue, details, err
+	}
+	result, _ := value.(any)
+	return result, details, nil
+}
+
+func (c *Client) GetJSONBytesAssignment(flagKey, subjectKey string, subjectAttributes eppoclient.Attributes, defaultValue []byte) ([]byte, error) {
+	value, _, err := c.getJSONBytesAssignment(context.Background(), "GetJSONBytesAssignment", flagKey, subjectKey, subjectAttributes, defaultValue)
+	return value, err
+}
+
+func (c *Client) GetJSONBytesAssignmentContext(ctx context.Context, flagKey, subjectKey string, subjectAttributes eppoclient.Attributes, defaultValue []byte) ([]byte, error) {
+	value, _, err := c.getJSONBytesAssignment(ctx, "GetJSONBytesAssignmentContext", flagKey, subjectKey, subjectAttributes, defaultValue)
+	return value, err
+}
+
+func (c *Client) GetJSONBytesAssignmentDetails(flagKey, subjectKey string, subjectAttributes eppoclient.Attributes, defaultValue []byte) ([]byte, eppoclient.EvaluationDetails, error) {
+	return c.getJSONBytesAssignment(context.Background(), "GetJSONBytesAssignmentDetails", flagKey, subjectKey, subjectAttributes, defaultValue)
+}
+
+func (c *Client) GetJSONBytesAssignmentDetailsContext(ctx context.Context, flagKey, subjectKey string, subjectAttributes eppoclient.Attributes, defaultValue []byte) ([]byte, eppoclient.EvaluationDetails, error) {
+	return c.getJSONBytesAssignment(ctx, "GetJSONBytesAssignmentDetailsContext", flagKey, subjectKey, subjectAttributes, defaultValue)
+}
+
+func (c *Client) getJSONBytesAssignment(ctx context.Context, method string, flagKey, subjectKey string, subjectAttributes eppoclient.Attributes, defaultValue []byte) ([]byte, eppoclient.EvaluationDetails, error) {
+	value, details, err := c.assign(ctx, method, flagKey, subjectKey, subjectAttributes, "JSON", toJSONBytes)
+	if err != nil {
+		return defaultValue, details, err
+	}
+	result, _ := value.([]byte)
+	return result, details, nil
+}
+
+func toBool(value interface{}) (interface{}, bool) {
+	b, ok := value.(bool)
+	return b, ok
+}
+
+func toNumeric(value interface{}) (interface{}, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	}
+	return nil, false
+}
+
+func toInteger(value interface{}) (interface{}, bool) {
+	switch v := value.(type) {
+	case int64:
+		return v, true
+	case int:
+		return int64(v), true
+	case int32:
+		return int64(v), true
+	}
+	return nil, false
+}
+
+func toString(value interface{}) (interface{}, bool) {
+	s, ok := value.(string)
+	return s, ok
+}
+
+// toJSON returns the value as if parsed from JSON.
+func toJSON(value interface{}) (interface{}, bool) {
+	raw, ok := toJSONBytes(value)
+	if !ok {
+		return nil, false
+	}
+	var parsed interface{}
+	if err := json.Unmarshal(raw.([]byte), &parsed); err != nil {
+		return nil, false
+	}
+	return parsed, true
+}
+
+func toJSONBytes(value interface{}) (interface{}, bool) {
+	switch v := value.(type) {
+	case []byte:
+		return v, json.Valid(v)
+	case json.RawMessage:
+		return []byte(v), json.Valid(v)
+	}
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return nil, false
+	}
+	return raw, true
+}